@@ -0,0 +1,622 @@
+package graphs
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// nodeIDs returns the IDs of all nodes in the GraphDocument, including
+// relationship endpoints that are not listed in Nodes, in first-seen order.
+func (gd *GraphDocument) nodeIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, node := range gd.Nodes {
+		add(node.ID)
+	}
+	for _, rel := range gd.Relationships {
+		add(rel.Source.ID)
+		add(rel.Target.ID)
+	}
+	return ids
+}
+
+// PageRank computes the PageRank of every node over the directed relationships.
+// Parallel relationships between the same pair of nodes add weight to that edge,
+// and the rank of nodes without outgoing relationships is spread evenly over all nodes.
+func (gd *GraphDocument) PageRank(damping float64, iterations int) map[string]float64 {
+	ids := gd.nodeIDs()
+	ranks := make(map[string]float64, len(ids))
+	if len(ids) == 0 {
+		return ranks
+	}
+
+	count := float64(len(ids))
+	for _, id := range ids {
+		ranks[id] = 1 / count
+	}
+
+	// Edge weights keyed by source then target, plus total outgoing weight
+	weights := make(map[string]map[string]float64)
+	outWeight := make(map[string]float64)
+	for _, rel := range gd.Relationships {
+		if weights[rel.Source.ID] == nil {
+			weights[rel.Source.ID] = make(map[string]float64)
+		}
+		weights[rel.Source.ID][rel.Target.ID]++
+		outWeight[rel.Source.ID]++
+	}
+
+	for i := 0; i < iterations; i++ {
+		var dangling float64
+		for _, id := range ids {
+			if outWeight[id] == 0 {
+				dangling += ranks[id]
+			}
+		}
+
+		base := (1-damping)/count + damping*dangling/count
+		next := make(map[string]float64, len(ids))
+		for _, id := range ids {
+			next[id] = base
+		}
+		for source, targets := range weights {
+			share := damping * ranks[source] / outWeight[source]
+			for target, weight := range targets {
+				next[target] += share * weight
+			}
+		}
+		ranks = next
+	}
+
+	return ranks
+}
+
+// CommunityOption configures community detection.
+type CommunityOption func(*communityOptions)
+
+// communityOptions contains configuration for community detection
+type communityOptions struct {
+	seed int64
+}
+
+// WithSeed sets the seed used to order node updates during community detection.
+// The same seed always produces the same communities for the same graph.
+func WithSeed(seed int64) CommunityOption {
+	return func(opts *communityOptions) {
+		opts.seed = seed
+	}
+}
+
+// neighbors returns the undirected adjacency of the GraphDocument.
+// Parallel relationships are counted once per relationship.
+func (gd *GraphDocument) neighbors() map[string][]string {
+	adjacency := make(map[string][]string)
+	for _, rel := range gd.Relationships {
+		if rel.Source.ID == rel.Target.ID {
+			continue
+		}
+		adjacency[rel.Source.ID] = append(adjacency[rel.Source.ID], rel.Target.ID)
+		adjacency[rel.Target.ID] = append(adjacency[rel.Target.ID], rel.Source.ID)
+	}
+	return adjacency
+}
+
+// DetectCommunities groups nodes into communities using label propagation
+// over the relationships treated as undirected. Each node repeatedly adopts
+// the most common community among its neighbors until nothing changes or
+// maxIterations is reached. Community IDs are numbered from zero.
+func (gd *GraphDocument) DetectCommunities(maxIterations int, options ...CommunityOption) map[string]int {
+	opts := &communityOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	ids := gd.nodeIDs()
+	adjacency := gd.neighbors()
+
+	// Every node starts in its own community
+	labels := make(map[string]int, len(ids))
+	for i, id := range ids {
+		labels[id] = i
+	}
+
+	rng := rand.New(rand.NewSource(opts.seed))
+	order := make([]string, len(ids))
+	copy(order, ids)
+
+	for i := 0; i < maxIterations; i++ {
+		rng.Shuffle(len(order), func(a, b int) {
+			order[a], order[b] = order[b], order[a]
+		})
+
+		changed := false
+		for _, id := range order {
+			if len(adjacency[id]) == 0 {
+				continue
+			}
+
+			counts := make(map[int]int)
+			for _, neighbor := range adjacency[id] {
+				counts[labels[neighbor]]++
+			}
+
+			maxCount := 0
+			for _, count := range counts {
+				if count > maxCount {
+					maxCount = count
+				}
+			}
+
+			// Keep the current label on ties so the process settles,
+			// otherwise break ties randomly among the leading labels
+			best := labels[id]
+			if counts[best] != maxCount {
+				var candidates []int
+				for label, count := range counts {
+					if count == maxCount {
+						candidates = append(candidates, label)
+					}
+				}
+				sort.Ints(candidates)
+				best = candidates[rng.Intn(len(candidates))]
+			}
+
+			if best != labels[id] {
+				labels[id] = best
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	// Renumber communities densely in node order
+	renumber := make(map[int]int)
+	communities := make(map[string]int, len(ids))
+	for _, id := range ids {
+		label := labels[id]
+		if _, ok := renumber[label]; !ok {
+			renumber[label] = len(renumber)
+		}
+		communities[id] = renumber[label]
+	}
+
+	return communities
+}
+
+// neighborSets returns the distinct undirected neighbors of every node, ignoring self-loops
+func (gd *GraphDocument) neighborSets() map[string]map[string]bool {
+	sets := make(map[string]map[string]bool)
+	for _, rel := range gd.Relationships {
+		if rel.Source.ID == rel.Target.ID {
+			continue
+		}
+		if sets[rel.Source.ID] == nil {
+			sets[rel.Source.ID] = make(map[string]bool)
+		}
+		if sets[rel.Target.ID] == nil {
+			sets[rel.Target.ID] = make(map[string]bool)
+		}
+		sets[rel.Source.ID][rel.Target.ID] = true
+		sets[rel.Target.ID][rel.Source.ID] = true
+	}
+	return sets
+}
+
+// FindPathByRelTypes returns every path of nodes starting at startID that follows outgoing
+// relationships matching relTypePath in order. Each path holds len(relTypePath)+1 nodes,
+// starting with the start node. Nodes are taken from the node list when present and
+// from the relationship endpoints otherwise. Nil is returned when no path matches.
+func (gd *GraphDocument) FindPathByRelTypes(startID string, relTypePath []string) [][]Node {
+	start := gd.pathNode(startID, nil)
+	if start == nil {
+		return nil
+	}
+
+	outgoing := make(map[string][]Relationship)
+	for _, rel := range gd.Relationships {
+		outgoing[rel.Source.ID] = append(outgoing[rel.Source.ID], rel)
+	}
+
+	var paths [][]Node
+	var walk func(path []Node, depth int)
+	walk = func(path []Node, depth int) {
+		if depth == len(relTypePath) {
+			paths = append(paths, append([]Node(nil), path...))
+			return
+		}
+		for _, rel := range outgoing[path[len(path)-1].ID] {
+			if rel.Type == relTypePath[depth] {
+				target := rel.Target
+				walk(append(path, *gd.pathNode(target.ID, &target)), depth+1)
+			}
+		}
+	}
+	walk([]Node{*start}, 0)
+
+	return paths
+}
+
+// pathNode returns the listed node with the given ID, falling back to endpoint.
+// Without an endpoint, a node referenced only by relationships is built from its ID.
+func (gd *GraphDocument) pathNode(id string, endpoint *Node) *Node {
+	if node := gd.FindNode(id); node != nil {
+		return node
+	}
+	if endpoint != nil {
+		return endpoint
+	}
+	for _, rel := range gd.Relationships {
+		if rel.Source.ID == id {
+			return &rel.Source
+		}
+		if rel.Target.ID == id {
+			return &rel.Target
+		}
+	}
+	return nil
+}
+
+// ClusteringCoefficient returns the local clustering coefficient of a node,
+// the fraction of pairs of its neighbors that are themselves connected.
+// Relationships are treated as undirected. Nodes with fewer than two
+// neighbors have a coefficient of zero.
+func (gd *GraphDocument) ClusteringCoefficient(nodeID string) (float64, error) {
+	sets := gd.neighborSets()
+	if !gd.NodeExists(nodeID) && sets[nodeID] == nil {
+		return 0, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+
+	neighbors := make([]string, 0, len(sets[nodeID]))
+	for neighbor := range sets[nodeID] {
+		neighbors = append(neighbors, neighbor)
+	}
+
+	k := len(neighbors)
+	if k < 2 {
+		return 0, nil
+	}
+
+	links := 0
+	for i := 0; i < k; i++ {
+		for j := i + 1; j < k; j++ {
+			if sets[neighbors[i]][neighbors[j]] {
+				links++
+			}
+		}
+	}
+
+	return float64(2*links) / float64(k*(k-1)), nil
+}
+
+// GlobalClusteringCoefficient returns the transitivity of the graph, the ratio of
+// closed triplets to all connected triplets with relationships treated as undirected.
+func (gd *GraphDocument) GlobalClusteringCoefficient() float64 {
+	sets := gd.neighborSets()
+
+	var closed, triplets int
+	for _, neighbors := range sets {
+		list := make([]string, 0, len(neighbors))
+		for neighbor := range neighbors {
+			list = append(list, neighbor)
+		}
+
+		k := len(list)
+		triplets += k * (k - 1) / 2
+		for i := 0; i < k; i++ {
+			for j := i + 1; j < k; j++ {
+				if sets[list[i]][list[j]] {
+					closed++
+				}
+			}
+		}
+	}
+
+	if triplets == 0 {
+		return 0
+	}
+	return float64(closed) / float64(triplets)
+}
+
+// Sample returns a preview of the GraphDocument containing at most maxNodes nodes
+// and the relationships among them. Nodes are collected by a breadth-first search
+// from a random starting node, restarting from another random node whenever a
+// connected component is exhausted. The same seed always yields the same sample.
+func (gd *GraphDocument) Sample(maxNodes int, seed int64) *GraphDocument {
+	sample := NewGraphDocument(gd.Source)
+	if maxNodes <= 0 {
+		return &sample
+	}
+
+	// Resolve every ID to a node, falling back to relationship endpoints
+	nodesByID := make(map[string]Node)
+	for _, rel := range gd.Relationships {
+		nodesByID[rel.Source.ID] = rel.Source
+		nodesByID[rel.Target.ID] = rel.Target
+	}
+	for _, node := range gd.Nodes {
+		nodesByID[node.ID] = node
+	}
+
+	ids := gd.nodeIDs()
+	sets := gd.neighborSets()
+	rng := rand.New(rand.NewSource(seed))
+
+	selected := make(map[string]bool)
+	var order []string
+	for len(order) < maxNodes && len(order) < len(ids) {
+		// Pick a random unvisited start node
+		var remaining []string
+		for _, id := range ids {
+			if !selected[id] {
+				remaining = append(remaining, id)
+			}
+		}
+		start := remaining[rng.Intn(len(remaining))]
+
+		selected[start] = true
+		order = append(order, start)
+		queue := []string{start}
+		for len(queue) > 0 && len(order) < maxNodes {
+			current := queue[0]
+			queue = queue[1:]
+
+			neighbors := make([]string, 0, len(sets[current]))
+			for neighbor := range sets[current] {
+				neighbors = append(neighbors, neighbor)
+			}
+			sort.Strings(neighbors)
+			rng.Shuffle(len(neighbors), func(a, b int) {
+				neighbors[a], neighbors[b] = neighbors[b], neighbors[a]
+			})
+
+			for _, neighbor := range neighbors {
+				if len(order) >= maxNodes {
+					break
+				}
+				if !selected[neighbor] {
+					selected[neighbor] = true
+					order = append(order, neighbor)
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+
+	for _, id := range order {
+		node := nodesByID[id]
+		sample.AddNode(node.Clone())
+	}
+	for _, rel := range gd.Relationships {
+		if selected[rel.Source.ID] && selected[rel.Target.ID] {
+			sample.AddRelationship(rel.Clone())
+		}
+	}
+
+	return &sample
+}
+
+// StructuralReport summarizes the shape of a GraphDocument.
+type StructuralReport struct {
+	// NodeCount is the number of distinct nodes, including relationship endpoints
+	NodeCount int `json:"node_count"`
+	// RelationshipCount is the number of relationships
+	RelationshipCount int `json:"relationship_count"`
+	// Density is the ratio of relationships to possible directed relationships
+	Density float64 `json:"density"`
+	// ComponentCount is the number of connected components, ignoring direction
+	ComponentCount int `json:"component_count"`
+	// LargestComponentSize is the number of nodes in the largest connected component
+	LargestComponentSize int `json:"largest_component_size"`
+	// DegreeBuckets counts nodes by total degree in power-of-two buckets such as "0", "1", "2-3" and "4-7"
+	DegreeBuckets map[string]int `json:"degree_buckets"`
+}
+
+// StructuralReport computes density, connected components and the degree distribution
+func (gd *GraphDocument) StructuralReport() StructuralReport {
+	ids := gd.nodeIDs()
+	report := StructuralReport{
+		NodeCount:         len(ids),
+		RelationshipCount: len(gd.Relationships),
+		DegreeBuckets:     make(map[string]int),
+	}
+
+	if len(ids) > 1 {
+		report.Density = float64(len(gd.Relationships)) / float64(len(ids)*(len(ids)-1))
+	}
+
+	// Connected components by breadth-first search
+	sets := gd.neighborSets()
+	visited := make(map[string]bool)
+	for _, id := range ids {
+		if visited[id] {
+			continue
+		}
+		report.ComponentCount++
+
+		size := 0
+		visited[id] = true
+		queue := []string{id}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			size++
+			for neighbor := range sets[current] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		if size > report.LargestComponentSize {
+			report.LargestComponentSize = size
+		}
+	}
+
+	degrees := gd.degrees()
+	for _, id := range ids {
+		report.DegreeBuckets[degreeBucket(degrees[id])]++
+	}
+
+	return report
+}
+
+// degrees returns the total degree of every node, counting every relationship end
+func (gd *GraphDocument) degrees() map[string]int {
+	degrees := make(map[string]int)
+	for _, rel := range gd.Relationships {
+		degrees[rel.Source.ID]++
+		degrees[rel.Target.ID]++
+	}
+	return degrees
+}
+
+// DegreeHistogram maps each total degree to the number of nodes with that degree.
+// Relationships are treated as undirected, isolated nodes have degree zero, and
+// self-loops count twice towards their node.
+func (gd *GraphDocument) DegreeHistogram() map[int]int {
+	degrees := gd.degrees()
+	histogram := make(map[int]int)
+	for _, id := range gd.nodeIDs() {
+		histogram[degrees[id]]++
+	}
+	return histogram
+}
+
+// degreeBucket returns the power-of-two bucket label of a degree
+func degreeBucket(degree int) string {
+	if degree < 2 {
+		return fmt.Sprintf("%d", degree)
+	}
+	low := 2
+	for low*2 <= degree {
+		low *= 2
+	}
+	return fmt.Sprintf("%d-%d", low, low*2-1)
+}
+
+// edge is a relationship seen from one of its endpoints
+type edge struct {
+	rel      Relationship
+	neighbor Node
+}
+
+// edges returns the relationships of every node in the given direction, in document order
+func (gd *GraphDocument) edges(direction Direction) map[string][]edge {
+	adjacency := make(map[string][]edge)
+	for _, rel := range gd.Relationships {
+		if direction != DirectionIncoming {
+			adjacency[rel.Source.ID] = append(adjacency[rel.Source.ID], edge{rel: rel, neighbor: rel.Target})
+		}
+		if direction != DirectionOutgoing && rel.Source.ID != rel.Target.ID {
+			adjacency[rel.Target.ID] = append(adjacency[rel.Target.ID], edge{rel: rel, neighbor: rel.Source})
+		}
+	}
+	return adjacency
+}
+
+// Neighbors returns the distinct nodes connected to nodeID by a relationship in the
+// given direction, in relationship order. A node with a self-loop is its own neighbor.
+// Nodes are taken from the node list when present and from the relationship endpoints otherwise.
+func (gd *GraphDocument) Neighbors(nodeID string, direction Direction) []Node {
+	var neighbors []Node
+	seen := make(map[string]bool)
+	for _, e := range gd.edges(direction)[nodeID] {
+		if seen[e.neighbor.ID] {
+			continue
+		}
+		seen[e.neighbor.ID] = true
+		endpoint := e.neighbor
+		neighbors = append(neighbors, *gd.pathNode(endpoint.ID, &endpoint))
+	}
+	return neighbors
+}
+
+// BFS returns the nodes reachable from startID in breadth-first order, following
+// relationships in either direction, starting with the start node. Nodes further than
+// maxDepth relationships from the start are not visited; a negative maxDepth visits the
+// whole connected component. Each node is visited once, so cycles are safe. Nil is
+// returned when the start node does not exist.
+func (gd *GraphDocument) BFS(startID string, maxDepth int) []Node {
+	start := gd.pathNode(startID, nil)
+	if start == nil {
+		return nil
+	}
+
+	adjacency := gd.edges(DirectionBoth)
+	visited := map[string]bool{startID: true}
+	order := []Node{*start}
+	frontier := []string{startID}
+	for depth := 0; len(frontier) > 0 && (maxDepth < 0 || depth < maxDepth); depth++ {
+		var next []string
+		for _, id := range frontier {
+			for _, e := range adjacency[id] {
+				if visited[e.neighbor.ID] {
+					continue
+				}
+				visited[e.neighbor.ID] = true
+				endpoint := e.neighbor
+				order = append(order, *gd.pathNode(endpoint.ID, &endpoint))
+				next = append(next, endpoint.ID)
+			}
+		}
+		frontier = next
+	}
+	return order
+}
+
+// ShortestPath returns the relationships of a shortest path between sourceID and
+// targetID, following relationships in either direction, in order from the source.
+// The relationships keep their original direction. It reports false when the nodes
+// are not connected, and an empty path when they are the same node.
+func (gd *GraphDocument) ShortestPath(sourceID, targetID string) ([]Relationship, bool) {
+	if gd.pathNode(sourceID, nil) == nil || gd.pathNode(targetID, nil) == nil {
+		return nil, false
+	}
+	if sourceID == targetID {
+		return []Relationship{}, true
+	}
+
+	adjacency := gd.edges(DirectionBoth)
+	via := make(map[string]edge)
+	visited := map[string]bool{sourceID: true}
+	queue := []string{sourceID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, e := range adjacency[id] {
+			if visited[e.neighbor.ID] {
+				continue
+			}
+			visited[e.neighbor.ID] = true
+			via[e.neighbor.ID] = edge{rel: e.rel, neighbor: Node{ID: id}}
+			if e.neighbor.ID == targetID {
+				return pathTo(via, sourceID, targetID), true
+			}
+			queue = append(queue, e.neighbor.ID)
+		}
+	}
+	return nil, false
+}
+
+// pathTo walks via back from targetID to sourceID and returns the relationships in path order
+func pathTo(via map[string]edge, sourceID, targetID string) []Relationship {
+	var path []Relationship
+	for id := targetID; id != sourceID; id = via[id].neighbor.ID {
+		path = append(path, via[id].rel)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,435 @@
+package graphs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// newDirectedGraph builds a GraphDocument from source/target ID pairs
+func newDirectedGraph(edges ...[2]string) GraphDocument {
+	gd := NewGraphDocument(schema.Document{})
+	for _, edge := range edges {
+		source := NewNode(edge[0], "Node")
+		target := NewNode(edge[1], "Node")
+		if !gd.NodeExists(source.ID) {
+			gd.AddNode(source)
+		}
+		if !gd.NodeExists(target.ID) {
+			gd.AddNode(target)
+		}
+		gd.AddRelationship(NewRelationship(source, target, "LINKS"))
+	}
+	return gd
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-3 {
+		t.Errorf("%s: expected %.4f, got %.4f", name, want, got)
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	gd := newDirectedGraph(
+		[2]string{"a", "b"},
+		[2]string{"a", "c"},
+		[2]string{"b", "c"},
+		[2]string{"c", "a"},
+	)
+
+	ranks := gd.PageRank(0.85, 100)
+
+	// Stationary solution of the PageRank equations for this graph
+	assertClose(t, "a", ranks["a"], 0.3878)
+	assertClose(t, "b", ranks["b"], 0.2148)
+	assertClose(t, "c", ranks["c"], 0.3974)
+}
+
+func TestPageRankDanglingNode(t *testing.T) {
+	gd := newDirectedGraph([2]string{"a", "b"})
+
+	ranks := gd.PageRank(0.85, 100)
+
+	// b has no outgoing edges so its rank is redistributed evenly:
+	// a = 0.075 + 0.425b, b = 0.075 + 0.85a + 0.425b
+	assertClose(t, "a", ranks["a"], 0.3509)
+	assertClose(t, "b", ranks["b"], 0.6491)
+	assertClose(t, "sum", ranks["a"]+ranks["b"], 1)
+}
+
+func TestPageRankParallelEdges(t *testing.T) {
+	gd := newDirectedGraph(
+		[2]string{"a", "b"},
+		[2]string{"a", "b"},
+		[2]string{"a", "c"},
+		[2]string{"b", "a"},
+		[2]string{"c", "a"},
+	)
+
+	ranks := gd.PageRank(0.85, 100)
+
+	if ranks["b"] <= ranks["c"] {
+		t.Errorf("Expected doubled edge to give b a higher rank than c, got b=%.4f c=%.4f", ranks["b"], ranks["c"])
+	}
+}
+
+func TestPageRankEmpty(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	if ranks := gd.PageRank(0.85, 10); len(ranks) != 0 {
+		t.Errorf("Expected no ranks for an empty graph, got %v", ranks)
+	}
+}
+
+// newTwoClusterGraph builds two fully connected clusters joined by a single edge
+func newTwoClusterGraph() GraphDocument {
+	var edges [][2]string
+	clusters := [][]string{{"a1", "a2", "a3", "a4"}, {"b1", "b2", "b3", "b4"}}
+	for _, cluster := range clusters {
+		for i := range cluster {
+			for j := i + 1; j < len(cluster); j++ {
+				edges = append(edges, [2]string{cluster[i], cluster[j]})
+			}
+		}
+	}
+	edges = append(edges, [2]string{"a1", "b1"})
+	return newDirectedGraph(edges...)
+}
+
+func TestDetectCommunities(t *testing.T) {
+	gd := newTwoClusterGraph()
+
+	communities := gd.DetectCommunities(20, WithSeed(42))
+
+	if len(communities) != 8 {
+		t.Fatalf("Expected a community for all 8 nodes, got %d", len(communities))
+	}
+
+	distinct := make(map[int]bool)
+	for _, community := range communities {
+		distinct[community] = true
+	}
+	if len(distinct) > 2 {
+		t.Errorf("Expected at most 2 communities, got %d: %v", len(distinct), communities)
+	}
+
+	for _, cluster := range [][]string{{"a2", "a3", "a4"}, {"b2", "b3", "b4"}} {
+		for _, id := range cluster[1:] {
+			if communities[id] != communities[cluster[0]] {
+				t.Errorf("Expected %s and %s in the same community: %v", id, cluster[0], communities)
+			}
+		}
+	}
+	if communities["a2"] == communities["b2"] {
+		t.Errorf("Expected the two clusters to resolve into different communities: %v", communities)
+	}
+}
+
+func TestDetectCommunitiesDeterministic(t *testing.T) {
+	gd := newTwoClusterGraph()
+
+	first := gd.DetectCommunities(20, WithSeed(7))
+	for i := 0; i < 5; i++ {
+		again := gd.DetectCommunities(20, WithSeed(7))
+		for id, community := range first {
+			if again[id] != community {
+				t.Fatalf("Expected identical communities for the same seed, got %v and %v", first, again)
+			}
+		}
+	}
+}
+
+func TestClusteringCoefficientTriangle(t *testing.T) {
+	gd := newDirectedGraph(
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "a"},
+	)
+
+	for _, id := range []string{"a", "b", "c"} {
+		coefficient, err := gd.ClusteringCoefficient(id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertClose(t, id, coefficient, 1)
+	}
+	assertClose(t, "global", gd.GlobalClusteringCoefficient(), 1)
+}
+
+func TestClusteringCoefficientStar(t *testing.T) {
+	gd := newDirectedGraph(
+		[2]string{"hub", "a"},
+		[2]string{"hub", "b"},
+		[2]string{"hub", "c"},
+		[2]string{"d", "hub"},
+	)
+
+	for _, id := range []string{"hub", "a", "d"} {
+		coefficient, err := gd.ClusteringCoefficient(id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertClose(t, id, coefficient, 0)
+	}
+	assertClose(t, "global", gd.GlobalClusteringCoefficient(), 0)
+}
+
+func TestClusteringCoefficientUnknownNode(t *testing.T) {
+	gd := newDirectedGraph([2]string{"a", "b"})
+
+	if _, err := gd.ClusteringCoefficient("missing"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+}
+
+func TestSample(t *testing.T) {
+	gd := newTwoClusterGraph()
+
+	for _, maxNodes := range []int{0, 1, 3, 5, 8, 20} {
+		sample := gd.Sample(maxNodes, 1)
+
+		want := maxNodes
+		if want > 8 {
+			want = 8
+		}
+		if sample.GetNodeCount() != want {
+			t.Errorf("Expected %d sampled nodes for maxNodes=%d, got %d", want, maxNodes, sample.GetNodeCount())
+		}
+
+		for _, rel := range sample.Relationships {
+			if !sample.NodeExists(rel.Source.ID) || !sample.NodeExists(rel.Target.ID) {
+				t.Errorf("Relationship %s->%s references a node outside the sample", rel.Source.ID, rel.Target.ID)
+			}
+		}
+	}
+
+	if full := gd.Sample(8, 1); full.GetRelationshipCount() != gd.GetRelationshipCount() {
+		t.Errorf("Expected all relationships when sampling every node, got %d", full.GetRelationshipCount())
+	}
+}
+
+func TestSampleDeterministic(t *testing.T) {
+	gd := newTwoClusterGraph()
+
+	first := gd.Sample(4, 99)
+	second := gd.Sample(4, 99)
+	for i := range first.Nodes {
+		if first.Nodes[i].ID != second.Nodes[i].ID {
+			t.Fatalf("Expected identical samples for the same seed, got %v and %v", first.Nodes, second.Nodes)
+		}
+	}
+}
+
+func TestStructuralReport(t *testing.T) {
+	// A triangle, a separate pair and an isolated node
+	gd := newDirectedGraph(
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "a"},
+		[2]string{"a", "d"},
+		[2]string{"a", "e"},
+		[2]string{"x", "y"},
+	)
+	gd.AddNode(NewNode("lonely", "Node"))
+
+	report := gd.StructuralReport()
+
+	if report.NodeCount != 8 || report.RelationshipCount != 6 {
+		t.Errorf("Expected 8 nodes and 6 relationships, got %d and %d", report.NodeCount, report.RelationshipCount)
+	}
+	assertClose(t, "density", report.Density, 6.0/56.0)
+	if report.ComponentCount != 3 {
+		t.Errorf("Expected 3 components, got %d", report.ComponentCount)
+	}
+	if report.LargestComponentSize != 5 {
+		t.Errorf("Expected largest component of 5 nodes, got %d", report.LargestComponentSize)
+	}
+
+	// Degrees: a=4, b=2, c=2, d=1, e=1, x=1, y=1, lonely=0
+	expected := map[string]int{"0": 1, "1": 4, "2-3": 2, "4-7": 1}
+	for bucket, count := range expected {
+		if report.DegreeBuckets[bucket] != count {
+			t.Errorf("Expected %d nodes in bucket %s, got %d", count, bucket, report.DegreeBuckets[bucket])
+		}
+	}
+	if len(report.DegreeBuckets) != len(expected) {
+		t.Errorf("Unexpected buckets: %v", report.DegreeBuckets)
+	}
+}
+
+func TestFindPathByRelTypes(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	alice := NewNode("alice", "Person")
+	acme := NewNode("acme", "Company")
+	globex := NewNode("globex", "Company")
+	bob := NewNode("bob", "Person")
+	tech := NewNode("tech", "Industry")
+	for _, node := range []Node{alice, acme, globex, bob, tech} {
+		gd.AddNode(node)
+	}
+
+	gd.AddRelationship(NewRelationship(alice, acme, "WORKS_AT"))
+	gd.AddRelationship(NewRelationship(alice, globex, "WORKS_AT"))
+	gd.AddRelationship(NewRelationship(alice, bob, "KNOWS"))
+	gd.AddRelationship(NewRelationship(acme, tech, "IN_INDUSTRY"))
+	gd.AddRelationship(NewRelationship(globex, alice, "EMPLOYS"))
+	gd.AddRelationship(NewRelationship(bob, globex, "WORKS_AT"))
+	// The industry is only known through the relationship
+	gd.AddRelationship(NewRelationship(globex, NewNode("retail", "Industry"), "IN_INDUSTRY"))
+
+	paths := gd.FindPathByRelTypes("alice", []string{"WORKS_AT", "IN_INDUSTRY"})
+	var got []string
+	for _, path := range paths {
+		var ids []string
+		for _, node := range path {
+			ids = append(ids, node.ID)
+		}
+		got = append(got, strings.Join(ids, "->"))
+	}
+
+	expected := []string{"alice->acme->tech", "alice->globex->retail"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, got)
+	}
+	if paths[1][2].Type != "Industry" {
+		t.Errorf("Expected endpoint-only node to keep its type, got %q", paths[1][2].Type)
+	}
+
+	if paths := gd.FindPathByRelTypes("alice", []string{"KNOWS", "IN_INDUSTRY"}); paths != nil {
+		t.Errorf("Expected no path for an unmatched sequence, got %v", paths)
+	}
+	if paths := gd.FindPathByRelTypes("missing", []string{"WORKS_AT"}); paths != nil {
+		t.Errorf("Expected no path from a missing node, got %v", paths)
+	}
+	if paths := gd.FindPathByRelTypes("alice", nil); len(paths) != 1 || len(paths[0]) != 1 {
+		t.Errorf("Expected the start node alone for an empty sequence, got %v", paths)
+	}
+}
+
+func TestDegreeHistogram(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	hub := NewNode("hub", "Node")
+	gd.AddNode(hub)
+	for i := 0; i < 5; i++ {
+		leaf := NewNode(fmt.Sprintf("leaf%d", i), "Node")
+		gd.AddNode(leaf)
+		gd.AddRelationship(NewRelationship(hub, leaf, "LINKS"))
+	}
+	gd.AddNode(NewNode("isolated", "Node"))
+
+	expected := map[int]int{5: 1, 1: 5, 0: 1}
+	if got := gd.DegreeHistogram(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestDegreeHistogramEmpty(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	if got := gd.DegreeHistogram(); len(got) != 0 {
+		t.Errorf("Expected an empty histogram, got %v", got)
+	}
+}
+
+// nodeIDsOf returns the IDs of nodes in order
+func nodeIDsOf(nodes []Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+// newTraversalGraph builds a graph with the cycle a->b->c->a, the tail c->d->e
+// and the disconnected pair x->y
+func newTraversalGraph() GraphDocument {
+	return newDirectedGraph(
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "a"},
+		[2]string{"c", "d"},
+		[2]string{"d", "e"},
+		[2]string{"x", "y"},
+	)
+}
+
+func TestNeighbors(t *testing.T) {
+	gd := newTraversalGraph()
+
+	tests := []struct {
+		direction Direction
+		want      []string
+	}{
+		{DirectionOutgoing, []string{"a", "d"}},
+		{DirectionIncoming, []string{"b"}},
+		{DirectionBoth, []string{"b", "a", "d"}},
+	}
+	for _, tt := range tests {
+		if got := nodeIDsOf(gd.Neighbors("c", tt.direction)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Direction %v: expected %v, got %v", tt.direction, tt.want, got)
+		}
+	}
+
+	if got := gd.Neighbors("e", DirectionOutgoing); len(got) != 0 {
+		t.Errorf("Expected no outgoing neighbors for a sink, got %v", nodeIDsOf(got))
+	}
+}
+
+func TestBFS(t *testing.T) {
+	gd := newTraversalGraph()
+
+	tests := []struct {
+		maxDepth int
+		want     []string
+	}{
+		{0, []string{"a"}},
+		{1, []string{"a", "b", "c"}},
+		{2, []string{"a", "b", "c", "d"}},
+		{-1, []string{"a", "b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		if got := nodeIDsOf(gd.BFS("a", tt.maxDepth)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Depth %d: expected %v, got %v", tt.maxDepth, tt.want, got)
+		}
+	}
+
+	if got := gd.BFS("missing", -1); got != nil {
+		t.Errorf("Expected nil for a missing start node, got %v", nodeIDsOf(got))
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	gd := newTraversalGraph()
+
+	path, ok := gd.ShortestPath("b", "e")
+	if !ok {
+		t.Fatal("Expected b and e to be connected")
+	}
+	var hops []string
+	for _, rel := range path {
+		hops = append(hops, rel.Source.ID+"->"+rel.Target.ID)
+	}
+	if want := []string{"b->c", "c->d", "d->e"}; !reflect.DeepEqual(hops, want) {
+		t.Errorf("Expected %v, got %v", want, hops)
+	}
+
+	// Relationships may be followed against their direction
+	path, ok = gd.ShortestPath("a", "c")
+	if !ok || len(path) != 1 || path[0].Source.ID != "c" || path[0].Target.ID != "a" {
+		t.Errorf("Expected the single relationship c->a, got %v", path)
+	}
+
+	if path, ok := gd.ShortestPath("a", "a"); !ok || len(path) != 0 {
+		t.Errorf("Expected an empty path to the start node, got %v", path)
+	}
+	if _, ok := gd.ShortestPath("a", "y"); ok {
+		t.Error("Expected no path between disconnected components")
+	}
+	if _, ok := gd.ShortestPath("a", "missing"); ok {
+		t.Error("Expected no path to a missing node")
+	}
+}
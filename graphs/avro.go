@@ -0,0 +1,209 @@
+package graphs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// avroSchema is the Avro schema of an encoded GraphDocument. Nodes and relationships
+// are written as a single array of a union of the two record types, and property
+// values are a union of the Avro primitive types.
+const avroSchema = `{
+  "type": "record",
+  "name": "GraphDocument",
+  "namespace": "langchaingo.graphs",
+  "fields": [
+    {
+      "name": "elements",
+      "type": {
+        "type": "array",
+        "items": [
+          {
+            "type": "record",
+            "name": "Node",
+            "fields": [
+              {"name": "id", "type": "string"},
+              {"name": "type", "type": "string"},
+              {"name": "properties", "type": {"type": "map", "values": ["null", "boolean", "long", "double", "string"]}}
+            ]
+          },
+          {
+            "type": "record",
+            "name": "Relationship",
+            "fields": [
+              {"name": "source", "type": "string"},
+              {"name": "target", "type": "string"},
+              {"name": "type", "type": "string"},
+              {"name": "properties", "type": {"type": "map", "values": ["null", "boolean", "long", "double", "string"]}}
+            ]
+          }
+        ]
+      }
+    }
+  ]
+}`
+
+// Branch indexes of the Avro unions in avroSchema
+const (
+	avroNodeBranch = iota
+	avroRelationshipBranch
+)
+
+const (
+	avroNullBranch = iota
+	avroBooleanBranch
+	avroLongBranch
+	avroDoubleBranch
+	avroStringBranch
+)
+
+// ToAvro encodes the GraphDocument in the Avro binary encoding and returns it
+// together with the Avro schema it was written with. Property values that are
+// not Avro primitives, such as lists and maps, are encoded as JSON strings.
+func (gd *GraphDocument) ToAvro() (data []byte, schemaJSON string, err error) {
+	var buf bytes.Buffer
+
+	count := len(gd.Nodes) + len(gd.Relationships)
+	if count > 0 {
+		writeAvroLong(&buf, int64(count))
+
+		for _, node := range gd.Nodes {
+			writeAvroLong(&buf, avroNodeBranch)
+			writeAvroString(&buf, node.ID)
+			writeAvroString(&buf, node.Type)
+			if err := writeAvroProperties(&buf, node.Properties); err != nil {
+				return nil, "", fmt.Errorf("node %s: %w", node.ID, err)
+			}
+		}
+
+		for _, rel := range gd.Relationships {
+			writeAvroLong(&buf, avroRelationshipBranch)
+			writeAvroString(&buf, rel.Source.ID)
+			writeAvroString(&buf, rel.Target.ID)
+			writeAvroString(&buf, rel.Type)
+			if err := writeAvroProperties(&buf, rel.Properties); err != nil {
+				return nil, "", fmt.Errorf("relationship %s-%s->%s: %w", rel.Source.ID, rel.Type, rel.Target.ID, err)
+			}
+		}
+	}
+
+	// End of array blocks
+	writeAvroLong(&buf, 0)
+
+	return buf.Bytes(), avroSchema, nil
+}
+
+// writeAvroLong writes a zig-zag encoded variable-length long
+func writeAvroLong(buf *bytes.Buffer, value int64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(scratch[:], value)
+	buf.Write(scratch[:n])
+}
+
+// writeAvroString writes a length-prefixed UTF-8 string
+func writeAvroString(buf *bytes.Buffer, value string) {
+	writeAvroLong(buf, int64(len(value)))
+	buf.WriteString(value)
+}
+
+// writeAvroProperties writes a property map with keys in sorted order
+func writeAvroProperties(buf *bytes.Buffer, properties map[string]interface{}) error {
+	if len(properties) > 0 {
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		writeAvroLong(buf, int64(len(keys)))
+		for _, key := range keys {
+			writeAvroString(buf, key)
+			if err := writeAvroValue(buf, properties[key]); err != nil {
+				return fmt.Errorf("property %s: %w", key, err)
+			}
+		}
+	}
+
+	// End of map blocks
+	writeAvroLong(buf, 0)
+	return nil
+}
+
+// writeAvroValue writes a property value as a branch of the property value union
+func writeAvroValue(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		writeAvroLong(buf, avroNullBranch)
+	case bool:
+		writeAvroLong(buf, avroBooleanBranch)
+		if v {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case int:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case int8:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case int16:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case int32:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case int64:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, v)
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return fmt.Errorf("value %d overflows an Avro long", v)
+		}
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case uint8:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case uint16:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case uint32:
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case uint64:
+		if v > math.MaxInt64 {
+			return fmt.Errorf("value %d overflows an Avro long", v)
+		}
+		writeAvroLong(buf, avroLongBranch)
+		writeAvroLong(buf, int64(v))
+	case float32:
+		writeAvroLong(buf, avroDoubleBranch)
+		writeAvroDouble(buf, float64(v))
+	case float64:
+		writeAvroLong(buf, avroDoubleBranch)
+		writeAvroDouble(buf, v)
+	case string:
+		writeAvroLong(buf, avroStringBranch)
+		writeAvroString(buf, v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		writeAvroLong(buf, avroStringBranch)
+		writeAvroString(buf, string(encoded))
+	}
+	return nil
+}
+
+// writeAvroDouble writes a little-endian IEEE 754 double
+func writeAvroDouble(buf *bytes.Buffer, value float64) {
+	var scratch [8]byte
+	binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(value))
+	buf.Write(scratch[:])
+}
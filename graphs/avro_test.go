@@ -0,0 +1,323 @@
+package graphs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// avroReader decodes the subset of the Avro binary encoding used by ToAvro
+type avroReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (ar *avroReader) long() int64 {
+	v, err := binary.ReadVarint(ar.r)
+	if err != nil && ar.err == nil {
+		ar.err = err
+	}
+	return v
+}
+
+func (ar *avroReader) str() string {
+	b := make([]byte, ar.long())
+	if _, err := io.ReadFull(ar.r, b); err != nil && ar.err == nil {
+		ar.err = err
+	}
+	return string(b)
+}
+
+func (ar *avroReader) value() interface{} {
+	switch ar.long() {
+	case avroNullBranch:
+		return nil
+	case avroBooleanBranch:
+		b, _ := ar.r.ReadByte()
+		return b == 1
+	case avroLongBranch:
+		return ar.long()
+	case avroDoubleBranch:
+		var b [8]byte
+		io.ReadFull(ar.r, b[:])
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+	default:
+		return ar.str()
+	}
+}
+
+func (ar *avroReader) properties() map[string]interface{} {
+	props := make(map[string]interface{})
+	for count := ar.long(); count != 0 && ar.err == nil; count = ar.long() {
+		for i := int64(0); i < count; i++ {
+			key := ar.str()
+			props[key] = ar.value()
+		}
+	}
+	return props
+}
+
+func TestToAvro(t *testing.T) {
+	gd := newTestGraphDocument()
+	alice := gd.FindNode("alice")
+	alice.SetProperty("age", 42)
+	alice.SetProperty("score", 0.5)
+	alice.SetProperty("active", true)
+	alice.SetProperty("tags", []string{"a", "b"})
+
+	data, schemaJSON, err := gd.ToAvro()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &parsed); err != nil {
+		t.Fatalf("Expected schema to be valid JSON: %v", err)
+	}
+	if parsed["name"] != "GraphDocument" {
+		t.Errorf("Unexpected schema name %v", parsed["name"])
+	}
+
+	ar := &avroReader{r: bytes.NewReader(data)}
+	var nodes, relationships int
+	var decodedAlice map[string]interface{}
+	for count := ar.long(); count != 0 && ar.err == nil; count = ar.long() {
+		for i := int64(0); i < count; i++ {
+			switch ar.long() {
+			case avroNodeBranch:
+				id := ar.str()
+				ar.str()
+				props := ar.properties()
+				if id == "alice" {
+					decodedAlice = props
+				}
+				nodes++
+			case avroRelationshipBranch:
+				ar.str()
+				ar.str()
+				ar.str()
+				ar.properties()
+				relationships++
+			}
+		}
+	}
+	if ar.err != nil {
+		t.Fatalf("Failed to decode output: %v", ar.err)
+	}
+	if ar.r.Len() != 0 {
+		t.Errorf("Expected all bytes to be consumed, %d left", ar.r.Len())
+	}
+
+	if nodes != 3 || relationships != 3 {
+		t.Errorf("Expected 3 nodes and 3 relationships, got %d and %d", nodes, relationships)
+	}
+
+	expected := map[string]interface{}{
+		"name":   "Alice",
+		"age":    int64(42),
+		"score":  0.5,
+		"active": true,
+		"tags":   `["a","b"]`,
+	}
+	for key, want := range expected {
+		if decodedAlice[key] != want {
+			t.Errorf("Expected %s to decode as %v, got %v", key, want, decodedAlice[key])
+		}
+	}
+}
+
+func TestToAvroEmpty(t *testing.T) {
+	gd := GraphDocument{}
+
+	data, _, err := gd.ToAvro()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, []byte{0}) {
+		t.Errorf("Expected a single empty array block, got %v", data)
+	}
+}
+
+// avroSchemaDecoder decodes Avro binary data by walking a parsed schema rather than the
+// layout ToAvro writes, so mistakes in either the schema or the encoder show up as
+// decoding errors. It supports the primitive, record, array, map and union types.
+type avroSchemaDecoder struct {
+	reader *avroReader
+}
+
+func (d *avroSchemaDecoder) decode(schema interface{}) (interface{}, error) {
+	switch s := schema.(type) {
+	case string:
+		switch s {
+		case "null":
+			return nil, nil
+		case "boolean":
+			b, err := d.reader.r.ReadByte()
+			if err != nil || b > 1 {
+				return nil, fmt.Errorf("invalid boolean %d: %v", b, err)
+			}
+			return b == 1, nil
+		case "long":
+			return d.reader.long(), d.reader.err
+		case "double":
+			var b [8]byte
+			if _, err := io.ReadFull(d.reader.r, b[:]); err != nil {
+				return nil, err
+			}
+			return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+		case "string":
+			return d.reader.str(), d.reader.err
+		}
+		return nil, fmt.Errorf("unsupported type %s", s)
+	case []interface{}:
+		branch := d.reader.long()
+		if d.reader.err != nil || branch < 0 || branch >= int64(len(s)) {
+			return nil, fmt.Errorf("invalid union branch %d: %v", branch, d.reader.err)
+		}
+		return d.decode(s[branch])
+	case map[string]interface{}:
+		switch s["type"] {
+		case "record":
+			record := map[string]interface{}{"__record": s["name"]}
+			for _, field := range s["fields"].([]interface{}) {
+				field := field.(map[string]interface{})
+				value, err := d.decode(field["type"])
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", s["name"], field["name"], err)
+				}
+				record[field["name"].(string)] = value
+			}
+			return record, nil
+		case "array":
+			var items []interface{}
+			err := d.blocks(func() error {
+				item, err := d.decode(s["items"])
+				items = append(items, item)
+				return err
+			})
+			return items, err
+		case "map":
+			values := make(map[string]interface{})
+			err := d.blocks(func() error {
+				key := d.reader.str()
+				value, err := d.decode(s["values"])
+				values[key] = value
+				return err
+			})
+			return values, err
+		}
+		return d.decode(s["type"])
+	}
+	return nil, fmt.Errorf("unsupported schema %v", schema)
+}
+
+// blocks decodes the blocks of an array or map, calling item for every item
+func (d *avroSchemaDecoder) blocks(item func() error) error {
+	for {
+		count := d.reader.long()
+		if d.reader.err != nil {
+			return d.reader.err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			// A negative count is followed by the block size in bytes
+			count = -count
+			d.reader.long()
+		}
+		for i := int64(0); i < count; i++ {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func TestToAvroDecodesWithSchema(t *testing.T) {
+	gd := newTestGraphDocument()
+	alice := gd.FindNode("alice")
+	alice.SetProperty("age", uint64(42))
+	alice.SetProperty("score", float32(0.5))
+	alice.SetProperty("active", false)
+	alice.SetProperty("nothing", nil)
+
+	data, schemaJSON, err := gd.ToAvro()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var schema interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		t.Fatalf("Expected schema to be valid JSON: %v", err)
+	}
+
+	decoder := &avroSchemaDecoder{reader: &avroReader{r: bytes.NewReader(data)}}
+	decoded, err := decoder.decode(schema)
+	if err != nil {
+		t.Fatalf("Failed to decode with the schema: %v", err)
+	}
+	if decoder.reader.r.Len() != 0 {
+		t.Errorf("Expected all bytes to be consumed, %d left", decoder.reader.r.Len())
+	}
+
+	elements := decoded.(map[string]interface{})["elements"].([]interface{})
+	if len(elements) != len(gd.Nodes)+len(gd.Relationships) {
+		t.Fatalf("Expected %d elements, got %d", len(gd.Nodes)+len(gd.Relationships), len(elements))
+	}
+	first := elements[0].(map[string]interface{})
+	want := map[string]interface{}{
+		"__record": "Node",
+		"id":       "alice",
+		"type":     alice.Type,
+		"properties": map[string]interface{}{
+			"name":    "Alice",
+			"age":     int64(42),
+			"score":   0.5,
+			"active":  false,
+			"nothing": nil,
+		},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("Expected %v, got %v", want, first)
+	}
+	if last := elements[len(elements)-1].(map[string]interface{}); last["__record"] != "Relationship" {
+		t.Errorf("Expected relationships after nodes, got %v", last)
+	}
+}
+
+func TestToAvroUnsignedIntegers(t *testing.T) {
+	for _, value := range []interface{}{uint(7), uint64(7)} {
+		gd := GraphDocument{}
+		node := NewNode("n", "Thing")
+		node.SetProperty("count", value)
+		gd.AddNode(node)
+
+		data, _, err := gd.ToAvro()
+		if err != nil {
+			t.Fatalf("Unexpected error for %T: %v", value, err)
+		}
+		ar := &avroReader{r: bytes.NewReader(data)}
+		ar.long()
+		ar.long()
+		ar.str()
+		ar.str()
+		if props := ar.properties(); props["count"] != int64(7) {
+			t.Errorf("Expected %T to be written as a long, got %v", value, props["count"])
+		}
+	}
+
+	for _, value := range []interface{}{uint64(math.MaxInt64) + 1, uint(math.MaxUint64)} {
+		gd := GraphDocument{}
+		node := NewNode("n", "Thing")
+		node.SetProperty("count", value)
+		gd.AddNode(node)
+		if _, _, err := gd.ToAvro(); err == nil {
+			t.Errorf("Expected an error for %T value %v above MaxInt64", value, value)
+		}
+	}
+}
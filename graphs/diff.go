@@ -0,0 +1,125 @@
+package graphs
+
+import "reflect"
+
+// PropertyDiff compares the properties of n, the old version of a node, with other,
+// the new version. added holds the properties only on other, removed the properties
+// only on n with their old values, and changed the properties on both whose values
+// differ, with their new values. Maps are never nil.
+func (n Node) PropertyDiff(other Node) (added, removed, changed map[string]interface{}) {
+	return diffProperties(n.Properties, other.Properties)
+}
+
+// PropertyDiff compares the properties of r, the old version of a relationship, with
+// other, the new version, with the semantics of Node.PropertyDiff.
+func (r Relationship) PropertyDiff(other Relationship) (added, removed, changed map[string]interface{}) {
+	return diffProperties(r.Properties, other.Properties)
+}
+
+// diffProperties splits the differences between two property maps into added, removed
+// and changed properties
+func diffProperties(old, updated map[string]interface{}) (added, removed, changed map[string]interface{}) {
+	added = make(map[string]interface{})
+	removed = make(map[string]interface{})
+	changed = make(map[string]interface{})
+
+	for key, value := range updated {
+		previous, exists := old[key]
+		switch {
+		case !exists:
+			added[key] = value
+		case !reflect.DeepEqual(previous, value):
+			changed[key] = value
+		}
+	}
+	for key, value := range old {
+		if _, exists := updated[key]; !exists {
+			removed[key] = value
+		}
+	}
+
+	return added, removed, changed
+}
+
+// GraphDiff holds the changes turning one graph document into another. Nodes are
+// matched by ID and relationships by identifier; modified entities exist in both
+// documents with different properties and are held in their new version.
+type GraphDiff struct {
+	AddedNodes            []Node
+	RemovedNodes          []Node
+	ModifiedNodes         []Node
+	AddedRelationships    []Relationship
+	RemovedRelationships  []Relationship
+	ModifiedRelationships []Relationship
+}
+
+// IsEmpty reports whether the diff holds no changes
+func (d GraphDiff) IsEmpty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ModifiedNodes) == 0 &&
+		len(d.AddedRelationships) == 0 && len(d.RemovedRelationships) == 0 && len(d.ModifiedRelationships) == 0
+}
+
+// Diff compares gd, the old version of a graph, with other, the new version. Properties
+// are compared deeply, and a nil property map equals an empty one. Added and modified
+// entities follow the order of other, removed ones the order of gd. When an ID or
+// identifier repeats within a document, its first occurrence is used.
+func (gd *GraphDocument) Diff(other *GraphDocument) GraphDiff {
+	var diff GraphDiff
+
+	oldNodes := make(map[string]Node, len(gd.Nodes))
+	for _, node := range gd.Nodes {
+		if _, ok := oldNodes[node.ID]; !ok {
+			oldNodes[node.ID] = node
+		}
+	}
+	newNodes := make(map[string]bool, len(other.Nodes))
+	for _, node := range other.Nodes {
+		if newNodes[node.ID] {
+			continue
+		}
+		newNodes[node.ID] = true
+		old, ok := oldNodes[node.ID]
+		switch {
+		case !ok:
+			diff.AddedNodes = append(diff.AddedNodes, node)
+		case !sameProperties(old.Properties, node.Properties):
+			diff.ModifiedNodes = append(diff.ModifiedNodes, node)
+		}
+	}
+	for _, node := range gd.Nodes {
+		if !newNodes[node.ID] {
+			newNodes[node.ID] = true
+			diff.RemovedNodes = append(diff.RemovedNodes, node)
+		}
+	}
+
+	oldRels := make(map[RelationshipIdentifier]Relationship, len(gd.Relationships))
+	for _, rel := range gd.Relationships {
+		if _, ok := oldRels[rel.GetIdentifier()]; !ok {
+			oldRels[rel.GetIdentifier()] = rel
+		}
+	}
+	newRels := make(map[RelationshipIdentifier]bool, len(other.Relationships))
+	for _, rel := range other.Relationships {
+		identifier := rel.GetIdentifier()
+		if newRels[identifier] {
+			continue
+		}
+		newRels[identifier] = true
+		old, ok := oldRels[identifier]
+		switch {
+		case !ok:
+			diff.AddedRelationships = append(diff.AddedRelationships, rel)
+		case !sameProperties(old.Properties, rel.Properties):
+			diff.ModifiedRelationships = append(diff.ModifiedRelationships, rel)
+		}
+	}
+	for _, rel := range gd.Relationships {
+		if identifier := rel.GetIdentifier(); !newRels[identifier] {
+			newRels[identifier] = true
+			diff.RemovedRelationships = append(diff.RemovedRelationships, rel)
+		}
+	}
+
+	return diff
+}
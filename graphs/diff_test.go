@@ -0,0 +1,157 @@
+package graphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodePropertyDiff(t *testing.T) {
+	before := NewNode("alice", "Person")
+	before.SetProperty("name", "Alice")
+	before.SetProperty("age", 30)
+	before.SetProperty("email", "alice@example.com")
+	before.SetProperty("tags", []string{"a", "b"})
+
+	after := before.Clone()
+	after.SetProperty("age", 31)
+	after.RemoveProperty("email")
+	after.SetProperty("city", "Paris")
+	after.SetProperty("tags", []string{"a", "b"})
+
+	added, removed, changed := before.PropertyDiff(after)
+
+	if want := map[string]interface{}{"city": "Paris"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := map[string]interface{}{"email": "alice@example.com"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if want := map[string]interface{}{"age": 31}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+}
+
+func TestNodePropertyDiffNilProperties(t *testing.T) {
+	before := Node{ID: "alice", Type: "Person"}
+	after := NewNode("alice", "Person")
+	after.SetProperty("name", "Alice")
+
+	added, removed, changed := before.PropertyDiff(after)
+	if len(added) != 1 || len(removed) != 0 || len(changed) != 0 {
+		t.Errorf("Unexpected diff: added %v, removed %v, changed %v", added, removed, changed)
+	}
+
+	added, removed, changed = before.PropertyDiff(before)
+	if added == nil || removed == nil || changed == nil {
+		t.Fatal("Expected non-nil maps")
+	}
+	if len(added)+len(removed)+len(changed) != 0 {
+		t.Errorf("Expected no differences, got added %v, removed %v, changed %v", added, removed, changed)
+	}
+}
+
+func TestRelationshipPropertyDiff(t *testing.T) {
+	before := NewRelationship(NewNode("alice", "Person"), NewNode("acme", "Company"), "WORKS_AT")
+	before.SetProperty("since", 2020)
+	before.SetProperty("role", "engineer")
+
+	after := before.Clone()
+	after.SetProperty("role", "manager")
+	after.RemoveProperty("since")
+	after.SetProperty("remote", true)
+
+	added, removed, changed := before.PropertyDiff(after)
+
+	if want := map[string]interface{}{"remote": true}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := map[string]interface{}{"since": 2020}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if want := map[string]interface{}{"role": "manager"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+}
+
+// diffTestDocument returns alice knowing bob and working at acme
+func diffTestDocument() *GraphDocument {
+	alice := NewNode("alice", "Person")
+	alice.SetProperty("tags", []string{"a", "b"})
+	bob := NewNode("bob", "Person")
+	acme := NewNode("acme", "Company")
+
+	gd := &GraphDocument{}
+	gd.AddNode(alice)
+	gd.AddNode(bob)
+	gd.AddNode(acme)
+	gd.AddRelationship(NewRelationship(alice, bob, "KNOWS"))
+	gd.AddRelationship(NewRelationship(alice, acme, "WORKS_AT"))
+	return gd
+}
+
+func TestGraphDocumentDiffIdentical(t *testing.T) {
+	diff := diffTestDocument().Diff(diffTestDocument())
+	if !diff.IsEmpty() {
+		t.Errorf("Expected an empty diff, got %+v", diff)
+	}
+}
+
+func TestGraphDocumentDiffNodeAdded(t *testing.T) {
+	after := diffTestDocument()
+	after.AddNode(NewNode("carol", "Person"))
+
+	diff := diffTestDocument().Diff(after)
+	if len(diff.AddedNodes) != 1 || diff.AddedNodes[0].ID != "carol" {
+		t.Errorf("Expected carol to be added, got %+v", diff.AddedNodes)
+	}
+	if len(diff.RemovedNodes)+len(diff.ModifiedNodes) != 0 {
+		t.Errorf("Expected no other node changes, got %+v", diff)
+	}
+
+	diff = after.Diff(diffTestDocument())
+	if len(diff.RemovedNodes) != 1 || diff.RemovedNodes[0].ID != "carol" {
+		t.Errorf("Expected carol to be removed, got %+v", diff.RemovedNodes)
+	}
+}
+
+func TestGraphDocumentDiffNodePropertyChanged(t *testing.T) {
+	after := diffTestDocument()
+	after.UpdateNode("alice", map[string]interface{}{"tags": []string{"a", "c"}})
+
+	diff := diffTestDocument().Diff(after)
+	if len(diff.ModifiedNodes) != 1 || diff.ModifiedNodes[0].ID != "alice" {
+		t.Fatalf("Expected alice to be modified, got %+v", diff.ModifiedNodes)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(diff.ModifiedNodes[0].Properties["tags"], want) {
+		t.Errorf("Expected the new version of alice, got %v", diff.ModifiedNodes[0].Properties)
+	}
+	if len(diff.AddedNodes)+len(diff.RemovedNodes) != 0 {
+		t.Errorf("Expected no other node changes, got %+v", diff)
+	}
+}
+
+func TestGraphDocumentDiffRelationshipRemoved(t *testing.T) {
+	after := diffTestDocument()
+	after.RemoveRelationship("alice", "acme", "WORKS_AT")
+
+	diff := diffTestDocument().Diff(after)
+	if len(diff.RemovedRelationships) != 1 {
+		t.Fatalf("Expected 1 removed relationship, got %+v", diff.RemovedRelationships)
+	}
+	if want := (RelationshipIdentifier{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"}); diff.RemovedRelationships[0].GetIdentifier() != want {
+		t.Errorf("Expected %+v to be removed, got %+v", want, diff.RemovedRelationships[0].GetIdentifier())
+	}
+	if len(diff.AddedRelationships)+len(diff.ModifiedRelationships) != 0 || len(diff.AddedNodes)+len(diff.RemovedNodes)+len(diff.ModifiedNodes) != 0 {
+		t.Errorf("Expected no other changes, got %+v", diff)
+	}
+}
+
+func TestGraphDocumentDiffRelationshipPropertyChanged(t *testing.T) {
+	after := diffTestDocument()
+	after.UpdateRelationship("alice", "bob", "KNOWS", map[string]interface{}{"since": 2020})
+
+	diff := diffTestDocument().Diff(after)
+	if len(diff.ModifiedRelationships) != 1 || diff.ModifiedRelationships[0].Properties["since"] != 2020 {
+		t.Errorf("Expected KNOWS to be modified, got %+v", diff.ModifiedRelationships)
+	}
+}
@@ -0,0 +1,317 @@
+package graphs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ToTGF converts the GraphDocument to Trivial Graph Format. Nodes are numbered
+// from 1 in the order they appear and labelled with their original IDs, so the
+// mapping can be recovered from the output. Node lines are followed by a "#"
+// separator line and then one line per relationship labelled with its type.
+func (gd *GraphDocument) ToTGF() string {
+	var sb strings.Builder
+
+	numbers := make(map[string]int)
+	for _, id := range gd.nodeIDs() {
+		numbers[id] = len(numbers) + 1
+		fmt.Fprintf(&sb, "%d %s\n", numbers[id], tgfLabel(id))
+	}
+
+	sb.WriteString("#\n")
+
+	for _, rel := range gd.Relationships {
+		fmt.Fprintf(&sb, "%d %d %s\n", numbers[rel.Source.ID], numbers[rel.Target.ID], tgfLabel(rel.Type))
+	}
+
+	return sb.String()
+}
+
+// tgfLabel keeps a label on a single line
+func tgfLabel(label string) string {
+	return strings.Join(strings.Fields(label), " ")
+}
+
+// ToCytoscapeJSON converts the GraphDocument to the elements JSON consumed by
+// Cytoscape.js. Node data holds the node properties with "id" and "label" set
+// to the node ID and type; edge data holds the relationship properties with
+// "id", "source", "target" and "label" set to the relationship type. Endpoints
+// missing from Nodes are added so that every edge references a node, and edge
+// IDs are made unique across the document.
+func (gd *GraphDocument) ToCytoscapeJSON() ([]byte, error) {
+	type element struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	nodes := []element{}
+	edges := []element{}
+
+	ids := make(map[string]bool)
+	addNode := func(node Node) {
+		if ids[node.ID] {
+			return
+		}
+		ids[node.ID] = true
+		data := cytoscapeData(node.Properties)
+		data["id"] = node.ID
+		data["label"] = node.Type
+		nodes = append(nodes, element{Data: data})
+	}
+
+	for _, node := range gd.Nodes {
+		addNode(node)
+	}
+	for _, rel := range gd.Relationships {
+		addNode(rel.Source)
+		addNode(rel.Target)
+	}
+
+	for _, rel := range gd.Relationships {
+		id := fmt.Sprintf("%s-%s->%s", rel.Source.ID, rel.Type, rel.Target.ID)
+		for n := 2; ids[id]; n++ {
+			id = fmt.Sprintf("%s-%s->%s#%d", rel.Source.ID, rel.Type, rel.Target.ID, n)
+		}
+		ids[id] = true
+
+		data := cytoscapeData(rel.Properties)
+		data["id"] = id
+		data["source"] = rel.Source.ID
+		data["target"] = rel.Target.ID
+		data["label"] = rel.Type
+		edges = append(edges, element{Data: data})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"elements": map[string]interface{}{
+			"nodes": nodes,
+			"edges": edges,
+		},
+	})
+}
+
+// cytoscapeData copies properties into a new element data map
+func cytoscapeData(properties map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(properties)+4)
+	for k, v := range properties {
+		data[k] = v
+	}
+	return data
+}
+
+const (
+	// MarkdownTableMaxRows is the number of rows ToMarkdownTables renders per table
+	MarkdownTableMaxRows = 25
+	// MarkdownTableMaxValueLength is the number of characters after which ToMarkdownTables truncates cell values
+	MarkdownTableMaxValueLength = 60
+)
+
+// ToMarkdownTables renders the GraphDocument as compact Markdown for LLM prompts: one
+// table per node type, in sorted order, with an "id" column followed by the union of the
+// property keys of the type, then a table of relationships with source, type and target
+// columns. Long values are truncated to MarkdownTableMaxValueLength characters, and tables
+// longer than MarkdownTableMaxRows rows end with an "...and N more" line.
+func (gd *GraphDocument) ToMarkdownTables() string {
+	var sections []string
+
+	types := gd.GetNodeTypes()
+	sort.Strings(types)
+	for _, nodeType := range types {
+		nodes := gd.FindNodesByType(nodeType)
+
+		keySet := make(map[string]bool)
+		for _, node := range nodes {
+			for key := range node.Properties {
+				if key != "id" {
+					keySet[key] = true
+				}
+			}
+		}
+		keys := make([]string, 0, len(keySet))
+		for key := range keySet {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		rows := make([][]string, 0, len(nodes))
+		for _, node := range nodes {
+			row := []string{node.ID}
+			for _, key := range keys {
+				value, ok := node.Properties[key]
+				if !ok {
+					row = append(row, "")
+					continue
+				}
+				row = append(row, fmt.Sprint(value))
+			}
+			rows = append(rows, row)
+		}
+
+		title := fmt.Sprintf("### %s (%d)", markdownCell(nodeType), len(nodes))
+		sections = append(sections, markdownTable(title, append([]string{"id"}, keys...), rows))
+	}
+
+	if len(gd.Relationships) > 0 {
+		rows := make([][]string, 0, len(gd.Relationships))
+		for _, rel := range gd.Relationships {
+			rows = append(rows, []string{rel.Source.ID, rel.Type, rel.Target.ID})
+		}
+		title := fmt.Sprintf("### Relationships (%d)", len(gd.Relationships))
+		sections = append(sections, markdownTable(title, []string{"source", "type", "target"}, rows))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// markdownTable renders a titled table, capping it at MarkdownTableMaxRows rows
+func markdownTable(title string, header []string, rows [][]string) string {
+	var sb strings.Builder
+	sb.WriteString(title + "\n\n")
+
+	cells := make([]string, len(header))
+	for i, column := range header {
+		cells[i] = markdownCell(column)
+	}
+	sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+
+	for i, row := range rows {
+		if i == MarkdownTableMaxRows {
+			fmt.Fprintf(&sb, "\n...and %d more\n", len(rows)-MarkdownTableMaxRows)
+			break
+		}
+		for j, value := range row {
+			cells[j] = markdownCell(value)
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return sb.String()
+}
+
+// markdownCell keeps a value on one line, escapes pipes and truncates it to
+// MarkdownTableMaxValueLength characters
+func markdownCell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > MarkdownTableMaxValueLength {
+		value = string(runes[:MarkdownTableMaxValueLength-3]) + "..."
+	}
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// TemplateFuncs returns the helper functions available to templates rendered with Render.
+// Templates using the helpers must be parsed with them, for example:
+//
+//	tmpl := template.Must(template.New("report").Funcs(graphs.TemplateFuncs()).Parse(text))
+func TemplateFuncs() template.FuncMap {
+	return (&GraphDocument{}).templateFuncs()
+}
+
+// templateFuncs returns the template helpers bound to the document, listing types in sorted order
+func (gd *GraphDocument) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"nodesByType":         gd.FindNodesByType,
+		"relationshipsByType": gd.FindRelationshipsByType,
+		"nodeTypes": func() []string {
+			types := gd.GetNodeTypes()
+			sort.Strings(types)
+			return types
+		},
+		"relationshipTypes": func() []string {
+			types := gd.GetRelationshipTypes()
+			sort.Strings(types)
+			return types
+		},
+	}
+}
+
+// Render executes tmpl with the GraphDocument as data, exposing its Nodes,
+// Relationships and Source fields. Helpers from TemplateFuncs operate on the
+// rendered document. tmpl itself is left unchanged.
+func (gd *GraphDocument) Render(tmpl *template.Template) (string, error) {
+	bound, err := tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to clone template: %w", err)
+	}
+	bound.Funcs(gd.templateFuncs())
+
+	var sb strings.Builder
+	if err := bound.Execute(&sb, gd); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// NodeRows returns one row per node for tabular consumers such as dataframes.
+// Each row holds the node "id" and "type" followed by its properties, with
+// nested property maps flattened into dotted keys. Every row has the same
+// keys: properties a node lacks are set to nil. The id and type columns take
+// precedence over properties with the same key.
+func (gd *GraphDocument) NodeRows() []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(gd.Nodes))
+	for _, node := range gd.Nodes {
+		row := flattenProperties(node.Properties)
+		row["id"] = node.ID
+		row["type"] = node.Type
+		rows = append(rows, row)
+	}
+	return alignRows(rows)
+}
+
+// RelationshipRows returns one row per relationship for tabular consumers. Each
+// row holds "source", "source_type", "target", "target_type" and "type" followed
+// by the flattened relationship properties, with the same key set across rows
+// as in NodeRows.
+func (gd *GraphDocument) RelationshipRows() []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(gd.Relationships))
+	for _, rel := range gd.Relationships {
+		row := flattenProperties(rel.Properties)
+		row["source"] = rel.Source.ID
+		row["source_type"] = rel.Source.Type
+		row["target"] = rel.Target.ID
+		row["target_type"] = rel.Target.Type
+		row["type"] = rel.Type
+		rows = append(rows, row)
+	}
+	return alignRows(rows)
+}
+
+// flattenProperties copies properties into a new map, joining the keys of nested maps with dots
+func flattenProperties(properties map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	flattenInto(flat, "", properties)
+	return flat
+}
+
+// flattenInto writes properties into flat under prefix
+func flattenInto(flat map[string]interface{}, prefix string, properties map[string]interface{}) {
+	for key, value := range properties {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(flat, key, nested)
+			continue
+		}
+		flat[key] = value
+	}
+}
+
+// alignRows sets every key found in any row on all rows, using nil for missing values
+func alignRows(rows []map[string]interface{}) []map[string]interface{} {
+	keys := make(map[string]struct{})
+	for _, row := range rows {
+		for key := range row {
+			keys[key] = struct{}{}
+		}
+	}
+	for _, row := range rows {
+		for key := range keys {
+			if _, ok := row[key]; !ok {
+				row[key] = nil
+			}
+		}
+	}
+	return rows
+}
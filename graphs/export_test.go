@@ -0,0 +1,316 @@
+package graphs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestToTGF(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	tgf := gd.ToTGF()
+	lines := strings.Split(strings.TrimSuffix(tgf, "\n"), "\n")
+
+	separator := -1
+	for i, line := range lines {
+		if line == "#" {
+			if separator != -1 {
+				t.Fatalf("Expected a single separator line, got another at %d", i)
+			}
+			separator = i
+		}
+	}
+	if separator != 3 {
+		t.Fatalf("Expected separator after 3 node lines, got index %d in:\n%s", separator, tgf)
+	}
+
+	expectedNodes := []string{"1 alice", "2 bob", "3 acme"}
+	for i, want := range expectedNodes {
+		if lines[i] != want {
+			t.Errorf("Expected node line %q, got %q", want, lines[i])
+		}
+	}
+
+	expectedEdges := []string{"1 2 KNOWS", "1 3 WORKS_AT", "2 3 WORKS_AT"}
+	edges := lines[separator+1:]
+	if len(edges) != len(expectedEdges) {
+		t.Fatalf("Expected %d edge lines, got %d", len(expectedEdges), len(edges))
+	}
+	for i, want := range expectedEdges {
+		if edges[i] != want {
+			t.Errorf("Expected edge line %q, got %q", want, edges[i])
+		}
+	}
+}
+
+func TestToTGFEmpty(t *testing.T) {
+	gd := GraphDocument{}
+	if tgf := gd.ToTGF(); tgf != "#\n" {
+		t.Errorf("Expected only the separator for an empty graph, got %q", tgf)
+	}
+}
+
+func TestToCytoscapeJSON(t *testing.T) {
+	gd := newTestGraphDocument()
+	// An endpoint missing from Nodes and a duplicate relationship
+	carol := NewNode("carol", "Person")
+	gd.AddRelationship(NewRelationship(carol, gd.Nodes[0], "KNOWS"))
+	gd.AddRelationship(NewRelationship(gd.Nodes[0], gd.Nodes[1], "KNOWS"))
+
+	data, err := gd.ToCytoscapeJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out struct {
+		Elements struct {
+			Nodes []struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+
+	if len(out.Elements.Nodes) != 4 {
+		t.Fatalf("Expected 4 nodes, got %d", len(out.Elements.Nodes))
+	}
+	if len(out.Elements.Edges) != 5 {
+		t.Fatalf("Expected 5 edges, got %d", len(out.Elements.Edges))
+	}
+
+	ids := make(map[string]bool)
+	nodeIDs := make(map[string]bool)
+	for _, node := range out.Elements.Nodes {
+		id, _ := node.Data["id"].(string)
+		if id == "" || ids[id] {
+			t.Errorf("Expected a unique node id, got %v", node.Data)
+		}
+		ids[id], nodeIDs[id] = true, true
+	}
+	alice := out.Elements.Nodes[0].Data
+	if alice["id"] != "alice" || alice["label"] != "Person" || alice["name"] != "Alice" {
+		t.Errorf("Unexpected node data %v", alice)
+	}
+
+	for _, edge := range out.Elements.Edges {
+		id, _ := edge.Data["id"].(string)
+		if id == "" || ids[id] {
+			t.Errorf("Expected a unique edge id, got %v", edge.Data)
+		}
+		ids[id] = true
+		for _, key := range []string{"source", "target"} {
+			if ref, _ := edge.Data[key].(string); !nodeIDs[ref] {
+				t.Errorf("Edge %s references unknown %s %q", id, key, ref)
+			}
+		}
+		if edge.Data["label"] == "" {
+			t.Errorf("Expected a label on edge %s", id)
+		}
+	}
+	knows := out.Elements.Edges[0].Data
+	if knows["source"] != "alice" || knows["target"] != "bob" || knows["label"] != "KNOWS" || knows["since"] != "2020" {
+		t.Errorf("Unexpected edge data %v", knows)
+	}
+}
+
+func TestToCytoscapeJSONEmpty(t *testing.T) {
+	gd := GraphDocument{}
+	data, err := gd.ToCytoscapeJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := `{"elements":{"edges":[],"nodes":[]}}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+func TestRender(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	text := `{{len .Nodes}} nodes, {{len .Relationships}} relationships
+{{range nodeTypes}}{{.}}: {{len (nodesByType .)}}
+{{end}}{{range relationshipsByType "WORKS_AT"}}{{.Source.ID}} works at {{.Target.ID}}
+{{end}}`
+	tmpl := template.Must(template.New("report").Funcs(TemplateFuncs()).Parse(text))
+
+	out, err := gd.Render(tmpl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `3 nodes, 3 relationships
+Company: 1
+Person: 2
+alice works at acme
+bob works at acme
+`
+	if out != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	// The template stays reusable for other documents
+	other := NewGraphDocument(gd.Source)
+	other.AddNode(NewNode("solo", "Person"))
+	out, err = other.Render(tmpl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "1 nodes, 0 relationships\nPerson: 1\n") {
+		t.Errorf("Unexpected output for second document: %q", out)
+	}
+}
+
+func TestRenderExecutionError(t *testing.T) {
+	gd := newTestGraphDocument()
+	tmpl := template.Must(template.New("bad").Parse("{{.Missing}}"))
+
+	if _, err := gd.Render(tmpl); err == nil {
+		t.Error("Expected an error for an invalid field")
+	}
+}
+
+func TestNodeRowsFlattensNestedProperties(t *testing.T) {
+	gd := GraphDocument{}
+	alice := NewNode("alice", "Person")
+	alice.SetProperty("name", "Alice")
+	alice.SetProperty("address", map[string]interface{}{
+		"city": "Paris",
+		"geo":  map[string]interface{}{"lat": 48.85, "lon": 2.35},
+	})
+	gd.AddNode(alice)
+
+	rows := gd.NodeRows()
+	if len(rows) != 1 {
+		t.Fatalf("Expected 1 row, got %d", len(rows))
+	}
+
+	expected := map[string]interface{}{
+		"id":              "alice",
+		"type":            "Person",
+		"name":            "Alice",
+		"address.city":    "Paris",
+		"address.geo.lat": 48.85,
+		"address.geo.lon": 2.35,
+	}
+	if !reflect.DeepEqual(rows[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, rows[0])
+	}
+}
+
+func TestRowsHaveConsistentKeys(t *testing.T) {
+	gd := newTestGraphDocument()
+	acme := gd.Nodes[2]
+	acme.SetProperty("hq", map[string]interface{}{"city": "Berlin"})
+	gd.Nodes[2] = acme
+
+	nodeRows := gd.NodeRows()
+	if len(nodeRows) != 3 {
+		t.Fatalf("Expected 3 node rows, got %d", len(nodeRows))
+	}
+	for _, row := range nodeRows {
+		if len(row) != 4 {
+			t.Errorf("Expected id, type, name and hq.city on every row, got %v", row)
+		}
+	}
+	if nodeRows[2]["name"] != nil || nodeRows[2]["hq.city"] != "Berlin" {
+		t.Errorf("Expected missing values to be nil, got %v", nodeRows[2])
+	}
+	if v, ok := nodeRows[0]["hq.city"]; !ok || v != nil {
+		t.Errorf("Expected hq.city to be present and nil, got %v", nodeRows[0])
+	}
+
+	relRows := gd.RelationshipRows()
+	if len(relRows) != 3 {
+		t.Fatalf("Expected 3 relationship rows, got %d", len(relRows))
+	}
+	for _, row := range relRows {
+		if len(row) != 6 {
+			t.Errorf("Expected 6 columns on every relationship row, got %v", row)
+		}
+	}
+	first := relRows[0]
+	if first["source"] != "alice" || first["source_type"] != "Person" || first["target"] != "bob" ||
+		first["target_type"] != "Person" || first["type"] != "KNOWS" || first["since"] != "2020" {
+		t.Errorf("Unexpected relationship row %v", first)
+	}
+	if v, ok := relRows[1]["since"]; !ok || v != nil {
+		t.Errorf("Expected since to be present and nil, got %v", relRows[1])
+	}
+}
+
+func TestToMarkdownTables(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.Nodes[1].SetProperty("age", 30)
+	gd.Nodes[2].SetProperty("motto", "Make | break")
+
+	want := `### Company (1)
+
+| id | motto |
+| --- | --- |
+| acme | Make \| break |
+
+### Person (2)
+
+| id | age | name |
+| --- | --- | --- |
+| alice |  | Alice |
+| bob | 30 | Bob |
+
+### Relationships (3)
+
+| source | type | target |
+| --- | --- | --- |
+| alice | KNOWS | bob |
+| alice | WORKS_AT | acme |
+| bob | WORKS_AT | acme |
+`
+	if got := gd.ToMarkdownTables(); got != want {
+		t.Errorf("Unexpected tables:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToMarkdownTablesCapsRowsAndValues(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	for i := 0; i < MarkdownTableMaxRows+5; i++ {
+		node := NewNode(fmt.Sprintf("chunk-%02d", i), "Chunk")
+		node.SetProperty("text", strings.Repeat("lorem ipsum\n", 20))
+		gd.AddNode(node)
+	}
+
+	tables := gd.ToMarkdownTables()
+
+	rows := strings.Count(tables, "| chunk-")
+	if rows != MarkdownTableMaxRows {
+		t.Errorf("Expected %d rows, got %d", MarkdownTableMaxRows, rows)
+	}
+	if !strings.HasSuffix(tables, "\n...and 5 more\n") {
+		t.Errorf("Expected a footer counting the omitted rows, got:\n%s", tables)
+	}
+	if strings.Contains(tables, "chunk-25") {
+		t.Error("Expected rows beyond the cap to be omitted")
+	}
+
+	firstRow := strings.Split(tables, "\n")[4]
+	cell := strings.TrimSuffix(strings.SplitN(firstRow, " | ", 2)[1], " |")
+	if len(cell) != MarkdownTableMaxValueLength || !strings.HasSuffix(cell, "...") {
+		t.Errorf("Expected a value truncated to %d characters, got %q", MarkdownTableMaxValueLength, cell)
+	}
+}
+
+func TestToMarkdownTablesEmpty(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	if got := gd.ToMarkdownTables(); got != "" {
+		t.Errorf("Expected no tables for an empty document, got %q", got)
+	}
+}
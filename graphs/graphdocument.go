@@ -1,17 +1,45 @@
 package graphs
 
 import (
+	"bufio"
+	"compress/gzip"
+	"crypto/md5"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 
 	"github.com/tmc/langchaingo/schema"
 )
 
+const (
+	// compressedMagic prefixes every compressed GraphDocument stream
+	compressedMagic = "LCGG"
+	// compressedVersion is the current version of the compressed format
+	compressedVersion byte = 1
+
+	// JSONVersion is the version of the JSON format written by ToJSON. FromJSON also
+	// reads version 1 payloads, written before the version field and node labels existed.
+	JSONVersion = 2
+)
+
+var (
+	// ErrUnsupportedJSONVersion is returned when JSON was written by a newer, unknown format version
+	ErrUnsupportedJSONVersion = errors.New("unsupported graph document JSON version")
+	// ErrInvalidCompressedData is returned when a compressed GraphDocument stream cannot be decoded
+	ErrInvalidCompressedData = errors.New("invalid compressed graph document")
+	// ErrNodeNotFound is returned when a node is not present in the GraphDocument
+	ErrNodeNotFound = errors.New("node not found")
+)
+
 // Node represents a node in a graph with associated properties.
 type Node struct {
 	// ID is the unique identifier for the node.
 	ID string `json:"id"`
 	// Type  is the type or label of the node.
 	Type string `json:"type"`
+	// Labels lists all labels of the node including Type, when known.
+	Labels []string `json:"labels,omitempty"`
 	// Properties contains additional properties and metadata associated with the node.
 	Properties map[string]interface{} `json:"properties,"`
 }
@@ -187,6 +215,18 @@ func (r *Relationship) GetIdentifier() RelationshipIdentifier {
 	}
 }
 
+// DropSelfLoops returns the relationships whose source and target are different nodes.
+// Stores use it to honor the DropSelfLoops option.
+func DropSelfLoops(relationships []Relationship) []Relationship {
+	kept := make([]Relationship, 0, len(relationships))
+	for _, rel := range relationships {
+		if rel.Source.ID != rel.Target.ID {
+			kept = append(kept, rel)
+		}
+	}
+	return kept
+}
+
 // NewGraphDocument creates a new GraphDocument with the given source document
 func NewGraphDocument(source schema.Document) GraphDocument {
 	return GraphDocument{
@@ -232,6 +272,75 @@ func (gd *GraphDocument) RemoveNodes(nodeIDs []string) int {
 	return removed
 }
 
+// ContractedViaProperty is the property listing, in path order, the IDs of the nodes
+// contracted into a relationship synthesized by ContractNode.
+const ContractedViaProperty = "contracted_via"
+
+// ContractedTypeFunc returns the type of the relationship replacing the path in -> node -> out.
+type ContractedTypeFunc func(in, out Relationship) string
+
+// ContractNode removes a node and connects each of its in-neighbors to each of its
+// out-neighbors, so that A-[X]->B-[Y]->C becomes A-[X_Y]->C when B is contracted.
+// See ContractNodeWith for details.
+func (gd *GraphDocument) ContractNode(nodeID string) error {
+	return gd.ContractNodeWith(nodeID, func(in, out Relationship) string {
+		return in.Type + "_" + out.Type
+	})
+}
+
+// ContractNodeWith removes a node and connects each of its in-neighbors to each of its
+// out-neighbors with a relationship of the type returned by typeFunc. Synthesized
+// relationships record the contracted node IDs in ContractedViaProperty, extending the
+// trace of relationships that were themselves synthesized, so contracting every inner
+// node of a chain leaves a single relationship listing the whole chain. Self-loops on
+// the node are dropped. ErrNodeNotFound is returned if the node does not exist.
+func (gd *GraphDocument) ContractNodeWith(nodeID string, typeFunc ContractedTypeFunc) error {
+	if !gd.NodeExists(nodeID) {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+
+	var incoming, outgoing []Relationship
+	for _, rel := range gd.Relationships {
+		switch {
+		case rel.Source.ID == nodeID && rel.Target.ID == nodeID:
+		case rel.Target.ID == nodeID:
+			incoming = append(incoming, rel)
+		case rel.Source.ID == nodeID:
+			outgoing = append(outgoing, rel)
+		}
+	}
+
+	gd.RemoveNode(nodeID)
+
+	for _, in := range incoming {
+		for _, out := range outgoing {
+			via := append(contractedVia(in), nodeID)
+			via = append(via, contractedVia(out)...)
+
+			rel := NewRelationship(in.Source, out.Target, typeFunc(in, out))
+			rel.SetProperty(ContractedViaProperty, via)
+			gd.AddRelationship(rel)
+		}
+	}
+
+	return nil
+}
+
+// contractedVia returns a copy of the contracted node IDs recorded on rel
+func contractedVia(rel Relationship) []string {
+	switch via := rel.Properties[ContractedViaProperty].(type) {
+	case []string:
+		return append([]string(nil), via...)
+	case []interface{}:
+		ids := make([]string, 0, len(via))
+		for _, id := range via {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		return ids
+	}
+	return nil
+}
+
 // RemoveRelationship removes a relationship from the GraphDocument
 func (gd *GraphDocument) RemoveRelationship(sourceID, targetID, relType string) bool {
 	for i, rel := range gd.Relationships {
@@ -255,6 +364,20 @@ func (gd *GraphDocument) RemoveRelationships(identifiers []RelationshipIdentifie
 	return removed
 }
 
+// RemoveRelationshipsByType removes all relationships of the given type from the GraphDocument
+// and returns the number removed. Nodes are left in place.
+func (gd *GraphDocument) RemoveRelationshipsByType(relType string) int {
+	kept := gd.Relationships[:0]
+	for _, rel := range gd.Relationships {
+		if rel.Type != relType {
+			kept = append(kept, rel)
+		}
+	}
+	removed := len(gd.Relationships) - len(kept)
+	gd.Relationships = kept
+	return removed
+}
+
 // removeRelationshipsByNodeID removes all relationships involving a specific node
 func (gd *GraphDocument) removeRelationshipsByNodeID(nodeID string) {
 	filtered := make([]Relationship, 0, len(gd.Relationships))
@@ -319,6 +442,32 @@ func (gd *GraphDocument) FindRelationshipsByNode(nodeID string) []Relationship {
 	return relationships
 }
 
+// RelationshipsBetweenTypes finds all relationships from a node of sourceType to a node of
+// targetType. Endpoint types are resolved from the node of the same ID in Nodes, falling back
+// to the type of the endpoint embedded in the relationship for nodes absent from Nodes.
+func (gd *GraphDocument) RelationshipsBetweenTypes(sourceType, targetType string) []Relationship {
+	types := make(map[string]string, len(gd.Nodes))
+	for _, node := range gd.Nodes {
+		if _, ok := types[node.ID]; !ok {
+			types[node.ID] = node.Type
+		}
+	}
+	typeOf := func(endpoint Node) string {
+		if nodeType, ok := types[endpoint.ID]; ok {
+			return nodeType
+		}
+		return endpoint.Type
+	}
+
+	var relationships []Relationship
+	for _, rel := range gd.Relationships {
+		if typeOf(rel.Source) == sourceType && typeOf(rel.Target) == targetType {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships
+}
+
 // UpdateNode updates an existing node's properties
 func (gd *GraphDocument) UpdateNode(nodeID string, properties map[string]interface{}) bool {
 	node := gd.FindNode(nodeID)
@@ -353,6 +502,101 @@ func (gd *GraphDocument) UpdateRelationship(sourceID, targetID, relType string,
 	return true
 }
 
+// ApplyNodeDefaults fills in missing properties on nodes from per-type defaults.
+// The defaults map is keyed by node type; existing property values are never overwritten.
+func (gd *GraphDocument) ApplyNodeDefaults(defaults map[string]map[string]interface{}) {
+	for i := range gd.Nodes {
+		for key, value := range defaults[gd.Nodes[i].Type] {
+			if !gd.Nodes[i].HasProperty(key) {
+				gd.Nodes[i].SetProperty(key, value)
+			}
+		}
+	}
+}
+
+// ApplyRelationshipDefaults fills in missing properties on relationships from per-type defaults.
+// The defaults map is keyed by relationship type; existing property values are never overwritten.
+func (gd *GraphDocument) ApplyRelationshipDefaults(defaults map[string]map[string]interface{}) {
+	for i := range gd.Relationships {
+		for key, value := range defaults[gd.Relationships[i].Type] {
+			if !gd.Relationships[i].HasProperty(key) {
+				gd.Relationships[i].SetProperty(key, value)
+			}
+		}
+	}
+}
+
+// CopyPropertiesFrom copies the listed property keys of every node in other into the node
+// with the same ID in the GraphDocument. Keys missing on the other node are skipped, and
+// an empty keys slice copies all properties. Nodes only in one document are untouched.
+func (gd *GraphDocument) CopyPropertiesFrom(other *GraphDocument, keys []string) {
+	for _, source := range other.Nodes {
+		target := gd.FindNode(source.ID)
+		if target == nil {
+			continue
+		}
+
+		if len(keys) == 0 {
+			for key, value := range source.Properties {
+				target.SetProperty(key, value)
+			}
+			continue
+		}
+		for _, key := range keys {
+			if value, ok := source.GetProperty(key); ok {
+				target.SetProperty(key, value)
+			}
+		}
+	}
+}
+
+// RelabelNodes renames node types according to mapping, leaving unmapped types unchanged.
+// Relationship endpoint types are renamed accordingly.
+func (gd *GraphDocument) RelabelNodes(mapping map[string]string) {
+	for i := range gd.Nodes {
+		if newType, ok := mapping[gd.Nodes[i].Type]; ok {
+			gd.Nodes[i].Type = newType
+		}
+	}
+	for i := range gd.Relationships {
+		if newType, ok := mapping[gd.Relationships[i].Source.Type]; ok {
+			gd.Relationships[i].Source.Type = newType
+		}
+		if newType, ok := mapping[gd.Relationships[i].Target.Type]; ok {
+			gd.Relationships[i].Target.Type = newType
+		}
+	}
+}
+
+// RelabelRelationships renames relationship types according to mapping, leaving unmapped types unchanged
+func (gd *GraphDocument) RelabelRelationships(mapping map[string]string) {
+	for i := range gd.Relationships {
+		if newType, ok := mapping[gd.Relationships[i].Type]; ok {
+			gd.Relationships[i].Type = newType
+		}
+	}
+}
+
+// ReconcileEndpoints replaces the Source and Target of every relationship with the node
+// of the same ID in Nodes, so embedded endpoints reflect later edits to the nodes.
+// Endpoints whose node is absent from Nodes are left unchanged.
+func (gd *GraphDocument) ReconcileEndpoints() {
+	nodes := make(map[string]Node, len(gd.Nodes))
+	for _, node := range gd.Nodes {
+		if _, exists := nodes[node.ID]; !exists {
+			nodes[node.ID] = node
+		}
+	}
+	for i := range gd.Relationships {
+		if node, ok := nodes[gd.Relationships[i].Source.ID]; ok {
+			gd.Relationships[i].Source = node
+		}
+		if node, ok := nodes[gd.Relationships[i].Target.ID]; ok {
+			gd.Relationships[i].Target = node
+		}
+	}
+}
+
 // NodeExists checks if a node exists in the GraphDocument
 func (gd *GraphDocument) NodeExists(nodeID string) bool {
 	return gd.FindNode(nodeID) != nil
@@ -401,19 +645,154 @@ func (gd *GraphDocument) GetRelationshipTypes() []string {
 	return result
 }
 
-// Merge merges another GraphDocument into this one
+// Merge merges another GraphDocument into this one, adding the nodes and relationships
+// that don't already exist. Entities already in the document are left unchanged.
 func (gd *GraphDocument) Merge(other *GraphDocument) {
-	// Add nodes that don't already exist
+	gd.MergeWith(other, nil)
+}
+
+// MergeWith merges another GraphDocument into this one. Nodes and relationships that
+// don't already exist are added. Colliding nodes, matched by ID, and relationships,
+// matched by identifier, have their properties reconciled with strategy as in
+// Node.Merge: keys missing on one side are preserved, and conflicting values are
+// resolved by strategy, e.g. MergeUnion to keep existing values, MergeOverwrite to
+// let newer non-nil values win or MergeCollect to keep all of them. MergeSkip, the
+// nil strategy, leaves colliding entities unchanged, as Merge does.
+func (gd *GraphDocument) MergeWith(other *GraphDocument, strategy MergeStrategy) {
+	nodeIndex := make(map[string]int, len(gd.Nodes))
+	for i, node := range gd.Nodes {
+		if _, ok := nodeIndex[node.ID]; !ok {
+			nodeIndex[node.ID] = i
+		}
+	}
+	relIndex := make(map[RelationshipIdentifier]int, len(gd.Relationships))
+	for i, rel := range gd.Relationships {
+		if _, ok := relIndex[rel.GetIdentifier()]; !ok {
+			relIndex[rel.GetIdentifier()] = i
+		}
+	}
+
 	for _, node := range other.Nodes {
-		if !gd.NodeExists(node.ID) {
+		i, ok := nodeIndex[node.ID]
+		if !ok {
+			nodeIndex[node.ID] = len(gd.Nodes)
 			gd.AddNode(node)
+		} else if strategy != nil {
+			gd.Nodes[i].Merge(node, strategy)
 		}
 	}
 
-	// Add relationships that don't already exist
 	for _, rel := range other.Relationships {
-		if !gd.RelationshipExists(rel.Source.ID, rel.Target.ID, rel.Type) {
+		i, ok := relIndex[rel.GetIdentifier()]
+		if !ok {
+			relIndex[rel.GetIdentifier()] = len(gd.Relationships)
 			gd.AddRelationship(rel)
+		} else if strategy != nil {
+			gd.Relationships[i].Merge(rel, strategy)
+		}
+	}
+}
+
+// UnionDocuments merges all documents into a single GraphDocument with the given source.
+// Nodes are deduplicated by ID and relationships by identifier, with their properties
+// unioned; when documents disagree on a property the first value seen is kept.
+func UnionDocuments(docs []*GraphDocument, source schema.Document) *GraphDocument {
+	union := NewGraphDocument(source)
+	nodeIndex := make(map[string]int)
+	relIndex := make(map[RelationshipIdentifier]int)
+
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+
+		for _, node := range doc.Nodes {
+			if i, ok := nodeIndex[node.ID]; ok {
+				unionProperties(union.Nodes[i].Properties, node.Properties)
+				continue
+			}
+			nodeIndex[node.ID] = len(union.Nodes)
+			union.AddNode(node.Clone())
+		}
+
+		for _, rel := range doc.Relationships {
+			identifier := rel.GetIdentifier()
+			if i, ok := relIndex[identifier]; ok {
+				unionProperties(union.Relationships[i].Properties, rel.Properties)
+				continue
+			}
+			relIndex[identifier] = len(union.Relationships)
+			union.AddRelationship(rel.Clone())
+		}
+	}
+
+	return &union
+}
+
+// SplitBySize partitions the GraphDocument into sub-documents of at most maxNodes nodes
+// for memory-bounded import. Every relationship is placed in the chunk of its source node,
+// and its target is copied into that chunk when owned by another, so each chunk can be
+// imported on its own. A node whose distinct targets alone exceed maxNodes gets an
+// oversized chunk. Every chunk keeps the document source. A non-positive maxNodes
+// returns a single copy of the document.
+func (gd *GraphDocument) SplitBySize(maxNodes int) []*GraphDocument {
+	if maxNodes <= 0 {
+		return []*GraphDocument{gd.Clone()}
+	}
+
+	outgoing := make(map[string][]Relationship)
+	for _, rel := range gd.Relationships {
+		outgoing[rel.Source.ID] = append(outgoing[rel.Source.ID], rel)
+	}
+
+	var chunks []*GraphDocument
+	var current *GraphDocument
+	var members map[string]bool
+
+	for _, id := range gd.nodeIDs() {
+		needed := func() []Node {
+			var nodes []Node
+			seen := make(map[string]bool)
+			if !members[id] {
+				nodes = append(nodes, *gd.pathNode(id, nil))
+				seen[id] = true
+			}
+			for _, rel := range outgoing[id] {
+				target := rel.Target
+				if !members[target.ID] && !seen[target.ID] {
+					nodes = append(nodes, *gd.pathNode(target.ID, &target))
+					seen[target.ID] = true
+				}
+			}
+			return nodes
+		}
+
+		nodes := needed()
+		if current == nil || (len(current.Nodes) > 0 && len(current.Nodes)+len(nodes) > maxNodes) {
+			chunk := NewGraphDocument(gd.Source)
+			current = &chunk
+			members = make(map[string]bool)
+			chunks = append(chunks, current)
+			nodes = needed()
+		}
+
+		for _, node := range nodes {
+			current.AddNode(node.Clone())
+			members[node.ID] = true
+		}
+		for _, rel := range outgoing[id] {
+			current.AddRelationship(rel.Clone())
+		}
+	}
+
+	return chunks
+}
+
+// unionProperties adds the incoming properties missing from target
+func unionProperties(target, incoming map[string]interface{}) {
+	for k, v := range incoming {
+		if _, exists := target[k]; !exists {
+			target[k] = v
 		}
 	}
 }
@@ -427,6 +806,7 @@ func (gd *GraphDocument) Clone() *GraphDocument {
 		newNode := Node{
 			ID:         node.ID,
 			Type:       node.Type,
+			Labels:     append([]string(nil), node.Labels...),
 			Properties: make(map[string]interface{}),
 		}
 		for k, v := range node.Properties {
@@ -452,17 +832,201 @@ func (gd *GraphDocument) Clone() *GraphDocument {
 	return &clone
 }
 
-// ToJSON converts the GraphDocument to a JSON representation
+// versionedGraphDocument is the JSON envelope of a GraphDocument, recording the format version
+type versionedGraphDocument struct {
+	Version int `json:"_version"`
+	GraphDocument
+}
+
+// SourceReference identifies the source document of a GraphDocument without its page content
+type SourceReference struct {
+	// ID is the source's "id" metadata, or the MD5 hash of its page content when absent
+	ID string `json:"id"`
+	// Metadata is the metadata of the source document
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// SourceResolver returns the source document referenced by a GraphDocument serialized with ToJSONLite
+type SourceResolver func(ref SourceReference) (schema.Document, error)
+
+// JSONOption configures FromJSON.
+type JSONOption func(*jsonOptions)
+
+// jsonOptions contains configuration for reading GraphDocument JSON
+type jsonOptions struct {
+	sourceResolver SourceResolver
+}
+
+// WithSourceResolver sets the resolver FromJSON uses to rehydrate the source of JSON
+// written by ToJSONLite. Without a resolver the source only carries the referenced
+// metadata, with the reference ID stored under the "id" key.
+func WithSourceResolver(resolver SourceResolver) JSONOption {
+	return func(opts *jsonOptions) {
+		opts.sourceResolver = resolver
+	}
+}
+
+// liteGraphDocument is the JSON envelope of a GraphDocument whose source is only referenced
+type liteGraphDocument struct {
+	Version       int             `json:"_version"`
+	Nodes         []Node          `json:"nodes"`
+	Relationships []Relationship  `json:"relationships"`
+	SourceRef     SourceReference `json:"source_ref"`
+}
+
+// SourceRef returns a reference to the source document of the GraphDocument.
+func (gd *GraphDocument) SourceRef() SourceReference {
+	id := fmt.Sprintf("%x", md5.Sum([]byte(gd.Source.PageContent)))
+	if value, ok := gd.Source.Metadata["id"].(string); ok && value != "" {
+		id = value
+	}
+	return SourceReference{ID: id, Metadata: gd.Source.Metadata}
+}
+
+// ToJSONLite converts the GraphDocument to JSON like ToJSON, replacing the source
+// document with a "source_ref" holding its SourceRef. The page content is omitted,
+// keeping serialized graphs small when many of them share source documents.
+func (gd *GraphDocument) ToJSONLite() ([]byte, error) {
+	return json.Marshal(liteGraphDocument{
+		Version:       JSONVersion,
+		Nodes:         gd.Nodes,
+		Relationships: gd.Relationships,
+		SourceRef:     gd.SourceRef(),
+	})
+}
+
+// ToJSON converts the GraphDocument to a JSON representation.
+// The output carries a "_version" field set to JSONVersion.
 func (gd *GraphDocument) ToJSON() ([]byte, error) {
-	return json.Marshal(gd)
+	return json.Marshal(versionedGraphDocument{Version: JSONVersion, GraphDocument: *gd})
 }
 
-// FromJSON creates a GraphDocument from JSON
-func FromJSON(data []byte) (*GraphDocument, error) {
+// FromJSON creates a GraphDocument from JSON, migrating payloads written by older
+// versions to the current format. JSON without a "_version" field is read as version 1.
+// The source of JSON written by ToJSONLite is rehydrated through WithSourceResolver.
+func FromJSON(data []byte, options ...JSONOption) (*GraphDocument, error) {
+	opts := &jsonOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	var header struct {
+		Version   *int             `json:"_version"`
+		SourceRef *SourceReference `json:"source_ref"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	version := 1
+	if header.Version != nil {
+		version = *header.Version
+	}
+	if version < 1 || version > JSONVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedJSONVersion, version)
+	}
+
 	var gd GraphDocument
 	err := json.Unmarshal(data, &gd)
 	if err != nil {
 		return nil, err
 	}
+	if version == 1 {
+		migrateJSONV1(&gd)
+	}
+	if header.SourceRef != nil {
+		source, err := resolveSource(*header.SourceRef, opts.sourceResolver)
+		if err != nil {
+			return nil, err
+		}
+		gd.Source = source
+	}
 	return &gd, nil
 }
+
+// resolveSource rehydrates a referenced source document, falling back to its metadata
+func resolveSource(ref SourceReference, resolver SourceResolver) (schema.Document, error) {
+	if resolver != nil {
+		source, err := resolver(ref)
+		if err != nil {
+			return schema.Document{}, fmt.Errorf("failed to resolve source %q: %w", ref.ID, err)
+		}
+		return source, nil
+	}
+
+	metadata := make(map[string]interface{}, len(ref.Metadata)+1)
+	for key, value := range ref.Metadata {
+		metadata[key] = value
+	}
+	metadata["id"] = ref.ID
+	return schema.Document{Metadata: metadata}, nil
+}
+
+// migrateJSONV1 promotes the single type of version 1 nodes into their labels
+func migrateJSONV1(gd *GraphDocument) {
+	promote := func(node *Node) {
+		if len(node.Labels) == 0 && node.Type != "" {
+			node.Labels = []string{node.Type}
+		}
+	}
+	for i := range gd.Nodes {
+		promote(&gd.Nodes[i])
+	}
+	for i := range gd.Relationships {
+		promote(&gd.Relationships[i].Source)
+		promote(&gd.Relationships[i].Target)
+	}
+}
+
+// WriteCompressed writes the GraphDocument as gzip-compressed JSON.
+// The stream starts with a short magic string and a format version byte.
+func (gd *GraphDocument) WriteCompressed(w io.Writer) error {
+	header := append([]byte(compressedMagic), compressedVersion)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	data, err := gd.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(data); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadCompressed reads a GraphDocument written by WriteCompressed
+func ReadCompressed(r io.Reader) (*GraphDocument, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(compressedMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCompressedData, err)
+	}
+	if string(header[:len(compressedMagic)]) != compressedMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidCompressedData)
+	}
+	if version := header[len(compressedMagic)]; version != compressedVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCompressedData, version)
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCompressedData, err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCompressedData, err)
+	}
+
+	gd, err := FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCompressedData, err)
+	}
+	return gd, nil
+}
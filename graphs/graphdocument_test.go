@@ -0,0 +1,720 @@
+package graphs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// newTestGraphDocument builds a small graph of people and the companies they work for
+func newTestGraphDocument() GraphDocument {
+	gd := NewGraphDocument(schema.Document{
+		PageContent: "Alice and Bob work at Acme.",
+		Metadata:    map[string]interface{}{"source": "test"},
+	})
+
+	alice := NewNode("alice", "Person")
+	alice.SetProperty("name", "Alice")
+	bob := NewNode("bob", "Person")
+	bob.SetProperty("name", "Bob")
+	acme := NewNode("acme", "Company")
+
+	gd.AddNode(alice)
+	gd.AddNode(bob)
+	gd.AddNode(acme)
+
+	knows := NewRelationship(alice, bob, "KNOWS")
+	knows.SetProperty("since", "2020")
+	gd.AddRelationship(knows)
+	gd.AddRelationship(NewRelationship(alice, acme, "WORKS_AT"))
+	gd.AddRelationship(NewRelationship(bob, acme, "WORKS_AT"))
+
+	return gd
+}
+
+func TestCompressedRoundTrip(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	var buf bytes.Buffer
+	if err := gd.WriteCompressed(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restored, err := ReadCompressed(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if restored.GetNodeCount() != 3 || restored.GetRelationshipCount() != 3 {
+		t.Errorf("Expected 3 nodes and 3 relationships, got %d and %d",
+			restored.GetNodeCount(), restored.GetRelationshipCount())
+	}
+	if restored.Source.PageContent != gd.Source.PageContent {
+		t.Errorf("Expected source content %q, got %q", gd.Source.PageContent, restored.Source.PageContent)
+	}
+	if rel := restored.FindRelationship("alice", "bob", "KNOWS"); rel == nil || rel.Properties["since"] != "2020" {
+		t.Errorf("Expected KNOWS relationship with properties to survive, got %+v", rel)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.Nodes[0].Labels = []string{"Person", "Employee"}
+
+	data, err := gd.ToJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if envelope["_version"] != float64(JSONVersion) {
+		t.Errorf("Expected _version %d, got %v", JSONVersion, envelope["_version"])
+	}
+
+	restored, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Nodes[0].Labels, []string{"Person", "Employee"}) {
+		t.Errorf("Expected labels to round-trip, got %v", restored.Nodes[0].Labels)
+	}
+	if restored.Nodes[1].Labels != nil {
+		t.Errorf("Expected current version nodes not to be migrated, got %v", restored.Nodes[1].Labels)
+	}
+	if restored.GetNodeCount() != 3 || restored.GetRelationshipCount() != 3 {
+		t.Errorf("Expected 3 nodes and 3 relationships, got %d and %d",
+			restored.GetNodeCount(), restored.GetRelationshipCount())
+	}
+	if rel := restored.FindRelationship("alice", "bob", "KNOWS"); rel == nil || rel.Properties["since"] != "2020" {
+		t.Errorf("Expected KNOWS relationship with properties to survive, got %+v", rel)
+	}
+}
+
+func TestToJSONLiteOmitsPageContent(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.Source.Metadata["id"] = "doc-1"
+
+	data, err := gd.ToJSONLite()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.Contains(data, []byte(gd.Source.PageContent)) || bytes.Contains(data, []byte("PageContent")) {
+		t.Errorf("Expected the lite form to omit page content, got %s", data)
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := envelope["source"]; ok {
+		t.Errorf("Expected no embedded source, got %v", envelope["source"])
+	}
+	ref := envelope["source_ref"].(map[string]interface{})
+	if ref["id"] != "doc-1" {
+		t.Errorf("Expected reference id doc-1, got %v", ref["id"])
+	}
+}
+
+func TestSourceRefHashesContentWithoutID(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	ref := gd.SourceRef()
+	if ref.ID != fmt.Sprintf("%x", md5.Sum([]byte(gd.Source.PageContent))) {
+		t.Errorf("Expected the content hash as reference id, got %s", ref.ID)
+	}
+	if ref.Metadata["source"] != "test" {
+		t.Errorf("Expected the metadata to be referenced, got %v", ref.Metadata)
+	}
+}
+
+func TestFromJSONLiteRoundTrip(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.Source.Metadata["id"] = "doc-1"
+
+	data, err := gd.ToJSONLite()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Without a resolver only the reference survives
+	restored, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored.Source.PageContent != "" || restored.Source.Metadata["id"] != "doc-1" || restored.Source.Metadata["source"] != "test" {
+		t.Errorf("Expected the referenced metadata, got %+v", restored.Source)
+	}
+	if restored.GetNodeCount() != 3 || restored.GetRelationshipCount() != 3 {
+		t.Errorf("Expected 3 nodes and 3 relationships, got %d and %d",
+			restored.GetNodeCount(), restored.GetRelationshipCount())
+	}
+	if again, _ := restored.ToJSONLite(); !bytes.Equal(again, data) {
+		t.Errorf("Expected the reference to round-trip, got %s want %s", again, data)
+	}
+
+	// A resolver rehydrates the full source
+	var resolved SourceReference
+	restored, err = FromJSON(data, WithSourceResolver(func(ref SourceReference) (schema.Document, error) {
+		resolved = ref
+		return gd.Source, nil
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolved.ID != "doc-1" {
+		t.Errorf("Expected the resolver to receive doc-1, got %s", resolved.ID)
+	}
+	if restored.Source.PageContent != gd.Source.PageContent {
+		t.Errorf("Expected the page content to be rehydrated, got %q", restored.Source.PageContent)
+	}
+}
+
+func TestFromJSONLiteResolverError(t *testing.T) {
+	gd := newTestGraphDocument()
+	data, err := gd.ToJSONLite()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	errMissing := errors.New("missing")
+	_, err = FromJSON(data, WithSourceResolver(func(SourceReference) (schema.Document, error) {
+		return schema.Document{}, errMissing
+	}))
+	if !errors.Is(err, errMissing) {
+		t.Errorf("Expected the resolver error, got %v", err)
+	}
+}
+
+func TestFromJSONMigratesVersion1(t *testing.T) {
+	v1 := `{
+		"nodes": [
+			{"id": "alice", "type": "Person", "properties": {"name": "Alice"}},
+			{"id": "acme", "type": "Company", "properties": null}
+		],
+		"relationships": [
+			{
+				"source": {"id": "alice", "type": "Person", "properties": null},
+				"target": {"id": "acme", "type": "Company", "properties": null},
+				"type": "WORKS_AT",
+				"properties": {"since": "2020"}
+			}
+		],
+		"source": {"PageContent": "Alice works at Acme.", "Metadata": null, "Score": 0}
+	}`
+
+	gd, err := FromJSON([]byte(v1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	alice := gd.FindNode("alice")
+	if alice == nil || alice.Type != "Person" || alice.Properties["name"] != "Alice" {
+		t.Fatalf("Unexpected node %+v", alice)
+	}
+	if !reflect.DeepEqual(alice.Labels, []string{"Person"}) {
+		t.Errorf("Expected type promoted into labels, got %v", alice.Labels)
+	}
+	rel := gd.FindRelationship("alice", "acme", "WORKS_AT")
+	if rel == nil || !reflect.DeepEqual(rel.Target.Labels, []string{"Company"}) {
+		t.Errorf("Expected relationship endpoints to be migrated, got %+v", rel)
+	}
+	if gd.Source.PageContent != "Alice works at Acme." {
+		t.Errorf("Unexpected source %+v", gd.Source)
+	}
+}
+
+func TestFromJSONRejectsUnknownVersion(t *testing.T) {
+	for _, data := range []string{`{"_version": 99, "nodes": []}`, `{"_version": 0}`} {
+		if _, err := FromJSON([]byte(data)); !errors.Is(err, ErrUnsupportedJSONVersion) {
+			t.Errorf("Expected ErrUnsupportedJSONVersion for %s, got %v", data, err)
+		}
+	}
+}
+
+func TestReadCompressedRejectsCorruptData(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	var buf bytes.Buffer
+	if err := gd.WriteCompressed(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+
+	corrupt := append([]byte{}, data...)
+	corrupt[len(corrupt)/2] ^= 0xFF
+
+	badVersion := append([]byte{}, data...)
+	badVersion[len(compressedMagic)] = 99
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated header", data[:2]},
+		{"bad magic", append([]byte("XXXX"), data[len(compressedMagic):]...)},
+		{"bad version", badVersion},
+		{"truncated body", data[:len(data)-10]},
+		{"corrupt body", corrupt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCompressed(bytes.NewReader(tt.data))
+			if !errors.Is(err, ErrInvalidCompressedData) {
+				t.Errorf("Expected ErrInvalidCompressedData, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyNodeDefaults(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	gd.ApplyNodeDefaults(map[string]map[string]interface{}{
+		"Person":  {"name": "Unknown", "verified": false},
+		"Company": {"industry": "unspecified"},
+	})
+
+	alice := gd.FindNode("alice")
+	if alice.Properties["name"] != "Alice" {
+		t.Errorf("Expected existing name to be kept, got %v", alice.Properties["name"])
+	}
+	if alice.Properties["verified"] != false {
+		t.Errorf("Expected missing verified to be filled, got %v", alice.Properties["verified"])
+	}
+	if alice.HasProperty("industry") {
+		t.Error("Expected defaults of other types not to be applied")
+	}
+
+	acme := gd.FindNode("acme")
+	if acme.Properties["industry"] != "unspecified" {
+		t.Errorf("Expected missing industry to be filled, got %v", acme.Properties["industry"])
+	}
+}
+
+func TestApplyRelationshipDefaults(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	gd.ApplyRelationshipDefaults(map[string]map[string]interface{}{
+		"KNOWS": {"since": "unknown", "weight": 1},
+	})
+
+	knows := gd.FindRelationship("alice", "bob", "KNOWS")
+	if knows.Properties["since"] != "2020" {
+		t.Errorf("Expected existing since to be kept, got %v", knows.Properties["since"])
+	}
+	if knows.Properties["weight"] != 1 {
+		t.Errorf("Expected missing weight to be filled, got %v", knows.Properties["weight"])
+	}
+
+	worksAt := gd.FindRelationship("alice", "acme", "WORKS_AT")
+	if len(worksAt.Properties) != 0 {
+		t.Errorf("Expected WORKS_AT to be untouched, got %v", worksAt.Properties)
+	}
+}
+
+func TestUnionDocuments(t *testing.T) {
+	alice := NewNode("alice", "Person")
+	alice.SetProperty("name", "Alice")
+	bob := NewNode("bob", "Person")
+	acme := NewNode("acme", "Company")
+
+	first := NewGraphDocument(schema.Document{PageContent: "chunk 1"})
+	first.AddNode(alice)
+	first.AddNode(bob)
+	first.AddRelationship(NewRelationship(alice, bob, "KNOWS"))
+
+	aliceAgain := NewNode("alice", "Person")
+	aliceAgain.SetProperty("name", "Alicia")
+	aliceAgain.SetProperty("age", 30)
+	second := NewGraphDocument(schema.Document{PageContent: "chunk 2"})
+	second.AddNode(aliceAgain)
+	second.AddNode(acme)
+	second.AddRelationship(NewRelationship(aliceAgain, acme, "WORKS_AT"))
+
+	knows := NewRelationship(alice, bob, "KNOWS")
+	knows.SetProperty("since", "2020")
+	third := NewGraphDocument(schema.Document{PageContent: "chunk 3"})
+	third.AddNode(bob)
+	third.AddNode(acme)
+	third.AddRelationship(knows)
+
+	source := schema.Document{PageContent: "chunk 1 chunk 2 chunk 3"}
+	union := UnionDocuments([]*GraphDocument{&first, &second, nil, &third}, source)
+
+	if union.Source.PageContent != source.PageContent {
+		t.Errorf("Expected combined source, got %q", union.Source.PageContent)
+	}
+	if union.GetNodeCount() != 3 {
+		t.Errorf("Expected 3 nodes, got %d", union.GetNodeCount())
+	}
+	if union.GetRelationshipCount() != 2 {
+		t.Errorf("Expected 2 relationships, got %d", union.GetRelationshipCount())
+	}
+
+	node := union.FindNode("alice")
+	if node.Properties["name"] != "Alice" || node.Properties["age"] != 30 {
+		t.Errorf("Expected unioned properties keeping the first value, got %v", node.Properties)
+	}
+
+	rel := union.FindRelationship("alice", "bob", "KNOWS")
+	if rel == nil || rel.Properties["since"] != "2020" {
+		t.Errorf("Expected unioned relationship properties, got %+v", rel)
+	}
+
+	// The inputs must not be modified through the union
+	union.FindNode("bob").SetProperty("name", "Bob")
+	if first.FindNode("bob").HasProperty("name") {
+		t.Error("Expected the union to hold copies of the input nodes")
+	}
+}
+
+func TestContractNode(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	alice := NewNode("alice", "Person")
+	bob := NewNode("bob", "Person")
+	team := NewNode("team", "Team")
+	acme := NewNode("acme", "Company")
+	globex := NewNode("globex", "Company")
+	for _, node := range []Node{alice, bob, team, acme, globex} {
+		gd.AddNode(node)
+	}
+	gd.AddRelationship(NewRelationship(alice, team, "MEMBER_OF"))
+	gd.AddRelationship(NewRelationship(bob, team, "MEMBER_OF"))
+	gd.AddRelationship(NewRelationship(team, acme, "PART_OF"))
+	gd.AddRelationship(NewRelationship(team, globex, "PARTNERS_WITH"))
+	gd.AddRelationship(NewRelationship(team, team, "SUBTEAM_OF"))
+	gd.AddRelationship(NewRelationship(alice, bob, "KNOWS"))
+
+	if err := gd.ContractNode("team"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gd.NodeExists("team") || len(gd.FindRelationshipsByNode("team")) != 0 {
+		t.Fatal("Expected the contracted node and its relationships to be gone")
+	}
+	if gd.GetRelationshipCount() != 5 {
+		t.Errorf("Expected KNOWS and 4 reconnecting relationships, got %d", gd.GetRelationshipCount())
+	}
+
+	for _, want := range []struct{ source, target, relType string }{
+		{"alice", "acme", "MEMBER_OF_PART_OF"},
+		{"bob", "acme", "MEMBER_OF_PART_OF"},
+		{"alice", "globex", "MEMBER_OF_PARTNERS_WITH"},
+		{"bob", "globex", "MEMBER_OF_PARTNERS_WITH"},
+	} {
+		rel := gd.FindRelationship(want.source, want.target, want.relType)
+		if rel == nil {
+			t.Errorf("Expected %s-%s->%s", want.source, want.relType, want.target)
+			continue
+		}
+		if !reflect.DeepEqual(rel.Properties[ContractedViaProperty], []string{"team"}) {
+			t.Errorf("Expected trace [team], got %v", rel.Properties[ContractedViaProperty])
+		}
+	}
+	if gd.FindRelationship("alice", "bob", "KNOWS") == nil {
+		t.Error("Expected unrelated relationships to be kept")
+	}
+}
+
+func TestContractNodeFlattensChain(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	var chain []Node
+	for _, id := range []string{"root", "a", "b", "leaf"} {
+		node := NewNode(id, "Category")
+		chain = append(chain, node)
+		gd.AddNode(node)
+	}
+	for i := 0; i+1 < len(chain); i++ {
+		gd.AddRelationship(NewRelationship(chain[i], chain[i+1], "PARENT_OF"))
+	}
+
+	ancestor := func(in, out Relationship) string { return "ANCESTOR_OF" }
+	for _, id := range []string{"b", "a"} {
+		if err := gd.ContractNodeWith(id, ancestor); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if gd.GetNodeCount() != 2 || gd.GetRelationshipCount() != 1 {
+		t.Fatalf("Expected 2 nodes and 1 relationship, got %d and %d", gd.GetNodeCount(), gd.GetRelationshipCount())
+	}
+	rel := gd.FindRelationship("root", "leaf", "ANCESTOR_OF")
+	if rel == nil {
+		t.Fatalf("Expected root-ANCESTOR_OF->leaf, got %+v", gd.Relationships)
+	}
+	if !reflect.DeepEqual(rel.Properties[ContractedViaProperty], []string{"a", "b"}) {
+		t.Errorf("Expected trace [a b], got %v", rel.Properties[ContractedViaProperty])
+	}
+}
+
+func TestContractNodeNotFound(t *testing.T) {
+	gd := newTestGraphDocument()
+	if err := gd.ContractNode("missing"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if gd.GetRelationshipCount() != 3 {
+		t.Errorf("Expected the document to be unchanged, got %d relationships", gd.GetRelationshipCount())
+	}
+}
+
+func TestRelabelNodes(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.AddNode(NewNode("paris", "city"))
+
+	gd.RelabelNodes(map[string]string{"Company": "Organization", "city": "City"})
+
+	if node := gd.FindNode("acme"); node.Type != "Organization" {
+		t.Errorf("Expected acme to be relabelled, got %q", node.Type)
+	}
+	if node := gd.FindNode("paris"); node.Type != "City" {
+		t.Errorf("Expected paris to be relabelled, got %q", node.Type)
+	}
+	if node := gd.FindNode("alice"); node.Type != "Person" {
+		t.Errorf("Expected unmapped type to be unchanged, got %q", node.Type)
+	}
+
+	for _, rel := range gd.FindRelationshipsByType("WORKS_AT") {
+		if rel.Source.Type != "Person" || rel.Target.Type != "Organization" {
+			t.Errorf("Expected endpoint types Person->Organization, got %s->%s", rel.Source.Type, rel.Target.Type)
+		}
+	}
+}
+
+func TestReconcileEndpoints(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.AddRelationship(NewRelationship(NewNode("carol", "Person"), NewNode("acme", "Company"), "WORKS_AT"))
+
+	// Replace the node so the endpoints embedded in relationships go stale
+	updated := NewNode("alice", "Employee")
+	updated.SetProperty("name", "Alice Smith")
+	updated.SetProperty("title", "CTO")
+	gd.Nodes[0] = updated
+	gd.FindNode("acme").SetProperty("city", "Paris")
+
+	gd.ReconcileEndpoints()
+
+	for _, rel := range gd.FindRelationshipsByNode("alice") {
+		if rel.Source.Type != "Employee" || rel.Source.Properties["name"] != "Alice Smith" || rel.Source.Properties["title"] != "CTO" {
+			t.Errorf("Expected refreshed source on %s, got %+v", rel.Type, rel.Source)
+		}
+	}
+	for _, rel := range gd.FindRelationshipsByType("WORKS_AT") {
+		if rel.Target.Properties["city"] != "Paris" {
+			t.Errorf("Expected refreshed target on %s->%s, got %+v", rel.Source.ID, rel.Target.ID, rel.Target)
+		}
+	}
+
+	carol := gd.FindRelationship("carol", "acme", "WORKS_AT")
+	if carol == nil || carol.Source.Type != "Person" || len(carol.Source.Properties) != 0 {
+		t.Errorf("Expected an endpoint absent from Nodes to be left alone, got %+v", carol)
+	}
+}
+
+func TestRelationshipsBetweenTypes(t *testing.T) {
+	gd := newTestGraphDocument()
+	acme := NewNode("acme", "Company")
+	globex := NewNode("globex", "Company")
+	gd.AddNode(globex)
+	gd.AddRelationship(NewRelationship(acme, NewNode("alice", "Person"), "EMPLOYS"))
+	gd.AddRelationship(NewRelationship(acme, globex, "PARTNERS_WITH"))
+	// Endpoints given by ID only take their type from the node list
+	gd.AddRelationship(NewRelationship(Node{ID: "bob"}, Node{ID: "globex"}, "CONSULTS_FOR"))
+	// Endpoints missing from the node list keep their embedded type
+	gd.AddRelationship(NewRelationship(NewNode("carol", "Person"), acme, "WORKS_AT"))
+
+	describe := func(rels []Relationship) []string {
+		var names []string
+		for _, rel := range rels {
+			names = append(names, rel.Source.ID+"-"+rel.Type+"->"+rel.Target.ID)
+		}
+		return names
+	}
+
+	tests := []struct {
+		sourceType, targetType string
+		want                   []string
+	}{
+		{"Person", "Company", []string{"alice-WORKS_AT->acme", "bob-WORKS_AT->acme", "bob-CONSULTS_FOR->globex", "carol-WORKS_AT->acme"}},
+		{"Company", "Person", []string{"acme-EMPLOYS->alice"}},
+		{"Person", "Person", []string{"alice-KNOWS->bob"}},
+		{"Company", "Company", []string{"acme-PARTNERS_WITH->globex"}},
+		{"Person", "Place", nil},
+	}
+	for _, tt := range tests {
+		if got := describe(gd.RelationshipsBetweenTypes(tt.sourceType, tt.targetType)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RelationshipsBetweenTypes(%s, %s) = %v, want %v", tt.sourceType, tt.targetType, got, tt.want)
+		}
+	}
+}
+
+func TestRelabelRelationships(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	gd.RelabelRelationships(map[string]string{"WORKS_AT": "EMPLOYED_BY", "UNUSED": "OTHER"})
+
+	if len(gd.FindRelationshipsByType("WORKS_AT")) != 0 {
+		t.Error("Expected no WORKS_AT relationships to remain")
+	}
+	if len(gd.FindRelationshipsByType("EMPLOYED_BY")) != 2 {
+		t.Errorf("Expected 2 EMPLOYED_BY relationships, got %d", len(gd.FindRelationshipsByType("EMPLOYED_BY")))
+	}
+	if gd.FindRelationship("alice", "bob", "KNOWS") == nil {
+		t.Error("Expected unmapped relationship type to be unchanged")
+	}
+}
+
+func TestSplitBySize(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{PageContent: "large"})
+	for i := 0; i < 10; i++ {
+		gd.AddNode(NewNode(fmt.Sprintf("n%d", i), "Node"))
+	}
+	// A chain plus links back to the first node cross chunk boundaries
+	for i := 0; i < 9; i++ {
+		gd.AddRelationship(NewRelationship(NewNode(fmt.Sprintf("n%d", i), "Node"), NewNode(fmt.Sprintf("n%d", i+1), "Node"), "NEXT"))
+	}
+	gd.AddRelationship(NewRelationship(NewNode("n7", "Node"), NewNode("n0", "Node"), "BACK"))
+	gd.AddRelationship(NewRelationship(NewNode("n9", "Node"), NewNode("external", "Other"), "LINKS"))
+
+	chunks := gd.SplitBySize(4)
+	if len(chunks) < 3 {
+		t.Fatalf("Expected at least 3 chunks, got %d", len(chunks))
+	}
+
+	owners := make(map[string]int)
+	relationships := 0
+	for i, chunk := range chunks {
+		if chunk.GetNodeCount() > 4 {
+			t.Errorf("Chunk %d has %d nodes, expected at most 4", i, chunk.GetNodeCount())
+		}
+		if chunk.Source.PageContent != "large" {
+			t.Errorf("Chunk %d lost the document source", i)
+		}
+		for _, rel := range chunk.Relationships {
+			if !chunk.NodeExists(rel.Source.ID) || !chunk.NodeExists(rel.Target.ID) {
+				t.Errorf("Chunk %d has relationship %s->%s without both endpoints", i, rel.Source.ID, rel.Target.ID)
+			}
+			owners[rel.Source.ID]++
+		}
+		relationships += chunk.GetRelationshipCount()
+	}
+
+	if relationships != gd.GetRelationshipCount() {
+		t.Errorf("Expected every relationship exactly once, got %d of %d", relationships, gd.GetRelationshipCount())
+	}
+	for _, node := range gd.Nodes {
+		found := false
+		for _, chunk := range chunks {
+			if chunk.NodeExists(node.ID) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Node %s is missing from every chunk", node.ID)
+		}
+	}
+}
+
+func TestSplitBySizeNonPositive(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	chunks := gd.SplitBySize(0)
+	if len(chunks) != 1 || chunks[0].GetNodeCount() != 3 || chunks[0].GetRelationshipCount() != 3 {
+		t.Errorf("Expected a single full copy, got %d chunks", len(chunks))
+	}
+}
+
+func TestRemoveRelationshipsByType(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	if removed := gd.RemoveRelationshipsByType("WORKS_AT"); removed != 2 {
+		t.Errorf("Expected 2 relationships removed, got %d", removed)
+	}
+	if gd.GetRelationshipCount() != 1 || gd.FindRelationship("alice", "bob", "KNOWS") == nil {
+		t.Errorf("Expected only the KNOWS relationship to remain, got %+v", gd.Relationships)
+	}
+	if gd.GetNodeCount() != 3 {
+		t.Errorf("Expected all nodes to remain, got %d", gd.GetNodeCount())
+	}
+
+	if removed := gd.RemoveRelationshipsByType("MISSING"); removed != 0 {
+		t.Errorf("Expected nothing removed for an unknown type, got %d", removed)
+	}
+}
+
+func newEnrichmentDocument() GraphDocument {
+	gd := NewGraphDocument(schema.Document{})
+	alice := NewNode("alice", "Person")
+	alice.SetProperty("name", "Alice Smith")
+	alice.SetProperty("email", "alice@example.com")
+	alice.SetProperty("age", 30)
+	gd.AddNode(alice)
+	unknown := NewNode("zoe", "Person")
+	unknown.SetProperty("email", "zoe@example.com")
+	gd.AddNode(unknown)
+	return gd
+}
+
+func TestCopyPropertiesFromSelectedKeys(t *testing.T) {
+	gd := newTestGraphDocument()
+	enrichment := newEnrichmentDocument()
+
+	gd.CopyPropertiesFrom(&enrichment, []string{"email", "missing"})
+
+	alice := gd.FindNode("alice")
+	if alice.Properties["email"] != "alice@example.com" {
+		t.Errorf("Expected email to be copied, got %v", alice.Properties)
+	}
+	if alice.Properties["name"] != "Alice" || alice.HasProperty("age") || alice.HasProperty("missing") {
+		t.Errorf("Expected only the listed keys to be copied, got %v", alice.Properties)
+	}
+	if gd.NodeExists("zoe") {
+		t.Error("Expected nodes only in the other document not to be added")
+	}
+	if gd.FindNode("bob").HasProperty("email") {
+		t.Error("Expected nodes absent from the other document to be untouched")
+	}
+}
+
+func TestCopyPropertiesFromAllKeys(t *testing.T) {
+	gd := newTestGraphDocument()
+	enrichment := newEnrichmentDocument()
+
+	gd.CopyPropertiesFrom(&enrichment, nil)
+
+	alice := gd.FindNode("alice")
+	expected := map[string]interface{}{"name": "Alice Smith", "email": "alice@example.com", "age": 30}
+	for key, value := range expected {
+		if alice.Properties[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, alice.Properties[key])
+		}
+	}
+}
+
+func TestDropSelfLoops(t *testing.T) {
+	alice := NewNode("alice", "Person")
+	bob := NewNode("bob", "Person")
+	rels := []Relationship{
+		NewRelationship(alice, alice, "KNOWS"),
+		NewRelationship(alice, bob, "KNOWS"),
+		NewRelationship(bob, bob, "LIKES"),
+	}
+
+	kept := DropSelfLoops(rels)
+	if len(kept) != 1 || kept[0].Target.ID != "bob" {
+		t.Errorf("Expected only alice-KNOWS-bob to be kept, got %+v", kept)
+	}
+	if len(rels) != 3 {
+		t.Errorf("Expected the input to be left intact, got %d relationships", len(rels))
+	}
+}
@@ -98,8 +98,63 @@ type Options struct {
 	Limit int
 	// Offset specifies the number of results to skip
 	Offset int
+	// MergePropertiesFunc resolves conflicts between existing and incoming properties on upsert
+	MergePropertiesFunc MergePropertiesFunc
+	// ForceLabel is an additional label applied to every added node alongside its type
+	ForceLabel string
+	// Direction specifies which relationship direction to match, relative to the source node
+	Direction Direction
+	// AssumeEndpointsExist makes relationship imports match existing endpoints instead of merging them
+	AssumeEndpointsExist bool
+	// CreateMissingEndpoints makes AddRelationships merge endpoint nodes that do not exist
+	CreateMissingEndpoints bool
+	// DropSelfLoops filters out relationships whose source and target are the same node
+	DropSelfLoops bool
+	// Confirm allows destructive maintenance operations, such as clearing the database, to run
+	Confirm bool
+	// FullPathDepth is the maximum number of hops of the path returned when no direct relationship exists
+	FullPathDepth int
+	// SkipSanitize returns vector search results unsanitized even when the store sanitizes values
+	SkipSanitize bool
 }
 
+// WithSkipSanitize sets whether vector search results keep embedding-like values and
+// oversized lists that the store would otherwise strip when sanitization is enabled.
+// It only applies to vector searches. Raw query results are always sanitized when the
+// store sanitizes, while node and relationship getters return properties unsanitized;
+// stores may instead offer a server-side option leaving out large node properties.
+func WithSkipSanitize(skip bool) Option {
+	return func(opts *Options) {
+		opts.SkipSanitize = skip
+	}
+}
+
+// WithReturnFullPath makes relationship lookups fall back to a shortest path of at most
+// maxDepth relationships when the nodes are not directly related, returning the path's
+// relationships in order from the source. The path follows the requested direction and
+// relationship type.
+func WithReturnFullPath(maxDepth int) Option {
+	return func(opts *Options) {
+		opts.FullPathDepth = maxDepth
+	}
+}
+
+// Direction defines which relationship direction to match relative to the source node.
+type Direction int
+
+const (
+	// DirectionOutgoing matches relationships from the source to the target
+	DirectionOutgoing Direction = iota
+	// DirectionIncoming matches relationships from the target to the source
+	DirectionIncoming
+	// DirectionBoth matches relationships in either direction
+	DirectionBoth
+)
+
+// MergePropertiesFunc combines the properties already stored on an entity with
+// incoming properties and returns the properties to store.
+type MergePropertiesFunc func(existing, incoming map[string]interface{}) map[string]interface{}
+
 // MergeMode defines how to handle existing entities during operations.
 type MergeMode int
 
@@ -126,6 +181,7 @@ func NewOptions() *Options {
 		ExcludeProperties: nil,
 		Limit:             0, // No limit by default
 		Offset:            0,
+		Direction:         DirectionOutgoing,
 	}
 }
 
@@ -143,7 +199,8 @@ func WithBatchSize(size int) Option {
 	}
 }
 
-// WithTimeout sets the query timeout in milliseconds.
+// WithTimeout sets the timeout of the operation in milliseconds, overriding the
+// default timeout of the store for this call. Zero keeps the store default.
 func WithTimeout(timeout int) Option {
 	return func(opts *Options) {
 		opts.Timeout = timeout
@@ -165,6 +222,7 @@ func WithCascadeDelete(cascade bool) Option {
 }
 
 // WithIncludeProperties sets which properties to include in results.
+// A non-empty list is an allowlist and takes precedence over WithExcludeProperties.
 func WithIncludeProperties(properties []string) Option {
 	return func(opts *Options) {
 		opts.IncludeProperties = properties
@@ -191,3 +249,63 @@ func WithOffset(offset int) Option {
 		opts.Offset = offset
 	}
 }
+
+// WithMergePropertiesFunc sets how existing and incoming properties are combined on upsert.
+// The function receives the stored properties and replaces the default overwrite semantics.
+func WithMergePropertiesFunc(fn MergePropertiesFunc) Option {
+	return func(opts *Options) {
+		opts.MergePropertiesFunc = fn
+	}
+}
+
+// WithForceLabel sets an additional label applied to every added node.
+// Nodes keep their own type, so a heterogeneous batch can share a common grouping label.
+func WithForceLabel(label string) Option {
+	return func(opts *Options) {
+		opts.ForceLabel = label
+	}
+}
+
+// WithAssumeEndpointsExist sets whether relationship imports require their endpoints to exist.
+// When enabled, endpoints are matched instead of merged and a missing endpoint fails the import
+// rather than creating an empty stub node.
+// Without the base entity label, endpoints are matched by id across all labels, which
+// cannot use an index.
+func WithAssumeEndpointsExist(assume bool) Option {
+	return func(opts *Options) {
+		opts.AssumeEndpointsExist = assume
+	}
+}
+
+// WithCreateMissingEndpoints sets whether AddRelationships creates missing endpoints. When
+// enabled, endpoint nodes that do not exist are created from the relationship's Source and
+// Target, labeled with their type; otherwise relationships with a missing endpoint are
+// skipped and reported in the returned error.
+func WithCreateMissingEndpoints(create bool) Option {
+	return func(opts *Options) {
+		opts.CreateMissingEndpoints = create
+	}
+}
+
+// WithDropSelfLoops sets whether self-referential relationships, whose Source and Target
+// share an ID, are dropped by imports and AddRelationships. Self-loops are kept by default.
+func WithDropSelfLoops(drop bool) Option {
+	return func(opts *Options) {
+		opts.DropSelfLoops = drop
+	}
+}
+
+// WithConfirm confirms a destructive maintenance operation, such as clearing the
+// database. Such operations refuse to run unless confirmed.
+func WithConfirm(confirm bool) Option {
+	return func(opts *Options) {
+		opts.Confirm = confirm
+	}
+}
+
+// WithDirection sets which relationship direction to match relative to the source node.
+func WithDirection(direction Direction) Option {
+	return func(opts *Options) {
+		opts.Direction = direction
+	}
+}
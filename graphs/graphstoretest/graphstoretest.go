@@ -0,0 +1,371 @@
+// Package graphstoretest provides a conformance test suite for implementations of the
+// graphs.GraphStore interface.
+package graphstoretest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// NewStore returns an empty store for a test. Implementations backed by a database
+// should clear it, and may register cleanup with t.Cleanup.
+type NewStore func(t *testing.T) graphs.GraphStore
+
+// Run runs the conformance suite against the stores returned by newStore, each subtest
+// getting a new store. Only behavior shared by every implementation is checked, so
+// stores may keep extra properties, such as the node ID, and return unordered results.
+func Run(t *testing.T, newStore NewStore) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store graphs.GraphStore)
+	}{
+		{"AddNodes", testAddNodes},
+		{"AddRelationships", testAddRelationships},
+		{"AddRelationshipsMissingEndpoint", testAddRelationshipsMissingEndpoint},
+		{"AddGraphDocument", testAddGraphDocument},
+		{"UpdateNode", testUpdateNode},
+		{"UpdateRelationship", testUpdateRelationship},
+		{"GetRelationshipsDirection", testGetRelationshipsDirection},
+		{"GetByType", testGetByType},
+		{"RemoveRelationships", testRemoveRelationships},
+		{"RemoveNodes", testRemoveNodes},
+		{"Schema", testSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+// person returns a Person node with a name property
+func person(id, name string) graphs.Node {
+	node := graphs.NewNode(id, "Person")
+	node.SetProperty("name", name)
+	return node
+}
+
+// seed adds alice, bob and acme, with alice knowing bob and both working at acme
+func seed(t *testing.T, store graphs.GraphStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	acme := graphs.NewNode("acme", "Company")
+	acme.SetProperty("name", "Acme")
+	nodes := []graphs.Node{person("alice", "Alice"), person("bob", "Bob"), acme}
+	if err := store.AddNodes(ctx, nodes); err != nil {
+		t.Fatalf("AddNodes: %v", err)
+	}
+
+	knows := graphs.NewRelationship(nodes[0], nodes[1], "KNOWS")
+	knows.SetProperty("since", "2020")
+	relationships := []graphs.Relationship{
+		knows,
+		graphs.NewRelationship(nodes[0], acme, "WORKS_AT"),
+		graphs.NewRelationship(nodes[1], acme, "WORKS_AT"),
+	}
+	if err := store.AddRelationships(ctx, relationships); err != nil {
+		t.Fatalf("AddRelationships: %v", err)
+	}
+}
+
+// nodeIDs returns the sorted IDs of nodes
+func nodeIDs(nodes []graphs.Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// expectIDs fails the test unless the sorted ids are expected
+func expectIDs(t *testing.T, what string, ids []string, expected ...string) {
+	t.Helper()
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %s %v, got %v", what, expected, ids)
+	}
+}
+
+// expectNodeExists fails the test unless the existence of the node is expected
+func expectNodeExists(t *testing.T, store graphs.GraphStore, nodeID string, expected bool) {
+	t.Helper()
+	exists, err := store.NodeExists(context.Background(), nodeID)
+	if err != nil {
+		t.Fatalf("NodeExists: %v", err)
+	}
+	if exists != expected {
+		t.Errorf("Expected node %s to exist: %v, got %v", nodeID, expected, exists)
+	}
+}
+
+// expectRelationshipExists fails the test unless the existence of the relationship is expected
+func expectRelationshipExists(t *testing.T, store graphs.GraphStore, sourceID, targetID, relType string, expected bool) {
+	t.Helper()
+	exists, err := store.RelationshipExists(context.Background(), sourceID, targetID, relType)
+	if err != nil {
+		t.Fatalf("RelationshipExists: %v", err)
+	}
+	if exists != expected {
+		t.Errorf("Expected relationship %s-[%s]->%s to exist: %v, got %v", sourceID, relType, targetID, expected, exists)
+	}
+}
+
+func testAddNodes(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	node, err := store.GetNode(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	if node.ID != "alice" || node.Type != "Person" || node.Properties["name"] != "Alice" {
+		t.Errorf("Unexpected node %+v", node)
+	}
+	if _, err := store.GetNode(ctx, "ghost"); err == nil {
+		t.Error("Expected an error for a missing node")
+	}
+
+	nodes, err := store.GetNodes(ctx, []string{"alice", "ghost", "acme"})
+	if err != nil {
+		t.Fatalf("GetNodes: %v", err)
+	}
+	expectIDs(t, "nodes", nodeIDs(nodes), "acme", "alice")
+
+	expectNodeExists(t, store, "bob", true)
+	expectNodeExists(t, store, "ghost", false)
+}
+
+func testAddRelationships(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	expectRelationshipExists(t, store, "alice", "bob", "KNOWS", true)
+	expectRelationshipExists(t, store, "bob", "alice", "KNOWS", false)
+	expectRelationshipExists(t, store, "alice", "bob", "WORKS_AT", false)
+
+	rels, err := store.GetRelationships(ctx, "alice", "bob", "KNOWS")
+	if err != nil {
+		t.Fatalf("GetRelationships: %v", err)
+	}
+	if len(rels) != 1 {
+		t.Fatalf("Expected 1 relationship, got %d", len(rels))
+	}
+	rel := rels[0]
+	if rel.Source.ID != "alice" || rel.Target.ID != "bob" || rel.Type != "KNOWS" || rel.Properties["since"] != "2020" {
+		t.Errorf("Unexpected relationship %+v", rel)
+	}
+	if rel.Source.Type != "Person" || rel.Target.Properties["name"] != "Bob" {
+		t.Errorf("Expected the endpoints to be the stored nodes, got %+v and %+v", rel.Source, rel.Target)
+	}
+}
+
+func testAddRelationshipsMissingEndpoint(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	rel := graphs.NewRelationship(person("alice", "Alice"), person("ghost", "Ghost"), "KNOWS")
+	if err := store.AddRelationships(ctx, []graphs.Relationship{rel}); err == nil {
+		t.Error("Expected an error for a missing endpoint")
+	}
+	expectNodeExists(t, store, "ghost", false)
+	expectRelationshipExists(t, store, "alice", "ghost", "KNOWS", false)
+
+	if err := store.AddRelationships(ctx, []graphs.Relationship{rel}, graphs.WithCreateMissingEndpoints(true)); err != nil {
+		t.Fatalf("AddRelationships: %v", err)
+	}
+	expectNodeExists(t, store, "ghost", true)
+	expectRelationshipExists(t, store, "alice", "ghost", "KNOWS", true)
+}
+
+func testAddGraphDocument(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+
+	doc := graphs.NewGraphDocument(schema.Document{PageContent: "Alice works at Acme."})
+	alice := person("alice", "Alice")
+	acme := graphs.NewNode("acme", "Company")
+	doc.AddNode(alice)
+	doc.AddNode(acme)
+	doc.AddRelationship(graphs.NewRelationship(alice, acme, "WORKS_AT"))
+	doc.AddRelationship(graphs.NewRelationship(alice, alice, "KNOWS"))
+
+	if err := store.AddGraphDocument(ctx, []graphs.GraphDocument{doc}, graphs.WithDropSelfLoops(true)); err != nil {
+		t.Fatalf("AddGraphDocument: %v", err)
+	}
+
+	expectNodeExists(t, store, "alice", true)
+	expectNodeExists(t, store, "acme", true)
+	expectRelationshipExists(t, store, "alice", "acme", "WORKS_AT", true)
+	expectRelationshipExists(t, store, "alice", "alice", "KNOWS", false)
+}
+
+func testUpdateNode(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	if err := store.UpdateNode(ctx, "alice", map[string]interface{}{"city": "Paris"}); err != nil {
+		t.Fatalf("UpdateNode: %v", err)
+	}
+	node, err := store.GetNode(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	if node.Properties["city"] != "Paris" || node.Properties["name"] != "Alice" {
+		t.Errorf("Expected the property to be added to the others, got %v", node.Properties)
+	}
+
+	if err := store.UpdateNode(ctx, "ghost", map[string]interface{}{"city": "Paris"}); err == nil {
+		t.Error("Expected an error for a missing node")
+	}
+}
+
+func testUpdateRelationship(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	if err := store.UpdateRelationship(ctx, "alice", "bob", "KNOWS", map[string]interface{}{"close": "yes"}); err != nil {
+		t.Fatalf("UpdateRelationship: %v", err)
+	}
+	rels, err := store.GetRelationships(ctx, "alice", "bob", "KNOWS")
+	if err != nil {
+		t.Fatalf("GetRelationships: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Properties["close"] != "yes" || rels[0].Properties["since"] != "2020" {
+		t.Errorf("Expected the property to be added to the others, got %+v", rels)
+	}
+
+	if err := store.UpdateRelationship(ctx, "bob", "alice", "KNOWS", map[string]interface{}{"close": "yes"}); err == nil {
+		t.Error("Expected an error for a missing relationship")
+	}
+}
+
+func testGetRelationshipsDirection(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	tests := []struct {
+		direction graphs.Direction
+		expected  int
+	}{
+		{graphs.DirectionOutgoing, 0},
+		{graphs.DirectionIncoming, 1},
+		{graphs.DirectionBoth, 1},
+	}
+	for _, tt := range tests {
+		rels, err := store.GetRelationships(ctx, "bob", "alice", "", graphs.WithDirection(tt.direction))
+		if err != nil {
+			t.Fatalf("GetRelationships: %v", err)
+		}
+		if len(rels) != tt.expected {
+			t.Errorf("Expected %d relationships from bob to alice in direction %d, got %d", tt.expected, tt.direction, len(rels))
+		}
+		for _, rel := range rels {
+			if rel.Source.ID != "alice" || rel.Target.ID != "bob" {
+				t.Errorf("Expected the stored orientation, got %s->%s", rel.Source.ID, rel.Target.ID)
+			}
+		}
+	}
+}
+
+func testGetByType(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	people, err := store.GetNodesByType(ctx, "Person")
+	if err != nil {
+		t.Fatalf("GetNodesByType: %v", err)
+	}
+	expectIDs(t, "people", nodeIDs(people), "alice", "bob")
+
+	page, err := store.GetNodesByType(ctx, "Person", graphs.WithLimit(1), graphs.WithOffset(1))
+	if err != nil {
+		t.Fatalf("GetNodesByType: %v", err)
+	}
+	if len(page) != 1 {
+		t.Errorf("Expected 1 node in the page, got %d", len(page))
+	}
+
+	rels, err := store.GetRelationshipsByType(ctx, "WORKS_AT")
+	if err != nil {
+		t.Fatalf("GetRelationshipsByType: %v", err)
+	}
+	var sources []string
+	for _, rel := range rels {
+		if rel.Type != "WORKS_AT" || rel.Target.ID != "acme" {
+			t.Errorf("Unexpected relationship %+v", rel)
+		}
+		sources = append(sources, rel.Source.ID)
+	}
+	sort.Strings(sources)
+	expectIDs(t, "sources", sources, "alice", "bob")
+}
+
+func testRemoveRelationships(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	if err := store.RemoveRelationship(ctx, "alice", "bob", "KNOWS"); err != nil {
+		t.Fatalf("RemoveRelationship: %v", err)
+	}
+	expectRelationshipExists(t, store, "alice", "bob", "KNOWS", false)
+
+	ids := []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"},
+		{SourceID: "bob", TargetID: "acme", Type: "WORKS_AT"},
+	}
+	if err := store.RemoveRelationships(ctx, ids); err != nil {
+		t.Fatalf("RemoveRelationships: %v", err)
+	}
+	for _, id := range ids {
+		expectRelationshipExists(t, store, id.SourceID, id.TargetID, id.Type, false)
+	}
+	expectNodeExists(t, store, "acme", true)
+}
+
+func testRemoveNodes(t *testing.T, store graphs.GraphStore) {
+	ctx := context.Background()
+	seed(t, store)
+
+	// Connected nodes are only removed with their relationships
+	if err := store.RemoveNode(ctx, "bob"); err != nil {
+		t.Fatalf("RemoveNode: %v", err)
+	}
+	expectNodeExists(t, store, "bob", true)
+
+	if err := store.RemoveNode(ctx, "bob", graphs.WithCascadeDelete(true)); err != nil {
+		t.Fatalf("RemoveNode: %v", err)
+	}
+	expectNodeExists(t, store, "bob", false)
+	expectRelationshipExists(t, store, "alice", "bob", "KNOWS", false)
+	expectRelationshipExists(t, store, "alice", "acme", "WORKS_AT", true)
+
+	if err := store.RemoveNodes(ctx, []string{"alice", "acme", "ghost"}, graphs.WithCascadeDelete(true)); err != nil {
+		t.Fatalf("RemoveNodes: %v", err)
+	}
+	expectNodeExists(t, store, "alice", false)
+	expectNodeExists(t, store, "acme", false)
+}
+
+func testSchema(t *testing.T, store graphs.GraphStore) {
+	seed(t, store)
+
+	if err := store.RefreshSchema(context.Background()); err != nil {
+		t.Fatalf("RefreshSchema: %v", err)
+	}
+	text := store.GetSchema()
+	for _, want := range []string{"Person", "Company", "(:Person)-[:KNOWS]->(:Person)", "(:Person)-[:WORKS_AT]->(:Company)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the schema to mention %s, got:\n%s", want, text)
+		}
+	}
+	if _, ok := store.GetStructuredSchema()["node_props"]; !ok {
+		t.Errorf("Expected node properties in the structured schema, got %v", store.GetStructuredSchema())
+	}
+}
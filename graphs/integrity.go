@@ -0,0 +1,177 @@
+package graphs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidGraphDocument is wrapped by the errors Validate reports
+var ErrInvalidGraphDocument = errors.New("invalid graph document")
+
+// IntegrityIssueKind categorizes a structural problem in a GraphDocument.
+type IntegrityIssueKind string
+
+const (
+	// IssueEmptyNodeID marks a node without an ID
+	IssueEmptyNodeID IntegrityIssueKind = "empty_node_id"
+	// IssueEmptyNodeType marks a node without a type
+	IssueEmptyNodeType IntegrityIssueKind = "empty_node_type"
+	// IssueDuplicateNodeID marks a node ID used by more than one node
+	IssueDuplicateNodeID IntegrityIssueKind = "duplicate_node_id"
+	// IssueEmptyRelationshipType marks a relationship without a type
+	IssueEmptyRelationshipType IntegrityIssueKind = "empty_relationship_type"
+	// IssueNilProperties marks a node or relationship whose properties map is nil
+	IssueNilProperties IntegrityIssueKind = "nil_properties"
+	// IssueMissingEndpoint marks a relationship referencing a node absent from the node list
+	IssueMissingEndpoint IntegrityIssueKind = "missing_endpoint"
+	// IssueDuplicateRelationship marks a repeated relationship with the same properties as
+	// an earlier one, which can safely be dropped
+	IssueDuplicateRelationship IntegrityIssueKind = "duplicate_relationship"
+	// IssueConflictingRelationship marks a repeated relationship whose properties differ
+	// from an earlier one, which needs reconciling before it is dropped
+	IssueConflictingRelationship IntegrityIssueKind = "conflicting_relationship"
+)
+
+// IntegrityIssue describes a single structural problem found in a GraphDocument.
+type IntegrityIssue struct {
+	// Kind is the category of the issue
+	Kind IntegrityIssueKind `json:"kind"`
+	// NodeID is the ID of the node involved, if any
+	NodeID string `json:"node_id,omitempty"`
+	// Relationship identifies the relationship involved, if any
+	Relationship *RelationshipIdentifier `json:"relationship,omitempty"`
+	// Message is a human-readable description of the issue
+	Message string `json:"message"`
+}
+
+// NodesWithoutType returns all nodes with an empty type
+func (gd *GraphDocument) NodesWithoutType() []Node {
+	var nodes []Node
+	for _, node := range gd.Nodes {
+		if node.Type == "" {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// IntegrityIssues reports every structural problem in the GraphDocument without failing.
+// Repeated relationships are reported as duplicates when their properties match the first
+// occurrence and as conflicts otherwise.
+// Issues are listed in document order, nodes first and relationships second.
+func (gd *GraphDocument) IntegrityIssues() []IntegrityIssue {
+	var issues []IntegrityIssue
+
+	seen := make(map[string]int)
+	for i, node := range gd.Nodes {
+		if node.ID == "" {
+			issues = append(issues, IntegrityIssue{
+				Kind:    IssueEmptyNodeID,
+				Message: fmt.Sprintf("node at index %d has an empty id", i),
+			})
+		}
+		if node.Type == "" {
+			issues = append(issues, IntegrityIssue{
+				Kind:    IssueEmptyNodeType,
+				NodeID:  node.ID,
+				Message: fmt.Sprintf("node %q has an empty type", node.ID),
+			})
+		}
+
+		if node.Properties == nil {
+			issues = append(issues, IntegrityIssue{
+				Kind:    IssueNilProperties,
+				NodeID:  node.ID,
+				Message: fmt.Sprintf("node %q has nil properties", node.ID),
+			})
+		}
+
+		seen[node.ID]++
+		if node.ID != "" && seen[node.ID] == 2 {
+			issues = append(issues, IntegrityIssue{
+				Kind:    IssueDuplicateNodeID,
+				NodeID:  node.ID,
+				Message: fmt.Sprintf("node id %q is used by more than one node", node.ID),
+			})
+		}
+	}
+
+	first := make(map[RelationshipIdentifier]int)
+	for i, rel := range gd.Relationships {
+		identifier := rel.GetIdentifier()
+
+		if j, ok := first[identifier]; !ok {
+			first[identifier] = i
+		} else if sameProperties(gd.Relationships[j].Properties, rel.Properties) {
+			issues = append(issues, IntegrityIssue{
+				Kind:         IssueDuplicateRelationship,
+				Relationship: &identifier,
+				Message: fmt.Sprintf("relationship %q-%s->%q at index %d duplicates the one at index %d",
+					rel.Source.ID, rel.Type, rel.Target.ID, i, j),
+			})
+		} else {
+			issues = append(issues, IntegrityIssue{
+				Kind:         IssueConflictingRelationship,
+				Relationship: &identifier,
+				Message: fmt.Sprintf("relationship %q-%s->%q at index %d has different properties than the one at index %d",
+					rel.Source.ID, rel.Type, rel.Target.ID, i, j),
+			})
+		}
+
+		if rel.Type == "" {
+			issues = append(issues, IntegrityIssue{
+				Kind:         IssueEmptyRelationshipType,
+				Relationship: &identifier,
+				Message:      fmt.Sprintf("relationship %q->%q has an empty type", rel.Source.ID, rel.Target.ID),
+			})
+		}
+
+		if rel.Properties == nil {
+			issues = append(issues, IntegrityIssue{
+				Kind:         IssueNilProperties,
+				Relationship: &identifier,
+				Message:      fmt.Sprintf("relationship %q-%s->%q has nil properties", rel.Source.ID, rel.Type, rel.Target.ID),
+			})
+		}
+
+		for _, endpoint := range []string{rel.Source.ID, rel.Target.ID} {
+			if seen[endpoint] == 0 {
+				issues = append(issues, IntegrityIssue{
+					Kind:         IssueMissingEndpoint,
+					NodeID:       endpoint,
+					Relationship: &identifier,
+					Message: fmt.Sprintf("relationship %q-%s->%q references missing node %q",
+						rel.Source.ID, rel.Type, rel.Target.ID, endpoint),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// Validate reports the structural problems that should be fixed before the GraphDocument
+// is imported: empty or duplicate node IDs, empty node or relationship types, nil properties
+// maps and relationships referencing nodes absent from Nodes, which a store would otherwise
+// create as placeholders. Each error wraps ErrInvalidGraphDocument; nil means the document
+// is valid. Repeated relationships are merged on import and are not reported.
+func (gd *GraphDocument) Validate() []error {
+	var errs []error
+	for _, issue := range gd.IntegrityIssues() {
+		switch issue.Kind {
+		case IssueDuplicateRelationship, IssueConflictingRelationship:
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidGraphDocument, issue.Message))
+	}
+	return errs
+}
+
+// sameProperties reports whether two property maps hold the same values, treating nil as empty
+func sameProperties(a, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
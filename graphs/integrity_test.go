@@ -0,0 +1,186 @@
+package graphs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestIntegrityIssues(t *testing.T) {
+	gd := NewGraphDocument(schema.Document{})
+	gd.AddNode(NewNode("alice", "Person"))
+	gd.AddNode(NewNode("alice", "Person"))
+	gd.AddNode(NewNode("untyped", ""))
+	gd.AddNode(NewNode("", "Person"))
+	gd.AddRelationship(NewRelationship(NewNode("alice", "Person"), NewNode("untyped", ""), ""))
+	gd.AddRelationship(NewRelationship(NewNode("alice", "Person"), NewNode("ghost", "Person"), "KNOWS"))
+
+	issues := gd.IntegrityIssues()
+
+	counts := make(map[IntegrityIssueKind]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+		if issue.Message == "" {
+			t.Errorf("Expected a message for issue %+v", issue)
+		}
+	}
+
+	expected := map[IntegrityIssueKind]int{
+		IssueEmptyNodeID:           1,
+		IssueEmptyNodeType:         1,
+		IssueDuplicateNodeID:       1,
+		IssueEmptyRelationshipType: 1,
+		IssueMissingEndpoint:       1,
+	}
+	for kind, count := range expected {
+		if counts[kind] != count {
+			t.Errorf("Expected %d %s issues, got %d", count, kind, counts[kind])
+		}
+	}
+	if len(issues) != 5 {
+		t.Errorf("Expected 5 issues, got %d: %+v", len(issues), issues)
+	}
+
+	for _, issue := range issues {
+		if issue.Kind == IssueMissingEndpoint {
+			if issue.NodeID != "ghost" || issue.Relationship == nil || issue.Relationship.Type != "KNOWS" {
+				t.Errorf("Unexpected missing endpoint issue: %+v", issue)
+			}
+		}
+	}
+}
+
+func TestIntegrityIssuesCleanDocument(t *testing.T) {
+	gd := newTestGraphDocument()
+	if issues := gd.IntegrityIssues(); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+}
+
+func TestNodesWithoutType(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.AddNode(NewNode("mystery", ""))
+
+	nodes := gd.NodesWithoutType()
+	if len(nodes) != 1 || nodes[0].ID != "mystery" {
+		t.Errorf("Expected only the untyped node, got %+v", nodes)
+	}
+}
+
+func TestIntegrityIssuesDuplicateRelationships(t *testing.T) {
+	gd := newTestGraphDocument()
+
+	// Same identifier and properties as the existing alice-KNOWS->bob
+	exact := NewRelationship(NewNode("alice", "Person"), NewNode("bob", "Person"), "KNOWS")
+	exact.SetProperty("since", "2020")
+	gd.AddRelationship(exact)
+
+	// Same identifier, different properties
+	conflicting := NewRelationship(NewNode("alice", "Person"), NewNode("bob", "Person"), "KNOWS")
+	conflicting.SetProperty("since", "2019")
+	gd.AddRelationship(conflicting)
+
+	// Repeats without properties are exact duplicates
+	gd.AddRelationship(NewRelationship(NewNode("bob", "Person"), NewNode("acme", "Company"), "WORKS_AT"))
+
+	issues := gd.IntegrityIssues()
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d: %+v", len(issues), issues)
+	}
+
+	expected := []struct {
+		kind    IntegrityIssueKind
+		relType string
+	}{
+		{IssueDuplicateRelationship, "KNOWS"},
+		{IssueConflictingRelationship, "KNOWS"},
+		{IssueDuplicateRelationship, "WORKS_AT"},
+	}
+	for i, want := range expected {
+		issue := issues[i]
+		if issue.Kind != want.kind || issue.Relationship == nil || issue.Relationship.Type != want.relType {
+			t.Errorf("Expected issue %d to be %s on %s, got %+v", i, want.kind, want.relType, issue)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(gd *GraphDocument)
+		want  string
+	}{
+		{
+			name: "missing endpoint",
+			build: func(gd *GraphDocument) {
+				gd.AddRelationship(NewRelationship(NewNode("alice", "Person"), NewNode("ghost", "Person"), "KNOWS"))
+			},
+			want: `references missing node "ghost"`,
+		},
+		{
+			name:  "duplicate node id",
+			build: func(gd *GraphDocument) { gd.AddNode(NewNode("alice", "Person")) },
+			want:  `node id "alice" is used by more than one node`,
+		},
+		{
+			name:  "empty node type",
+			build: func(gd *GraphDocument) { gd.AddNode(NewNode("mystery", "")) },
+			want:  `node "mystery" has an empty type`,
+		},
+		{
+			name: "empty relationship type",
+			build: func(gd *GraphDocument) {
+				gd.AddRelationship(NewRelationship(NewNode("bob", "Person"), NewNode("alice", "Person"), ""))
+			},
+			want: `relationship "bob"->"alice" has an empty type`,
+		},
+		{
+			name:  "nil node properties",
+			build: func(gd *GraphDocument) { gd.AddNode(Node{ID: "carol", Type: "Person"}) },
+			want:  `node "carol" has nil properties`,
+		},
+		{
+			name: "nil relationship properties",
+			build: func(gd *GraphDocument) {
+				gd.AddRelationship(Relationship{Source: Node{ID: "bob"}, Target: Node{ID: "alice"}, Type: "KNOWS"})
+			},
+			want: `relationship "bob"-KNOWS->"alice" has nil properties`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gd := newTestGraphDocument()
+			tt.build(&gd)
+
+			errs := gd.Validate()
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %v", errs)
+			}
+			if !errors.Is(errs[0], ErrInvalidGraphDocument) || !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("Expected an ErrInvalidGraphDocument mentioning %q, got %v", tt.want, errs[0])
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	gd := newTestGraphDocument()
+	gd.AddNode(NewNode("mystery", ""))
+	gd.AddRelationship(NewRelationship(NewNode("alice", "Person"), NewNode("ghost", "Person"), "KNOWS"))
+	// Repeated relationships are merged on import rather than reported
+	gd.AddRelationship(NewRelationship(NewNode("alice", "Person"), NewNode("acme", "Company"), "WORKS_AT"))
+
+	if errs := gd.Validate(); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v", errs)
+	}
+}
+
+func TestValidateCleanDocument(t *testing.T) {
+	gd := newTestGraphDocument()
+	if errs := gd.Validate(); errs != nil {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
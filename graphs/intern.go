@@ -0,0 +1,45 @@
+package graphs
+
+// Intern deduplicates the node and relationship type strings and property keys of
+// the document, so identical strings share a single copy in memory. It is meant
+// for large graphs built from decoded input, where every occurrence of a type
+// or key is otherwise allocated separately. Property maps are updated in place
+// rather than copied, and the document content is unchanged.
+func (gd *GraphDocument) Intern() {
+	pool := make(stringPool)
+
+	for i := range gd.Nodes {
+		gd.Nodes[i].Type = pool.intern(gd.Nodes[i].Type)
+		pool.internKeys(gd.Nodes[i].Properties)
+	}
+
+	for i := range gd.Relationships {
+		rel := &gd.Relationships[i]
+		rel.Type = pool.intern(rel.Type)
+		rel.Source.Type = pool.intern(rel.Source.Type)
+		rel.Target.Type = pool.intern(rel.Target.Type)
+		pool.internKeys(rel.Properties)
+		pool.internKeys(rel.Source.Properties)
+		pool.internKeys(rel.Target.Properties)
+	}
+}
+
+// stringPool maps strings to their canonical copy
+type stringPool map[string]string
+
+// intern returns the canonical copy of s
+func (p stringPool) intern(s string) string {
+	if canonical, ok := p[s]; ok {
+		return canonical
+	}
+	p[s] = s
+	return s
+}
+
+// internKeys replaces the keys of properties with their canonical copies in place.
+// Assigning to an existing key stores the assigned key string, so no map is rebuilt.
+func (p stringPool) internKeys(properties map[string]interface{}) {
+	for key, value := range properties {
+		properties[p.intern(key)] = value
+	}
+}
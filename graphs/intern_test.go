@@ -0,0 +1,119 @@
+package graphs
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// newUninternedGraphDocument returns a document in which every type and property key
+// is a separately allocated string, as when decoding input
+func newUninternedGraphDocument(nodes int) *GraphDocument {
+	gd := &GraphDocument{}
+	for i := 0; i < nodes; i++ {
+		node := NewNode(fmt.Sprintf("node-%d", i), strings.Clone("OrganizationalUnit"))
+		node.SetProperty(strings.Clone("display_name"), fmt.Sprintf("Unit %d", i))
+		node.SetProperty(strings.Clone("registration_number"), i)
+		gd.AddNode(node)
+		if i > 0 {
+			source := gd.Nodes[i-1].Clone()
+			source.Type = strings.Clone(source.Type)
+			rel := NewRelationship(source, node.Clone(), strings.Clone("REPORTS_TO_DIRECTLY"))
+			rel.SetProperty(strings.Clone("effective_since"), "2024")
+			gd.AddRelationship(rel)
+		}
+	}
+	return gd
+}
+
+func TestInternPreservesContent(t *testing.T) {
+	gd := newUninternedGraphDocument(10)
+	original := gd.Clone()
+
+	gd.Intern()
+
+	if !reflect.DeepEqual(gd, original) {
+		t.Error("Expected the interned document to be equal to the original")
+	}
+	if gd.FindNode("node-3") == nil || len(gd.FindNodesByType("OrganizationalUnit")) != 10 {
+		t.Error("Expected lookups to behave identically after interning")
+	}
+	if len(gd.FindRelationshipsByType("REPORTS_TO_DIRECTLY")) != 9 {
+		t.Error("Expected relationship lookups to behave identically after interning")
+	}
+}
+
+func TestInternSharesStrings(t *testing.T) {
+	gd := newUninternedGraphDocument(3)
+
+	if unsafe.StringData(gd.Nodes[0].Type) == unsafe.StringData(gd.Nodes[1].Type) {
+		t.Fatal("Expected decoded types to be separate copies before interning")
+	}
+
+	gd.Intern()
+
+	typ := unsafe.StringData(gd.Nodes[0].Type)
+	for _, node := range gd.Nodes {
+		if unsafe.StringData(node.Type) != typ {
+			t.Errorf("Expected node %s to share the interned type", node.ID)
+		}
+	}
+	for _, rel := range gd.Relationships {
+		if unsafe.StringData(rel.Source.Type) != typ || unsafe.StringData(rel.Target.Type) != typ {
+			t.Error("Expected relationship endpoints to share the interned type")
+		}
+	}
+
+	var keys []*byte
+	for _, node := range gd.Nodes {
+		for key := range node.Properties {
+			if key == "display_name" {
+				keys = append(keys, unsafe.StringData(key))
+			}
+		}
+	}
+	for _, key := range keys {
+		if key != keys[0] {
+			t.Error("Expected property keys to share the interned key")
+		}
+	}
+}
+
+func TestInternEmpty(t *testing.T) {
+	gd := &GraphDocument{}
+	gd.Intern()
+	if len(gd.Nodes) != 0 || len(gd.Relationships) != 0 {
+		t.Error("Expected an empty document to stay empty")
+	}
+}
+
+// BenchmarkIntern reports the heap retained by a 10k-node document with and
+// without interning, in retained-B/op
+func BenchmarkIntern(b *testing.B) {
+	for _, intern := range []bool{false, true} {
+		b.Run(fmt.Sprintf("intern=%v", intern), func(b *testing.B) {
+			var retained int64
+			for i := 0; i < b.N; i++ {
+				before := heapInUse()
+				gd := newUninternedGraphDocument(10000)
+				if intern {
+					gd.Intern()
+				}
+				retained += int64(heapInUse()) - int64(before)
+				runtime.KeepAlive(gd)
+			}
+			b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+		})
+	}
+}
+
+// heapInUse returns the live heap size after a garbage collection
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
@@ -0,0 +1,7 @@
+// Package memstore provides an in-memory implementation of the graphs.GraphStore interface.
+//
+// The store keeps nodes and relationships in maps and needs no database, which makes it a
+// fast test double for code written against graphs.GraphStore. It mirrors the behavior of
+// the Neo4j store for the operations of the interface, but only understands a tiny subset
+// of Cypher in Query.
+package memstore
@@ -0,0 +1,649 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+var (
+	// ErrClosed is returned by operations on a closed store
+	ErrClosed = errors.New("store closed")
+	// ErrNodeExists is returned when creating a node whose ID is already stored
+	ErrNodeExists = errors.New("node already exists")
+	// ErrRelationshipExists is returned when creating a relationship that is already stored
+	ErrRelationshipExists = errors.New("relationship already exists")
+	// ErrRelationshipNotFound is returned when a relationship is not stored
+	ErrRelationshipNotFound = errors.New("relationship not found")
+	// ErrEndpointNotFound is returned when relationships reference nodes that are not stored
+	ErrEndpointNotFound = errors.New("relationship endpoint not found")
+)
+
+// Store is an in-memory graphs.GraphStore. It is safe for concurrent use.
+type Store struct {
+	mu            sync.RWMutex
+	closed        bool
+	nodes         map[string]*graphs.Node
+	nodeOrder     []string
+	relationships map[graphs.RelationshipIdentifier]*graphs.Relationship
+	relOrder      []graphs.RelationshipIdentifier
+
+	schema           string
+	structuredSchema map[string]interface{}
+}
+
+var _ graphs.GraphStore = (*Store)(nil)
+
+// New creates an empty in-memory store.
+func New() *Store {
+	return &Store{
+		nodes:            make(map[string]*graphs.Node),
+		relationships:    make(map[graphs.RelationshipIdentifier]*graphs.Relationship),
+		structuredSchema: make(map[string]interface{}),
+	}
+}
+
+// parseOptions applies options to the default options
+func parseOptions(options []graphs.Option) *graphs.Options {
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	return opts
+}
+
+// AddGraphDocument adds graph documents to the store. Nodes are upserted, and relationship
+// endpoints that are not stored are created from the relationship unless the
+// AssumeEndpointsExist option is set, in which case relationships with a missing endpoint
+// are skipped and reported with ErrEndpointNotFound. Source documents are not stored.
+func (s *Store) AddGraphDocument(ctx context.Context, docs []graphs.GraphDocument, options ...graphs.Option) error {
+	opts := parseOptions(options)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	// Nodes of every document are added first so relationships can reference any of them
+	nodeTypes := make(map[string]string)
+	for _, doc := range docs {
+		for _, node := range doc.Nodes {
+			if err := s.putNode(node, graphs.MergeModeUpsert, nil, ""); err != nil {
+				return err
+			}
+			if node.Type != "" {
+				nodeTypes[node.ID] = node.Type
+			}
+		}
+	}
+
+	var missing []string
+	for _, doc := range docs {
+		relationships := doc.Relationships
+		if opts.DropSelfLoops {
+			relationships = graphs.DropSelfLoops(relationships)
+		}
+		for _, rel := range relationships {
+			if !opts.AssumeEndpointsExist {
+				for _, endpoint := range []graphs.Node{rel.Source, rel.Target} {
+					if endpoint.Type == "" {
+						endpoint.Type = nodeTypes[endpoint.ID]
+					}
+					s.ensureNode(endpoint)
+				}
+			}
+			if !s.hasEndpoints(rel) {
+				missing = append(missing, describeRelationship(rel.GetIdentifier()))
+				continue
+			}
+			if err := s.putRelationship(rel, graphs.MergeModeUpsert, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrEndpointNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// AddNodes adds individual nodes to the store according to the MergeMode option. Creating
+// a node that is already stored fails with ErrNodeExists, and updates skip nodes that are
+// not stored. The ForceLabel option adds a label to every node.
+func (s *Store) AddNodes(ctx context.Context, nodes []graphs.Node, options ...graphs.Option) error {
+	opts := parseOptions(options)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	for _, node := range nodes {
+		if err := s.putNode(node, opts.MergeMode, opts.MergePropertiesFunc, opts.ForceLabel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddRelationships adds individual relationships to the store according to the MergeMode
+// option. Relationships whose source or target node is not stored are skipped and reported
+// with ErrEndpointNotFound once the others have been written, unless the
+// CreateMissingEndpoints option is set, in which case missing endpoints are created first.
+func (s *Store) AddRelationships(ctx context.Context, relationships []graphs.Relationship, options ...graphs.Option) error {
+	opts := parseOptions(options)
+	if opts.DropSelfLoops {
+		relationships = graphs.DropSelfLoops(relationships)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	var missing []string
+	for _, rel := range relationships {
+		if opts.CreateMissingEndpoints {
+			s.ensureNode(rel.Source)
+			s.ensureNode(rel.Target)
+		}
+		if !s.hasEndpoints(rel) {
+			missing = append(missing, describeRelationship(rel.GetIdentifier()))
+			continue
+		}
+		if err := s.putRelationship(rel, opts.MergeMode, opts.MergePropertiesFunc); err != nil {
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrEndpointNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// UpdateNode sets properties on a stored node, keeping its other properties
+func (s *Store) UpdateNode(ctx context.Context, nodeID string, properties map[string]interface{}, options ...graphs.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	node, ok := s.nodes[nodeID]
+	if !ok {
+		return fmt.Errorf("%w: %s", graphs.ErrNodeNotFound, nodeID)
+	}
+	node.Properties = mergeProperties(node.Properties, properties)
+	return nil
+}
+
+// UpdateRelationship sets properties on a stored relationship, keeping its other properties
+func (s *Store) UpdateRelationship(ctx context.Context, sourceID, targetID, relType string, properties map[string]interface{}, options ...graphs.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	id := graphs.RelationshipIdentifier{SourceID: sourceID, TargetID: targetID, Type: relType}
+	rel, ok := s.relationships[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrRelationshipNotFound, describeRelationship(id))
+	}
+	rel.Properties = mergeProperties(rel.Properties, properties)
+	return nil
+}
+
+// RemoveNode removes a node from the store. A node with relationships is only removed,
+// together with its relationships, when the CascadeDelete option is set; otherwise it is
+// left in place, as are nodes that are not stored.
+func (s *Store) RemoveNode(ctx context.Context, nodeID string, options ...graphs.Option) error {
+	opts := parseOptions(options)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.removeNode(nodeID, opts.CascadeDelete)
+	return nil
+}
+
+// RemoveNodes removes multiple nodes from the store like RemoveNode
+func (s *Store) RemoveNodes(ctx context.Context, nodeIDs []string, options ...graphs.Option) error {
+	opts := parseOptions(options)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	for _, nodeID := range nodeIDs {
+		s.removeNode(nodeID, opts.CascadeDelete)
+	}
+	return nil
+}
+
+// RemoveRelationship removes a relationship from the store, if it is stored
+func (s *Store) RemoveRelationship(ctx context.Context, sourceID, targetID, relType string, options ...graphs.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.removeRelationship(graphs.RelationshipIdentifier{SourceID: sourceID, TargetID: targetID, Type: relType})
+	return nil
+}
+
+// RemoveRelationships removes multiple relationships from the store, skipping those not stored
+func (s *Store) RemoveRelationships(ctx context.Context, relationships []graphs.RelationshipIdentifier, options ...graphs.Option) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	for _, id := range relationships {
+		s.removeRelationship(id)
+	}
+	return nil
+}
+
+// GetNode retrieves a node by its ID
+func (s *Store) GetNode(ctx context.Context, nodeID string, options ...graphs.Option) (*graphs.Node, error) {
+	opts := parseOptions(options)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	node, ok := s.nodes[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", graphs.ErrNodeNotFound, nodeID)
+	}
+	result := copyNode(*node, opts)
+	return &result, nil
+}
+
+// GetNodes retrieves multiple nodes by their IDs, in the order of nodeIDs.
+// IDs without a node are omitted.
+func (s *Store) GetNodes(ctx context.Context, nodeIDs []string, options ...graphs.Option) ([]graphs.Node, error) {
+	opts := parseOptions(options)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	var nodes []graphs.Node
+	for _, nodeID := range nodeIDs {
+		if node, ok := s.nodes[nodeID]; ok {
+			nodes = append(nodes, copyNode(*node, opts))
+		}
+	}
+	return nodes, nil
+}
+
+// GetRelationships retrieves relationships between nodes. An empty relType matches every
+// type, and the Direction option selects whether relationships stored from sourceID to
+// targetID, from targetID to sourceID or either way are matched. The WithReturnFullPath
+// option is not supported.
+func (s *Store) GetRelationships(ctx context.Context, sourceID, targetID string, relType string, options ...graphs.Option) ([]graphs.Relationship, error) {
+	opts := parseOptions(options)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	outgoing := opts.Direction == graphs.DirectionOutgoing || opts.Direction == graphs.DirectionBoth
+	incoming := opts.Direction == graphs.DirectionIncoming || opts.Direction == graphs.DirectionBoth
+
+	var relationships []graphs.Relationship
+	for _, id := range s.relOrder {
+		if relType != "" && id.Type != relType {
+			continue
+		}
+		if (outgoing && id.SourceID == sourceID && id.TargetID == targetID) ||
+			(incoming && id.SourceID == targetID && id.TargetID == sourceID) {
+			relationships = append(relationships, s.resolveRelationship(s.relationships[id], opts))
+		}
+	}
+	return relationships, nil
+}
+
+// GetNodesByType retrieves all nodes of a specific type in insertion order,
+// honoring the Limit and Offset options
+func (s *Store) GetNodesByType(ctx context.Context, nodeType string, options ...graphs.Option) ([]graphs.Node, error) {
+	opts := parseOptions(options)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	doc := s.document()
+	nodes := paginate(doc.FindNodesByType(nodeType), opts)
+	for i, node := range nodes {
+		nodes[i] = copyNode(node, opts)
+	}
+	return nodes, nil
+}
+
+// GetRelationshipsByType retrieves all relationships of a specific type in insertion
+// order, honoring the Limit and Offset options
+func (s *Store) GetRelationshipsByType(ctx context.Context, relType string, options ...graphs.Option) ([]graphs.Relationship, error) {
+	opts := parseOptions(options)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	doc := s.document()
+	relationships := paginate(doc.FindRelationshipsByType(relType), opts)
+	for i, rel := range relationships {
+		relationships[i] = s.resolveRelationship(&rel, opts)
+	}
+	return relationships, nil
+}
+
+// NodeExists checks if a node is stored
+func (s *Store) NodeExists(ctx context.Context, nodeID string, options ...graphs.Option) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false, ErrClosed
+	}
+
+	_, ok := s.nodes[nodeID]
+	return ok, nil
+}
+
+// RelationshipExists checks if a relationship is stored
+func (s *Store) RelationshipExists(ctx context.Context, sourceID, targetID, relType string, options ...graphs.Option) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return false, ErrClosed
+	}
+
+	_, ok := s.relationships[graphs.RelationshipIdentifier{SourceID: sourceID, TargetID: targetID, Type: relType}]
+	return ok, nil
+}
+
+// Close closes the store. Operations on a closed store fail with ErrClosed.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// putNode writes a node according to mode. Stored nodes gain the labels of node, and
+// an empty stored type is taken from node.
+func (s *Store) putNode(node graphs.Node, mode graphs.MergeMode, mergeFunc graphs.MergePropertiesFunc, forceLabel string) error {
+	labels := nodeLabels(node, forceLabel)
+	existing, ok := s.nodes[node.ID]
+
+	switch {
+	case !ok && mode == graphs.MergeModeUpdate:
+		return nil
+	case !ok:
+		s.nodes[node.ID] = &graphs.Node{
+			ID:         node.ID,
+			Type:       node.Type,
+			Labels:     labels,
+			Properties: copyProperties(node.Properties),
+		}
+		s.nodeOrder = append(s.nodeOrder, node.ID)
+		return nil
+	case mode == graphs.MergeModeCreate:
+		return fmt.Errorf("%w: %s", ErrNodeExists, node.ID)
+	}
+
+	if existing.Type == "" {
+		existing.Type = node.Type
+	}
+	existing.Labels = unionLabels(existing.Labels, labels)
+	existing.Properties = writeProperties(existing.Properties, node.Properties, mode, mergeFunc)
+	return nil
+}
+
+// ensureNode stores node if no node with its ID is stored
+func (s *Store) ensureNode(node graphs.Node) {
+	if _, ok := s.nodes[node.ID]; !ok {
+		_ = s.putNode(node, graphs.MergeModeUpsert, nil, "")
+	}
+}
+
+// hasEndpoints reports whether both endpoints of rel are stored
+func (s *Store) hasEndpoints(rel graphs.Relationship) bool {
+	_, source := s.nodes[rel.Source.ID]
+	_, target := s.nodes[rel.Target.ID]
+	return source && target
+}
+
+// putRelationship writes a relationship according to mode. Endpoints are stored by ID
+// only and resolved to the stored nodes when read.
+func (s *Store) putRelationship(rel graphs.Relationship, mode graphs.MergeMode, mergeFunc graphs.MergePropertiesFunc) error {
+	id := rel.GetIdentifier()
+	existing, ok := s.relationships[id]
+
+	switch {
+	case !ok && mode == graphs.MergeModeUpdate:
+		return nil
+	case !ok:
+		s.relationships[id] = &graphs.Relationship{
+			Source:     graphs.Node{ID: id.SourceID},
+			Target:     graphs.Node{ID: id.TargetID},
+			Type:       id.Type,
+			Properties: copyProperties(rel.Properties),
+		}
+		s.relOrder = append(s.relOrder, id)
+		return nil
+	case mode == graphs.MergeModeCreate:
+		return fmt.Errorf("%w: %s", ErrRelationshipExists, describeRelationship(id))
+	}
+
+	existing.Properties = writeProperties(existing.Properties, rel.Properties, mode, mergeFunc)
+	return nil
+}
+
+// removeNode removes a node, and its relationships when cascade is set. Nodes with
+// relationships are kept unless cascade is set.
+func (s *Store) removeNode(nodeID string, cascade bool) {
+	if _, ok := s.nodes[nodeID]; !ok {
+		return
+	}
+
+	var attached []graphs.RelationshipIdentifier
+	for _, id := range s.relOrder {
+		if id.SourceID == nodeID || id.TargetID == nodeID {
+			attached = append(attached, id)
+		}
+	}
+	if len(attached) > 0 && !cascade {
+		return
+	}
+	for _, id := range attached {
+		s.removeRelationship(id)
+	}
+
+	delete(s.nodes, nodeID)
+	for i, id := range s.nodeOrder {
+		if id == nodeID {
+			s.nodeOrder = append(s.nodeOrder[:i], s.nodeOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// removeRelationship removes a relationship, if it is stored
+func (s *Store) removeRelationship(id graphs.RelationshipIdentifier) {
+	if _, ok := s.relationships[id]; !ok {
+		return
+	}
+	delete(s.relationships, id)
+	for i, stored := range s.relOrder {
+		if stored == id {
+			s.relOrder = append(s.relOrder[:i], s.relOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// document returns the stored graph as a GraphDocument in insertion order, sharing the
+// stored property maps. Relationship endpoints are the stored nodes.
+func (s *Store) document() graphs.GraphDocument {
+	doc := graphs.GraphDocument{
+		Nodes:         make([]graphs.Node, 0, len(s.nodeOrder)),
+		Relationships: make([]graphs.Relationship, 0, len(s.relOrder)),
+	}
+	for _, id := range s.nodeOrder {
+		doc.AddNode(*s.nodes[id])
+	}
+	for _, id := range s.relOrder {
+		rel := *s.relationships[id]
+		rel.Source = *s.nodes[id.SourceID]
+		rel.Target = *s.nodes[id.TargetID]
+		doc.AddRelationship(rel)
+	}
+	return doc
+}
+
+// resolveRelationship returns a copy of rel with its endpoints resolved to the stored nodes
+func (s *Store) resolveRelationship(rel *graphs.Relationship, opts *graphs.Options) graphs.Relationship {
+	return graphs.Relationship{
+		Source:     copyNode(*s.nodes[rel.Source.ID], opts),
+		Target:     copyNode(*s.nodes[rel.Target.ID], opts),
+		Type:       rel.Type,
+		Properties: filterProperties(rel.Properties, opts),
+	}
+}
+
+// nodeLabels returns the labels of node with its type first, adding forceLabel if set
+func nodeLabels(node graphs.Node, forceLabel string) []string {
+	labels := []string{node.Type}
+	labels = append(labels, node.Labels...)
+	labels = append(labels, forceLabel)
+	return unionLabels(nil, labels)
+}
+
+// unionLabels appends the non-empty labels of incoming missing from labels
+func unionLabels(labels, incoming []string) []string {
+	for _, label := range incoming {
+		if label == "" {
+			continue
+		}
+		found := false
+		for _, existing := range labels {
+			if existing == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// writeProperties returns the properties stored after writing incoming over existing with mode
+func writeProperties(existing, incoming map[string]interface{}, mode graphs.MergeMode, mergeFunc graphs.MergePropertiesFunc) map[string]interface{} {
+	switch {
+	case mode == graphs.MergeModeReplace:
+		return copyProperties(incoming)
+	case mode == graphs.MergeModeUpsert && mergeFunc != nil:
+		return copyProperties(mergeFunc(copyProperties(existing), copyProperties(incoming)))
+	default:
+		return mergeProperties(existing, incoming)
+	}
+}
+
+// mergeProperties sets the incoming properties on existing, returning the result
+func mergeProperties(existing, incoming map[string]interface{}) map[string]interface{} {
+	if existing == nil {
+		existing = make(map[string]interface{}, len(incoming))
+	}
+	for key, value := range incoming {
+		existing[key] = value
+	}
+	return existing
+}
+
+// copyProperties returns a shallow copy of properties, never nil
+func copyProperties(properties map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		copied[key] = value
+	}
+	return copied
+}
+
+// filterProperties returns a copy of properties selected by the include and exclude options of opts
+func filterProperties(properties map[string]interface{}, opts *graphs.Options) map[string]interface{} {
+	if len(opts.IncludeProperties) > 0 {
+		filtered := make(map[string]interface{}, len(opts.IncludeProperties))
+		for _, key := range opts.IncludeProperties {
+			if value, ok := properties[key]; ok {
+				filtered[key] = value
+			}
+		}
+		return filtered
+	}
+
+	filtered := copyProperties(properties)
+	for _, key := range opts.ExcludeProperties {
+		delete(filtered, key)
+	}
+	return filtered
+}
+
+// copyNode returns a copy of node whose properties are filtered by opts
+func copyNode(node graphs.Node, opts *graphs.Options) graphs.Node {
+	return graphs.Node{
+		ID:         node.ID,
+		Type:       node.Type,
+		Labels:     append([]string(nil), node.Labels...),
+		Properties: filterProperties(node.Properties, opts),
+	}
+}
+
+// paginate applies the Offset and Limit options to items
+func paginate[T any](items []T, opts *graphs.Options) []T {
+	if opts.Offset > 0 {
+		if opts.Offset >= len(items) {
+			return nil
+		}
+		items = items[opts.Offset:]
+	}
+	if opts.Limit > 0 && opts.Limit < len(items) {
+		items = items[:opts.Limit]
+	}
+	return items
+}
+
+// describeRelationship formats a relationship identifier for error messages
+func describeRelationship(id graphs.RelationshipIdentifier) string {
+	return fmt.Sprintf("(%s)-[:%s]->(%s)", id.SourceID, id.Type, id.TargetID)
+}
@@ -0,0 +1,133 @@
+package memstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+	"github.com/0xDezzy/langchaingo-graphs/graphs/graphstoretest"
+	"github.com/0xDezzy/langchaingo-graphs/graphs/memstore"
+)
+
+func TestConformance(t *testing.T) {
+	graphstoretest.Run(t, func(t *testing.T) graphs.GraphStore {
+		return memstore.New()
+	})
+}
+
+func newSeededStore(t *testing.T) *memstore.Store {
+	t.Helper()
+	store := memstore.New()
+	nodes := []graphs.Node{
+		graphs.NewNode("alice", "Person"),
+		graphs.NewNode("bob", "Person"),
+		graphs.NewNode("acme", "Company"),
+	}
+	if err := store.AddNodes(context.Background(), nodes); err != nil {
+		t.Fatalf("AddNodes: %v", err)
+	}
+	return store
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.Background()
+	store := newSeededStore(t)
+
+	tests := []struct {
+		query    string
+		key      string
+		expected int
+	}{
+		{"MATCH (n) RETURN n", "n", 3},
+		{"MATCH (n:Person) RETURN n", "n", 2},
+		{"MATCH (p:`Person`) RETURN p AS person LIMIT 1", "person", 1},
+		{"MATCH (n) RETURN n LIMIT 0", "n", 0},
+		{"match (n:Company) return n", "n", 1},
+	}
+	for _, tt := range tests {
+		result, err := store.Query(ctx, tt.query, nil)
+		if err != nil {
+			t.Fatalf("Query(%q): %v", tt.query, err)
+		}
+		records := result["records"].([]map[string]interface{})
+		if len(records) != tt.expected {
+			t.Errorf("Query(%q): expected %d records, got %d", tt.query, tt.expected, len(records))
+		}
+		for _, record := range records {
+			if _, ok := record[tt.key].(graphs.Node); !ok {
+				t.Errorf("Query(%q): expected a node under %s, got %v", tt.query, tt.key, record)
+			}
+		}
+	}
+
+	result, err := store.Query(ctx, "MATCH (n:Person) RETURN count(n) AS total", nil)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	records := result["records"].([]map[string]interface{})
+	if len(records) != 1 || records[0]["total"] != int64(2) {
+		t.Errorf("Expected a count of 2, got %v", records)
+	}
+}
+
+func TestQueryUnsupported(t *testing.T) {
+	store := newSeededStore(t)
+	for _, query := range []string{
+		"MATCH (n)-[r]->(m) RETURN r",
+		"MATCH (n) RETURN m",
+		"CREATE (n:Person {id: 'carol'})",
+		"MATCH (n) RETURN n LIMIT 99999999999999999999",
+	} {
+		if _, err := store.Query(context.Background(), query, nil); !errors.Is(err, memstore.ErrUnsupportedQuery) {
+			t.Errorf("Query(%q): expected ErrUnsupportedQuery, got %v", query, err)
+		}
+	}
+}
+
+func TestMergeModeCreate(t *testing.T) {
+	ctx := context.Background()
+	store := newSeededStore(t)
+
+	err := store.AddNodes(ctx, []graphs.Node{graphs.NewNode("alice", "Person")}, graphs.WithMergeMode(graphs.MergeModeCreate))
+	if !errors.Is(err, memstore.ErrNodeExists) {
+		t.Errorf("Expected ErrNodeExists, got %v", err)
+	}
+
+	rel := graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("bob", "Person"), "KNOWS")
+	if err := store.AddRelationships(ctx, []graphs.Relationship{rel}); err != nil {
+		t.Fatalf("AddRelationships: %v", err)
+	}
+	err = store.AddRelationships(ctx, []graphs.Relationship{rel}, graphs.WithMergeMode(graphs.MergeModeCreate))
+	if !errors.Is(err, memstore.ErrRelationshipExists) {
+		t.Errorf("Expected ErrRelationshipExists, got %v", err)
+	}
+}
+
+func TestForceLabel(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+
+	node := graphs.NewNode("alice", "Person")
+	if err := store.AddNodes(ctx, []graphs.Node{node}, graphs.WithForceLabel("Entity")); err != nil {
+		t.Fatalf("AddNodes: %v", err)
+	}
+
+	result, err := store.Query(ctx, "MATCH (n:Entity) RETURN count(n) AS count", nil)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if count := result["records"].([]map[string]interface{})[0]["count"]; count != int64(1) {
+		t.Errorf("Expected 1 node labeled Entity, got %v", count)
+	}
+}
+
+func TestClose(t *testing.T) {
+	store := newSeededStore(t)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := store.GetNode(context.Background(), "alice"); !errors.Is(err, memstore.ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
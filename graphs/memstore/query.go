@@ -0,0 +1,236 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// ErrUnsupportedQuery is returned by Query for queries outside the supported subset
+var ErrUnsupportedQuery = errors.New("unsupported query")
+
+// nodeQueryPattern matches the queries understood by Query:
+//
+//	MATCH (n[:Label]) RETURN n|count(n) [AS alias] [LIMIT k]
+var nodeQueryPattern = regexp.MustCompile("(?i)^\\s*MATCH\\s+\\((\\w+)(?:\\s*:\\s*(?:`([^`]+)`|(\\w+)))?\\s*\\)\\s+" +
+	"RETURN\\s+(?:(\\w+)|count\\(\\s*(\\w+)\\s*\\))(?:\\s+AS\\s+(\\w+))?(?:\\s+LIMIT\\s+(\\d+))?\\s*;?\\s*$")
+
+// Query runs a query against the store. Only a tiny subset of Cypher is understood:
+// matching all nodes or the nodes of one label, returning either the nodes, as
+// graphs.Node values, or their count, optionally aliased and limited. For example:
+//
+//	MATCH (n:Person) RETURN n LIMIT 10
+//	MATCH (n) RETURN count(n) AS count
+//
+// Other queries fail with ErrUnsupportedQuery. Parameters are not used. Results have
+// the same shape as those of the Neo4j store, with the rows under "records".
+func (s *Store) Query(ctx context.Context, query string, params map[string]interface{}) (map[string]interface{}, error) {
+	match := nodeQueryPattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedQuery, query)
+	}
+	variable, label := match[1], match[2]+match[3]
+	returned, counted, alias := match[4], match[5], match[6]
+	if (returned != "" && returned != variable) || (counted != "" && counted != variable) {
+		return nil, fmt.Errorf("%w: unknown variable in %s", ErrUnsupportedQuery, query)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	opts := graphs.NewOptions()
+	var nodes []graphs.Node
+	for _, id := range s.nodeOrder {
+		node := s.nodes[id]
+		if label == "" || hasLabel(node, label) {
+			nodes = append(nodes, copyNode(*node, opts))
+		}
+	}
+
+	records := []map[string]interface{}{}
+	if counted != "" {
+		key := alias
+		if key == "" {
+			key = "count(" + variable + ")"
+		}
+		records = append(records, map[string]interface{}{key: int64(len(nodes))})
+	} else {
+		key := alias
+		if key == "" {
+			key = variable
+		}
+		if match[7] != "" {
+			limit, err := strconv.Atoi(match[7])
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid limit in %s", ErrUnsupportedQuery, query)
+			}
+			// Unlike the Limit option, where zero means no limit, LIMIT 0 returns no rows
+			if limit < len(nodes) {
+				nodes = nodes[:limit]
+			}
+		}
+		for _, node := range nodes {
+			records = append(records, map[string]interface{}{key: node})
+		}
+	}
+
+	return map[string]interface{}{
+		"records": records,
+		"summary": map[string]interface{}{
+			"query":      query,
+			"parameters": params,
+		},
+	}, nil
+}
+
+// hasLabel reports whether node has the label
+func hasLabel(node *graphs.Node, label string) bool {
+	if node.Type == label {
+		return true
+	}
+	for _, l := range node.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshSchema recomputes the schema from the stored nodes and relationships
+func (s *Store) RefreshSchema(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	doc := s.document()
+
+	nodeProps := make(map[string]interface{})
+	for _, node := range doc.Nodes {
+		nodeProps[node.Type] = addPropertyTypes(nodeProps[node.Type], node.Properties)
+	}
+	relProps := make(map[string]interface{})
+	var relationships []map[string]interface{}
+	seen := make(map[string]bool)
+	for _, rel := range doc.Relationships {
+		relProps[rel.Type] = addPropertyTypes(relProps[rel.Type], rel.Properties)
+		key := rel.Source.Type + "|" + rel.Type + "|" + rel.Target.Type
+		if !seen[key] {
+			seen[key] = true
+			relationships = append(relationships, map[string]interface{}{
+				"start": rel.Source.Type,
+				"type":  rel.Type,
+				"end":   rel.Target.Type,
+			})
+		}
+	}
+
+	s.structuredSchema = map[string]interface{}{
+		"node_props":    nodeProps,
+		"rel_props":     relProps,
+		"relationships": relationships,
+		"metadata":      map[string]interface{}{},
+	}
+	s.schema = formatSchema(nodeProps, relProps, relationships)
+	return nil
+}
+
+// GetSchema returns the schema computed by the last RefreshSchema as a string
+func (s *Store) GetSchema() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.schema
+}
+
+// GetStructuredSchema returns the schema computed by the last RefreshSchema, in the
+// shape of the Neo4j store's structured schema
+func (s *Store) GetStructuredSchema() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.structuredSchema
+}
+
+// addPropertyTypes adds the properties missing from the property list props, with
+// their types, returning the list
+func addPropertyTypes(props interface{}, properties map[string]interface{}) []interface{} {
+	list, _ := props.([]interface{})
+	known := make(map[string]bool, len(list))
+	for _, prop := range list {
+		known[prop.(map[string]interface{})["property"].(string)] = true
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		if !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		list = append(list, map[string]interface{}{"property": key, "type": propertyType(properties[key])})
+	}
+	return list
+}
+
+// propertyType returns the Neo4j type name of a property value
+func propertyType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "STRING"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "INTEGER"
+	case float32, float64:
+		return "FLOAT"
+	case bool:
+		return "BOOLEAN"
+	case []interface{}, []string, []int, []int64, []float32, []float64:
+		return "LIST"
+	default:
+		return "ANY"
+	}
+}
+
+// formatSchema formats the schema like the Neo4j store does without enhanced schema
+func formatSchema(nodeProps, relProps map[string]interface{}, relationships []map[string]interface{}) string {
+	parts := []string{"Node properties:"}
+	parts = append(parts, formatProperties(nodeProps)...)
+	parts = append(parts, "Relationship properties:")
+	parts = append(parts, formatProperties(relProps)...)
+	parts = append(parts, "The relationships:")
+	for _, rel := range relationships {
+		parts = append(parts, fmt.Sprintf("(:%s)-[:%s]->(:%s)", rel["start"], rel["type"], rel["end"]))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// formatProperties formats property lists by label or type, in label order
+func formatProperties(props map[string]interface{}) []string {
+	labels := make([]string, 0, len(props))
+	for label := range props {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var parts []string
+	for _, label := range labels {
+		var propStrs []string
+		for _, prop := range props[label].([]interface{}) {
+			propMap := prop.(map[string]interface{})
+			propStrs = append(propStrs, fmt.Sprintf("%s: %s", propMap["property"], propMap["type"]))
+		}
+		if len(propStrs) > 0 {
+			parts = append(parts, fmt.Sprintf("%s {%s}", label, strings.Join(propStrs, ", ")))
+		}
+	}
+	return parts
+}
@@ -0,0 +1,118 @@
+package graphs
+
+import "reflect"
+
+// MergeStrategy resolves a property present on both entities being merged,
+// returning the value to keep.
+type MergeStrategy func(key string, existing, incoming interface{}) interface{}
+
+var (
+	// MergeSkip leaves entities already in a GraphDocument unchanged when passed to
+	// GraphDocument.MergeWith, as GraphDocument.Merge does. It is the nil strategy, so
+	// Node.Merge and Relationship.Merge treat it as MergeKeepExisting.
+	MergeSkip MergeStrategy
+
+	// MergeUnion adds the properties missing on the existing entity and keeps the
+	// existing value of conflicting properties, as UnionDocuments does
+	MergeUnion = MergeKeepExisting
+
+	// MergeKeepExisting keeps the existing value of conflicting properties
+	MergeKeepExisting MergeStrategy = func(key string, existing, incoming interface{}) interface{} {
+		return existing
+	}
+
+	// MergeOverwrite replaces conflicting properties with the incoming value, unless it is nil
+	MergeOverwrite MergeStrategy = func(key string, existing, incoming interface{}) interface{} {
+		if incoming == nil {
+			return existing
+		}
+		return incoming
+	}
+
+	// MergeCollect keeps every distinct value of conflicting properties in a list.
+	// An existing []interface{} value is extended rather than nested.
+	MergeCollect MergeStrategy = func(key string, existing, incoming interface{}) interface{} {
+		values := []interface{}{existing}
+		if list, ok := existing.([]interface{}); ok {
+			values = append([]interface{}(nil), list...)
+		}
+		for _, value := range values {
+			if reflect.DeepEqual(value, incoming) {
+				return values
+			}
+		}
+		return append(values, incoming)
+	}
+)
+
+// Merge combines another representation of the same node into n. Properties only
+// on other are added, conflicting properties are resolved by strategy, and an
+// empty type is taken from other. Labels of other missing from n are appended,
+// after the type of n when n has no labels. A nil strategy keeps existing values.
+func (n *Node) Merge(other Node, strategy MergeStrategy) {
+	if n.Type == "" {
+		n.Type = other.Type
+	}
+	if len(other.Labels) > 0 {
+		labels := append([]string(nil), n.Labels...)
+		if len(labels) == 0 && n.Type != "" {
+			labels = append(labels, n.Type)
+		}
+		n.Labels = unionLabels(labels, other.Labels)
+	}
+	n.Properties = mergeProperties(n.Properties, other.Properties, strategy)
+}
+
+// Merge combines another representation of the same relationship into r. Properties
+// are merged as in Node.Merge, and empty types of r and its endpoints are taken from other.
+func (r *Relationship) Merge(other Relationship, strategy MergeStrategy) {
+	if r.Type == "" {
+		r.Type = other.Type
+	}
+	if r.Source.Type == "" && r.Source.ID == other.Source.ID {
+		r.Source.Type = other.Source.Type
+	}
+	if r.Target.Type == "" && r.Target.ID == other.Target.ID {
+		r.Target.Type = other.Target.Type
+	}
+	r.Properties = mergeProperties(r.Properties, other.Properties, strategy)
+}
+
+// unionLabels appends the non-empty labels of incoming missing from labels
+func unionLabels(labels, incoming []string) []string {
+	for _, label := range incoming {
+		if label == "" {
+			continue
+		}
+		found := false
+		for _, existing := range labels {
+			if existing == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// mergeProperties merges incoming into existing, resolving conflicts with strategy
+func mergeProperties(existing, incoming map[string]interface{}, strategy MergeStrategy) map[string]interface{} {
+	if strategy == nil {
+		strategy = MergeKeepExisting
+	}
+	if existing == nil && len(incoming) > 0 {
+		existing = make(map[string]interface{}, len(incoming))
+	}
+
+	for key, value := range incoming {
+		if current, ok := existing[key]; ok {
+			existing[key] = strategy(key, current, value)
+		} else {
+			existing[key] = value
+		}
+	}
+	return existing
+}
@@ -0,0 +1,260 @@
+package graphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newMergeNodes() (Node, Node) {
+	existing := NewNode("alice", "")
+	existing.SetProperty("name", "Alice")
+	existing.SetProperty("age", 30)
+
+	incoming := NewNode("alice", "Person")
+	incoming.SetProperty("name", "Alice Smith")
+	incoming.SetProperty("city", "Paris")
+	return existing, incoming
+}
+
+func TestNodeMergeStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		wantName interface{}
+	}{
+		{"nil keeps existing", nil, "Alice"},
+		{"keep existing", MergeKeepExisting, "Alice"},
+		{"overwrite", MergeOverwrite, "Alice Smith"},
+		{"collect", MergeCollect, []interface{}{"Alice", "Alice Smith"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, incoming := newMergeNodes()
+			node.Merge(incoming, tt.strategy)
+
+			if !reflect.DeepEqual(node.Properties["name"], tt.wantName) {
+				t.Errorf("Expected name %v, got %v", tt.wantName, node.Properties["name"])
+			}
+			if node.Properties["age"] != 30 || node.Properties["city"] != "Paris" {
+				t.Errorf("Expected non-conflicting properties to be unioned, got %v", node.Properties)
+			}
+			if node.Type != "Person" {
+				t.Errorf("Expected the empty type to be filled, got %q", node.Type)
+			}
+		})
+	}
+}
+
+func TestNodeMergeCollectDeduplicates(t *testing.T) {
+	node := NewNode("alice", "Person")
+	node.SetProperty("tag", "a")
+
+	for _, tag := range []string{"b", "a", "b", "c"} {
+		other := NewNode("alice", "Person")
+		other.SetProperty("tag", tag)
+		node.Merge(other, MergeCollect)
+	}
+
+	expected := []interface{}{"a", "b", "c"}
+	if !reflect.DeepEqual(node.Properties["tag"], expected) {
+		t.Errorf("Expected %v, got %v", expected, node.Properties["tag"])
+	}
+}
+
+func TestNodeMergeCustomResolver(t *testing.T) {
+	node, incoming := newMergeNodes()
+	incoming.SetProperty("age", 31)
+
+	var resolved []string
+	longest := func(key string, existing, incoming interface{}) interface{} {
+		resolved = append(resolved, key)
+		if key == "age" {
+			return existing.(int) + incoming.(int)
+		}
+		if len(incoming.(string)) > len(existing.(string)) {
+			return incoming
+		}
+		return existing
+	}
+	node.Merge(incoming, longest)
+
+	if node.Properties["name"] != "Alice Smith" || node.Properties["age"] != 61 {
+		t.Errorf("Expected the custom resolver to apply, got %v", node.Properties)
+	}
+	if len(resolved) != 2 {
+		t.Errorf("Expected the resolver to run for the 2 conflicts only, got %v", resolved)
+	}
+}
+
+func TestNodeMergeKeepsType(t *testing.T) {
+	node := NewNode("acme", "Company")
+	node.Merge(NewNode("acme", "Organization"), nil)
+
+	if node.Type != "Company" {
+		t.Errorf("Expected the existing type to be kept, got %q", node.Type)
+	}
+}
+
+func TestNodeMergeUnionsLabels(t *testing.T) {
+	node := NewNode("acme", "Company")
+	other := NewNode("acme", "Company")
+	other.Labels = []string{"Company", "Customer", "Supplier"}
+	node.Merge(other, nil)
+
+	if !reflect.DeepEqual(node.Labels, []string{"Company", "Customer", "Supplier"}) {
+		t.Errorf("Expected the type followed by the labels of other, got %v", node.Labels)
+	}
+
+	node.Merge(Node{ID: "acme", Labels: []string{"Supplier", "Partner"}}, nil)
+	if !reflect.DeepEqual(node.Labels, []string{"Company", "Customer", "Supplier", "Partner"}) {
+		t.Errorf("Expected the labels to be unioned without duplicates, got %v", node.Labels)
+	}
+
+	plain := NewNode("bob", "Person")
+	plain.Merge(NewNode("bob", "Person"), nil)
+	if plain.Labels != nil {
+		t.Errorf("Expected no labels when neither node has any, got %v", plain.Labels)
+	}
+}
+
+func TestRelationshipMerge(t *testing.T) {
+	rel := NewRelationship(Node{ID: "alice"}, NewNode("acme", "Company"), "WORKS_AT")
+	rel.SetProperty("since", 2020)
+
+	other := NewRelationship(NewNode("alice", "Person"), NewNode("acme", "Company"), "WORKS_AT")
+	other.SetProperty("since", 2021)
+	other.SetProperty("role", "engineer")
+
+	rel.Merge(other, MergeOverwrite)
+
+	if rel.Properties["since"] != 2021 || rel.Properties["role"] != "engineer" {
+		t.Errorf("Expected merged properties, got %v", rel.Properties)
+	}
+	if rel.Source.Type != "Person" {
+		t.Errorf("Expected the empty source type to be filled, got %q", rel.Source.Type)
+	}
+}
+
+func TestMergeIntoNilProperties(t *testing.T) {
+	node := Node{ID: "alice"}
+	other := NewNode("alice", "Person")
+	other.SetProperty("name", "Alice")
+
+	node.Merge(other, nil)
+
+	if node.Properties["name"] != "Alice" {
+		t.Errorf("Expected properties to be added, got %v", node.Properties)
+	}
+}
+
+func TestMergeOverwriteKeepsExistingOnNil(t *testing.T) {
+	node := NewNode("alice", "Person")
+	node.SetProperty("name", "Alice")
+	incoming := NewNode("alice", "Person")
+	incoming.SetProperty("name", nil)
+
+	node.Merge(incoming, MergeOverwrite)
+
+	if node.Properties["name"] != "Alice" {
+		t.Errorf("Expected a nil incoming value not to overwrite, got %v", node.Properties["name"])
+	}
+}
+
+// newMergeDocuments returns two documents describing alice and her relationship to acme
+func newMergeDocuments() (*GraphDocument, *GraphDocument) {
+	existing, incoming := newMergeNodes()
+	acme := NewNode("acme", "Company")
+
+	first := &GraphDocument{}
+	first.AddNode(existing)
+	first.AddNode(acme)
+	worksAt := NewRelationship(existing, acme, "WORKS_AT")
+	worksAt.SetProperty("since", "2020")
+	first.AddRelationship(worksAt)
+
+	second := &GraphDocument{}
+	second.AddNode(incoming)
+	second.AddNode(NewNode("bob", "Person"))
+	updated := NewRelationship(incoming, acme, "WORKS_AT")
+	updated.SetProperty("since", "2021")
+	updated.SetProperty("role", "engineer")
+	second.AddRelationship(updated)
+	second.AddRelationship(NewRelationship(NewNode("bob", "Person"), acme, "WORKS_AT"))
+
+	return first, second
+}
+
+func TestGraphDocumentMergeWith(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  MergeStrategy
+		wantName  interface{}
+		wantSince interface{}
+	}{
+		{"keep existing", MergeKeepExisting, "Alice", "2020"},
+		{"union", MergeUnion, "Alice", "2020"},
+		{"overwrite", MergeOverwrite, "Alice Smith", "2021"},
+		{"collect", MergeCollect, []interface{}{"Alice", "Alice Smith"}, []interface{}{"2020", "2021"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gd, other := newMergeDocuments()
+			gd.MergeWith(other, tt.strategy)
+
+			if len(gd.Nodes) != 3 || len(gd.Relationships) != 2 {
+				t.Fatalf("Expected 3 nodes and 2 relationships, got %d and %d", len(gd.Nodes), len(gd.Relationships))
+			}
+
+			alice := gd.FindNode("alice")
+			if !reflect.DeepEqual(alice.Properties["name"], tt.wantName) {
+				t.Errorf("Expected name %v, got %v", tt.wantName, alice.Properties["name"])
+			}
+			if alice.Properties["age"] != 30 || alice.Properties["city"] != "Paris" {
+				t.Errorf("Expected the union of node properties, got %v", alice.Properties)
+			}
+
+			rel := gd.FindRelationship("alice", "acme", "WORKS_AT")
+			if !reflect.DeepEqual(rel.Properties["since"], tt.wantSince) {
+				t.Errorf("Expected since %v, got %v", tt.wantSince, rel.Properties["since"])
+			}
+			if rel.Properties["role"] != "engineer" {
+				t.Errorf("Expected the union of relationship properties, got %v", rel.Properties)
+			}
+		})
+	}
+}
+
+func TestGraphDocumentMergeSkipsCollisions(t *testing.T) {
+	for name, merge := range map[string]func(gd, other *GraphDocument){
+		"Merge":               func(gd, other *GraphDocument) { gd.Merge(other) },
+		"MergeWith MergeSkip": func(gd, other *GraphDocument) { gd.MergeWith(other, MergeSkip) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			gd, other := newMergeDocuments()
+			merge(gd, other)
+			expectCollisionsSkipped(t, gd)
+		})
+	}
+}
+
+// expectCollisionsSkipped checks that merging newMergeDocuments only added new entities
+func expectCollisionsSkipped(t *testing.T, gd *GraphDocument) {
+	t.Helper()
+
+	if len(gd.Nodes) != 3 || len(gd.Relationships) != 2 {
+		t.Fatalf("Expected 3 nodes and 2 relationships, got %d and %d", len(gd.Nodes), len(gd.Relationships))
+	}
+
+	alice := gd.FindNode("alice")
+	if alice.Properties["name"] != "Alice" {
+		t.Errorf("Expected the existing node to be unchanged, got %v", alice.Properties)
+	}
+	if _, ok := alice.Properties["city"]; ok {
+		t.Errorf("Expected no properties to be added to the existing node, got %v", alice.Properties)
+	}
+	if rel := gd.FindRelationship("alice", "acme", "WORKS_AT"); len(rel.Properties) != 1 {
+		t.Errorf("Expected the existing relationship to be unchanged, got %v", rel.Properties)
+	}
+}
@@ -0,0 +1,131 @@
+package neo4j
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// queryCache is a thread-safe LRU cache of read query results with a time to live.
+// Every invalidation starts a new generation, and results read during an earlier
+// generation are not stored, so a read racing a mutation cannot cache stale data.
+type queryCache struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	entries    map[string]*list.Element
+	order      *list.List
+	now        func() time.Time
+	generation uint64
+}
+
+// queryCacheEntry is a cached query result
+type queryCacheEntry struct {
+	key     string
+	result  map[string]interface{}
+	expires time.Time
+}
+
+// newQueryCache creates a cache holding up to size results for ttl each
+func newQueryCache(size int, ttl time.Duration) *queryCache {
+	return &queryCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// queryCacheKey hashes a query and its parameters. It returns false when
+// the parameters cannot be encoded, in which case the result is not cached.
+func queryCacheKey(database, query string, params map[string]interface{}) (string, bool) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(database))
+	hash.Write([]byte{0})
+	hash.Write([]byte(query))
+	hash.Write([]byte{0})
+	hash.Write(encoded)
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+// get returns the unexpired result cached under key
+func (c *queryCache) get(key string) (map[string]interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*queryCacheEntry)
+	if c.ttl > 0 && c.now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.result, true
+}
+
+// currentGeneration returns the generation to pass to put for a result about to be read
+func (c *queryCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// put caches result under key, evicting the least recently used result when full.
+// The result is dropped if the cache was invalidated since generation was read.
+func (c *queryCache) put(key string, result map[string]interface{}, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*queryCacheEntry)
+		entry.result = result
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&queryCacheEntry{key: key, result: result, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*queryCacheEntry).key)
+	}
+}
+
+// invalidate removes every cached result. It is a no-op on a nil cache.
+func (c *queryCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.generation++
+}
+
+// invalidateQueryCache drops cached query results after a mutating operation
+func (n *Neo4j) invalidateQueryCache() {
+	n.queryCache.invalidate()
+}
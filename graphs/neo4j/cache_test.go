@@ -0,0 +1,142 @@
+package neo4j
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+func newCachedNeo4j(size int, ttl time.Duration) (*Neo4j, *fakeDriver) {
+	n4j, driver := newFakeNeo4j(WithQueryCache(size, ttl))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("count", int64(len(driver.queries)))}, nil
+	}
+	return n4j, driver
+}
+
+func TestQueryCacheHit(t *testing.T) {
+	n4j, driver := newCachedNeo4j(10, time.Minute)
+	ctx := context.Background()
+	query := "MATCH (n:Person) WHERE n.age > $age RETURN count(n) AS count"
+
+	first, err := n4j.Query(ctx, query, map[string]interface{}{"age": 30})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := n4j.Query(ctx, query, map[string]interface{}{"age": 30})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected the second query to be served from cache, got %d queries", len(driver.queries))
+	}
+	if first["records"].([]map[string]interface{})[0]["count"] != second["records"].([]map[string]interface{})[0]["count"] {
+		t.Error("Expected the cached result to be returned")
+	}
+
+	// Different parameters are a different cache entry
+	if _, err := n4j.Query(ctx, query, map[string]interface{}{"age": 40}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected different params to miss the cache, got %d queries", len(driver.queries))
+	}
+}
+
+func TestQueryCacheTTLExpiry(t *testing.T) {
+	n4j, driver := newCachedNeo4j(10, time.Minute)
+	now := time.Now()
+	n4j.queryCache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	n4j.Query(ctx, "MATCH (n) RETURN count(n) AS count", nil)
+	now = now.Add(30 * time.Second)
+	n4j.Query(ctx, "MATCH (n) RETURN count(n) AS count", nil)
+	if len(driver.queries) != 1 {
+		t.Fatalf("Expected a cache hit within the ttl, got %d queries", len(driver.queries))
+	}
+
+	now = now.Add(time.Minute)
+	n4j.Query(ctx, "MATCH (n) RETURN count(n) AS count", nil)
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected the entry to expire after the ttl, got %d queries", len(driver.queries))
+	}
+}
+
+func TestQueryCacheInvalidatedByMutation(t *testing.T) {
+	n4j, driver := newCachedNeo4j(10, time.Minute)
+	ctx := context.Background()
+	query := "MATCH (n) RETURN count(n) AS count"
+
+	n4j.Query(ctx, query, nil)
+	if err := n4j.AddNodes(ctx, []graphs.Node{graphs.NewNode("alice", "Person")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	n4j.Query(ctx, query, nil)
+
+	if len(driver.queries) != 3 {
+		t.Errorf("Expected the read to run again after AddNodes, got %d queries", len(driver.queries))
+	}
+}
+
+func TestQueryCacheDropsReadsRacingInvalidation(t *testing.T) {
+	n4j, driver := newCachedNeo4j(10, time.Minute)
+	ctx := context.Background()
+	query := "MATCH (n) RETURN count(n) AS count"
+
+	// A mutation completes while the first read is in flight
+	driver.respond = func(string, map[string]interface{}) ([]*neo4j.Record, error) {
+		if len(driver.queries) == 1 {
+			n4j.invalidateQueryCache()
+		}
+		return []*neo4j.Record{newRecord("count", int64(len(driver.queries)))}, nil
+	}
+
+	n4j.Query(ctx, query, nil)
+	n4j.Query(ctx, query, nil)
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected the read racing the mutation not to be cached, got %d queries", len(driver.queries))
+	}
+
+	n4j.Query(ctx, query, nil)
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected reads after the mutation to be cached, got %d queries", len(driver.queries))
+	}
+}
+
+func TestQueryCacheBypassesWrites(t *testing.T) {
+	n4j, driver := newCachedNeo4j(10, time.Minute)
+	ctx := context.Background()
+	read := "MATCH (n) RETURN count(n) AS count"
+	write := "CREATE (n:Person {id: 'bob'}) RETURN n"
+
+	n4j.Query(ctx, read, nil)
+	n4j.Query(ctx, write, nil)
+	n4j.Query(ctx, write, nil)
+	n4j.Query(ctx, read, nil)
+
+	if len(driver.queries) != 4 {
+		t.Errorf("Expected writes to bypass the cache and invalidate it, got %d queries", len(driver.queries))
+	}
+}
+
+func TestQueryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	n4j, driver := newCachedNeo4j(2, 0)
+	ctx := context.Background()
+
+	n4j.Query(ctx, "RETURN 1 AS count", nil)
+	n4j.Query(ctx, "RETURN 2 AS count", nil)
+	n4j.Query(ctx, "RETURN 1 AS count", nil) // hit, now most recent
+	n4j.Query(ctx, "RETURN 3 AS count", nil) // evicts RETURN 2
+	n4j.Query(ctx, "RETURN 1 AS count", nil) // hit
+	n4j.Query(ctx, "RETURN 2 AS count", nil) // miss
+
+	if len(driver.queries) != 4 {
+		t.Errorf("Expected 4 queries to reach the database, got %d", len(driver.queries))
+	}
+}
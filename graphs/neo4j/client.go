@@ -2,33 +2,34 @@ package neo4j
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
 )
 
+// newDriver creates the underlying driver and can be replaced in tests
+var newDriver = neo4j.NewDriverWithContext
+
 // connect initializes the Neo4j driver connection
-func (n *Neo4j) connect() error {
+func (n *Neo4j) connect(ctx context.Context) error {
 	if n.uri == "" {
 		return ErrInvalidURI
 	}
 
-	// Create authentication token
-	auth := neo4j.BasicAuth(n.username, n.password, "")
+	// Create authentication token, refreshed through the provider if one is set
+	var tokenManager auth.TokenManager = neo4j.BasicAuth(n.username, n.password, "")
+	if n.authTokenProvider != nil {
+		tokenManager = auth.BasicTokenManager(n.authTokenProvider)
+	}
 
 	// Create driver with context support
-	driver, err := neo4j.NewDriverWithContext(n.uri, auth, func(config *neo4j.Config) {
-		// Apply any custom configuration
-		if n.config.MaxConnectionLifetime != 0 {
-			config.MaxConnectionLifetime = n.config.MaxConnectionLifetime
-		}
-		if n.config.MaxConnectionPoolSize != 0 {
-			config.MaxConnectionPoolSize = n.config.MaxConnectionPoolSize
-		}
-		if n.config.ConnectionAcquisitionTimeout != 0 {
-			config.ConnectionAcquisitionTimeout = n.config.ConnectionAcquisitionTimeout
-		}
-	})
+	driver, err := newDriver(n.uri, tokenManager, n.configureDriver)
 
 	if err != nil {
 		return err
@@ -36,9 +37,14 @@ func (n *Neo4j) connect() error {
 
 	n.driver = driver
 
-	// Verify connectivity
-	ctx := context.Background()
-	if err := n.driver.VerifyConnectivity(ctx); err != nil {
+	// Verify connectivity, bounded by the connect timeout if set
+	verifyCtx := ctx
+	if n.connectTimeout > 0 {
+		var cancel context.CancelFunc
+		verifyCtx, cancel = context.WithTimeout(ctx, n.connectTimeout)
+		defer cancel()
+	}
+	if err := n.driver.VerifyConnectivity(verifyCtx); err != nil {
 		n.driver.Close(ctx)
 		return err
 	}
@@ -46,66 +52,311 @@ func (n *Neo4j) connect() error {
 	return nil
 }
 
-// Close closes the Neo4j driver connection
+// connectWithRetry connects, trying up to attempts times with exponential backoff
+// starting at backoff. The error of the last attempt is returned.
+func (n *Neo4j) connectWithRetry(ctx context.Context, attempts int, backoff time.Duration) error {
+	delay := backoff
+	for attempt := 1; ; attempt++ {
+		err := n.connect(ctx)
+		if err == nil || attempt >= attempts || errors.Is(err, ErrInvalidURI) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %d attempts: %v", ctx.Err(), attempt, err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// configureDriver applies the instance configuration to the driver config
+func (n *Neo4j) configureDriver(config *neo4j.Config) {
+	// Apply any custom configuration
+	if n.config.MaxConnectionLifetime != 0 {
+		config.MaxConnectionLifetime = n.config.MaxConnectionLifetime
+	}
+	if n.config.MaxConnectionPoolSize != 0 {
+		config.MaxConnectionPoolSize = n.config.MaxConnectionPoolSize
+	}
+	if n.config.ConnectionAcquisitionTimeout != 0 {
+		config.ConnectionAcquisitionTimeout = n.config.ConnectionAcquisitionTimeout
+	}
+	if n.config.MaxTransactionRetryTime != 0 {
+		config.MaxTransactionRetryTime = n.config.MaxTransactionRetryTime
+	}
+	if n.connectTimeout > 0 {
+		config.SocketConnectTimeout = n.connectTimeout
+	}
+	switch {
+	case n.userAgent != "":
+		config.UserAgent = n.userAgent
+	case n.config.UserAgent != "":
+		config.UserAgent = n.config.UserAgent
+	default:
+		config.UserAgent = DefaultUserAgent
+	}
+}
+
+// logf reports an adjustment made to a write to the configured logger, if any
+func (n *Neo4j) logf(format string, v ...interface{}) {
+	if n.logger != nil {
+		n.logger.Printf(format, v...)
+	}
+}
+
+// Close stops the health check, if any, and closes the Neo4j driver connection
 func (n *Neo4j) Close() error {
+	n.stopHealthCheck()
 	if n.driver != nil {
 		return n.driver.Close(context.Background())
 	}
 	return nil
 }
 
+// withOperationTimeout bounds ctx by the per-call timeout in opts, falling back to the
+// store timeout when the call sets none. It suits operations running a single query or
+// transaction; batched operations use withBatchTimeout. The returned cancel func must
+// always be called.
+func (n *Neo4j) withOperationTimeout(ctx context.Context, opts *graphs.Options) (context.Context, context.CancelFunc) {
+	if opts.Timeout > 0 {
+		return withTimeout(ctx, time.Duration(opts.Timeout)*time.Millisecond)
+	}
+	return withTimeout(ctx, n.timeout)
+}
+
+// withBatchTimeout bounds ctx by the per-call timeout in opts only, for operations
+// running several queries. The store timeout applies to each of their queries through
+// withQueryTimeout instead of to the whole operation.
+func (n *Neo4j) withBatchTimeout(ctx context.Context, opts *graphs.Options) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, time.Duration(opts.Timeout)*time.Millisecond)
+}
+
+// withQueryTimeout bounds ctx by the store timeout, for one query of a batched operation
+func (n *Neo4j) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, n.timeout)
+}
+
+// withTimeout bounds ctx by timeout when it is positive
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Query executes a Cypher query against the Neo4j database
 func (n *Neo4j) Query(ctx context.Context, query string, params map[string]interface{}) (map[string]interface{}, error) {
+	if n.queryCache == nil {
+		return n.queryDatabase(ctx, n.database, query, params)
+	}
+
+	// Writes are never cached and invalidate cached reads
+	if checkReadOnlyQuery(query) != nil {
+		defer n.invalidateQueryCache()
+		return n.queryDatabase(ctx, n.database, query, params)
+	}
+
+	key, cacheable := queryCacheKey(n.database, query, n.withDefaultParams(params))
+	if cacheable {
+		if result, ok := n.queryCache.get(key); ok {
+			return result, nil
+		}
+	}
+
+	generation := n.queryCache.currentGeneration()
+	result, err := n.queryDatabase(ctx, n.database, query, params)
+	if err == nil && cacheable {
+		n.queryCache.put(key, result, generation)
+	}
+	return result, err
+}
+
+// QueryStream executes a Cypher query and yields its records one at a time as they
+// are pulled from the server, instead of collecting them in memory like Query.
+// Records are sanitized individually when sanitization is enabled, and results
+// are never cached. Errors are yielded with a nil record and end the iteration.
+//
+// The query runs when iteration starts. Records are pulled lazily, so a slow loop
+// body applies backpressure: the driver fetches the next batch of records, sized by
+// the driver's FetchSize, only once the current one has been consumed. Breaking out
+// of the loop or cancelling ctx stops the iteration and closes the session.
+//
+//	for record, err := range store.QueryStream(ctx, "MATCH (n) RETURN n.id AS id", nil) {
+//		if err != nil {
+//			return err
+//		}
+//		process(record)
+//	}
+func (n *Neo4j) QueryStream(ctx context.Context, query string, params map[string]interface{}) iter.Seq2[map[string]interface{}, error] {
+	return func(yield func(map[string]interface{}, error) bool) {
+		if n.driver == nil {
+			yield(nil, ErrDriverNotInitialized)
+			return
+		}
+		if checkReadOnlyQuery(query) != nil {
+			defer n.invalidateQueryCache()
+		}
+
+		session := n.driver.NewSession(ctx, n.getSessionConfig())
+		defer session.Close(ctx)
+
+		runCtx := ctx
+		if n.timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(ctx, n.timeout)
+			defer cancel()
+		}
+
+		result, err := session.Run(runCtx, query, n.withDefaultParams(params))
+		if err != nil {
+			yield(nil, fmt.Errorf("%w: %w", ErrQueryExecution, err))
+			return
+		}
+
+		for result.Next(runCtx) {
+			if err := runCtx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			record := result.Record().AsMap()
+			if n.sanitize {
+				sanitized, ok := valueSanitize(record).(map[string]interface{})
+				if !ok {
+					continue
+				}
+				record = sanitized
+			}
+			if n.sanitizeStrings {
+				record, _ = cleanValueStrings(record).(map[string]interface{})
+			}
+
+			if !yield(record, nil) {
+				return
+			}
+		}
+
+		if err := result.Err(); err != nil {
+			yield(nil, fmt.Errorf("%w: %w", ErrQueryExecution, err))
+			return
+		}
+		if err := runCtx.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// queryDatabase executes a Cypher query against the named database
+func (n *Neo4j) queryDatabase(ctx context.Context, database string, query string, params map[string]interface{}) (map[string]interface{}, error) {
 	if n.driver == nil {
 		return nil, ErrDriverNotInitialized
 	}
 
+	params = n.withDefaultParams(params)
+
 	// Create session
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: n.database,
-	})
+	config := n.getSessionConfig()
+	config.DatabaseName = database
+	session := n.driver.NewSession(ctx, config)
 	defer session.Close(ctx)
 
-	// Execute query with timeout
-	var result neo4j.ResultWithContext
-	var err error
+	// Execute query with timeout, retrying transient errors
+	var records []map[string]interface{}
+	err := n.withRetry(ctx, func() error {
+		runCtx := ctx
+		if n.timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(ctx, n.timeout)
+			defer cancel()
+		}
 
-	if n.timeout > 0 {
-		// Create a context with timeout
-		timeoutCtx, cancel := context.WithTimeout(ctx, n.timeout)
-		defer cancel()
-		result, err = session.Run(timeoutCtx, query, params)
-	} else {
-		result, err = session.Run(ctx, query, params)
-	}
+		result, err := session.Run(runCtx, query, params)
+		if err != nil {
+			return err
+		}
 
+		// Collect all records
+		records = nil
+		for result.Next(ctx) {
+			records = append(records, result.Record().AsMap())
+		}
+
+		// Check for errors during iteration
+		return result.Err()
+	})
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrQueryExecution, err)
+		return nil, fmt.Errorf("%w: %w", ErrQueryExecution, err)
 	}
 
-	// Collect all records
-	var records []map[string]interface{}
-	for result.Next(ctx) {
-		record := result.Record()
-		records = append(records, record.AsMap())
+	// Apply sanitization if enabled
+	if n.sanitize {
+		records = sanitizeRecords(records)
+	}
+	if n.sanitizeStrings {
+		records = cleanRecordStrings(records)
 	}
 
-	// Check for errors during iteration
-	if err = result.Err(); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrQueryExecution, err)
+	return map[string]interface{}{
+		"records": records,
+		"summary": map[string]interface{}{
+			"query":      query,
+			"parameters": params,
+		},
+	}, nil
+}
+
+// QueryReadOnly executes a Cypher query that must not modify the database.
+// Queries containing write clauses or write procedures are rejected with
+// ErrWriteQueryRejected before reaching the server, and accepted queries run in
+// a read transaction. Use it for untrusted Cypher such as LLM-generated queries.
+func (n *Neo4j) QueryReadOnly(ctx context.Context, query string, params map[string]interface{}) (map[string]interface{}, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	if err := checkReadOnlyQuery(query); err != nil {
+		return nil, err
+	}
+
+	params = n.withDefaultParams(params)
+
+	config := n.getSessionConfig()
+	config.AccessMode = neo4j.AccessModeRead
+	session := n.driver.NewSession(ctx, config)
+	defer session.Close(ctx)
+
+	if n.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+	}
+
+	output, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var records []map[string]interface{}
+		for result.Next(ctx) {
+			records = append(records, result.Record().AsMap())
+		}
+		return records, result.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryExecution, err)
 	}
 
+	records, _ := output.([]map[string]interface{})
+
 	// Apply sanitization if enabled
 	if n.sanitize {
-		sanitizedRecords := make([]map[string]interface{}, 0, len(records))
-		for _, record := range records {
-			if sanitized := valueSanitize(record); sanitized != nil {
-				if sanitizedMap, ok := sanitized.(map[string]interface{}); ok {
-					sanitizedRecords = append(sanitizedRecords, sanitizedMap)
-				}
-			}
-		}
-		records = sanitizedRecords
+		records = sanitizeRecords(records)
+	}
+	if n.sanitizeStrings {
+		records = cleanRecordStrings(records)
 	}
 
 	return map[string]interface{}{
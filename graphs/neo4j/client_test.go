@@ -0,0 +1,532 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+func TestUserAgentReachesDriverConfig(t *testing.T) {
+	n4j, _ := newFakeNeo4j(WithUserAgent("my-app/1.2"))
+
+	var config neo4j.Config
+	n4j.configureDriver(&config)
+
+	if config.UserAgent != "my-app/1.2" {
+		t.Errorf("Expected user agent %q, got %q", "my-app/1.2", config.UserAgent)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	n4j, _ := newFakeNeo4j()
+
+	var config neo4j.Config
+	n4j.configureDriver(&config)
+
+	if config.UserAgent != DefaultUserAgent {
+		t.Errorf("Expected default user agent %q, got %q", DefaultUserAgent, config.UserAgent)
+	}
+}
+
+func TestConfigUserAgentKeptWithoutWithUserAgent(t *testing.T) {
+	n4j, _ := newFakeNeo4j(WithConfig(neo4j.Config{UserAgent: "config-app/2.0"}))
+
+	var config neo4j.Config
+	n4j.configureDriver(&config)
+	if config.UserAgent != "config-app/2.0" {
+		t.Errorf("Expected the WithConfig user agent, got %q", config.UserAgent)
+	}
+
+	n4j, _ = newFakeNeo4j(WithConfig(neo4j.Config{UserAgent: "config-app/2.0"}), WithUserAgent("my-app/1.2"))
+	n4j.configureDriver(&config)
+	if config.UserAgent != "my-app/1.2" {
+		t.Errorf("Expected WithUserAgent to take precedence, got %q", config.UserAgent)
+	}
+}
+
+func TestDefaultUserAgentVersion(t *testing.T) {
+	// The module's own tests have no dependency entry for it, so Version is used
+	if DefaultUserAgent != "langchaingo-graphs/"+Version {
+		t.Errorf("Expected the fallback version in %q", DefaultUserAgent)
+	}
+}
+
+func TestQueryReadOnlyRejectsWrites(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	queries := []string{
+		"CREATE (n:Person {name: $name})",
+		"MATCH (n:Person) DETACH DELETE n",
+		"MATCH (n:Person) delete n",
+		"MATCH (n:Person) SET n.name = 'x'",
+		"MATCH (n:Person) REMOVE n.name",
+		"MERGE (n:Person {id: 1}) RETURN n",
+		"LOAD CSV FROM 'file:///x.csv' AS row RETURN row",
+		"CALL apoc.create.node(['Person'], {}) YIELD node RETURN node",
+		"CALL apoc.periodic.iterate('MATCH (n) RETURN n', 'DELETE n', {})",
+		"CALL apoc.cypher.runWrite('CREATE (n)', {})",
+		"MATCH (n) CALL { WITH n DETACH DELETE n } IN TRANSACTIONS",
+		"MATCH (n) FOREACH (x IN [1] | SET n.x = x)",
+		"DROP INDEX person_name",
+	}
+
+	for _, query := range queries {
+		_, err := n4j.QueryReadOnly(context.Background(), query, nil)
+		if !errors.Is(err, ErrWriteQueryRejected) {
+			t.Errorf("Expected %q to be rejected, got %v", query, err)
+		}
+	}
+
+	if len(driver.queries) != 0 {
+		t.Errorf("Rejected queries should not reach the database, got %d", len(driver.queries))
+	}
+}
+
+func TestQueryReadOnlyAllowsReads(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("name", "Alice")}, nil
+	}
+
+	queries := []string{
+		"MATCH (n:Person) RETURN n.name AS name",
+		"MATCH (n:Person) WHERE n.note = 'please DELETE me' RETURN n.name AS name",
+		"MATCH (n:`SET`) RETURN n.name AS name // CREATE nothing",
+		"MATCH (n:Person) WITH n.created_at AS createdAt RETURN createdAt",
+		"CALL db.labels() YIELD label RETURN label",
+		"CALL apoc.meta.data() YIELD label RETURN label",
+	}
+
+	for _, query := range queries {
+		result, err := n4j.QueryReadOnly(context.Background(), query, nil)
+		if err != nil {
+			t.Errorf("Expected %q to be allowed, got %v", query, err)
+			continue
+		}
+		records, ok := result["records"].([]map[string]interface{})
+		if !ok || len(records) != 1 || records[0]["name"] != "Alice" {
+			t.Errorf("Unexpected records for %q: %v", query, result["records"])
+		}
+	}
+
+	for _, q := range driver.queries {
+		if !q.inTx {
+			t.Errorf("Expected %q to run in a read transaction", q.query)
+		}
+	}
+}
+
+func TestQuerySanitizeStrings(t *testing.T) {
+	respond := func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord(
+			"bio", "Line 1\nLine 2\r\n\tLine 3\x00",
+			"tags", []interface{}{"a\nb"},
+			"nested", map[string]interface{}{"note": "x\ry"},
+			"age", int64(42),
+		)}, nil
+	}
+
+	n4j, driver := newFakeNeo4j(WithSanitizeStrings(true))
+	driver.respond = respond
+
+	result, err := n4j.Query(context.Background(), "MATCH (n) RETURN n.bio AS bio", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	record := result["records"].([]map[string]interface{})[0]
+	if record["bio"] != "Line 1 Line 2   Line 3" {
+		t.Errorf("Expected control characters to be normalized, got %q", record["bio"])
+	}
+	if record["tags"].([]interface{})[0] != "a b" {
+		t.Errorf("Expected strings in lists to be normalized, got %q", record["tags"])
+	}
+	if record["nested"].(map[string]interface{})["note"] != "x y" {
+		t.Errorf("Expected strings in maps to be normalized, got %q", record["nested"])
+	}
+	if record["age"] != int64(42) {
+		t.Errorf("Expected non-string values to be untouched, got %v", record["age"])
+	}
+
+	n4j, driver = newFakeNeo4j()
+	driver.respond = respond
+
+	result, err = n4j.Query(context.Background(), "MATCH (n) RETURN n.bio AS bio", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	record = result["records"].([]map[string]interface{})[0]
+	if record["bio"] != "Line 1\nLine 2\r\n\tLine 3\x00" {
+		t.Errorf("Expected strings to be untouched by default, got %q", record["bio"])
+	}
+}
+
+func TestAuthTokenProviderUsedOnReconnect(t *testing.T) {
+	tokens := []string{"first-token", "second-token"}
+	calls := 0
+	provider := func(ctx context.Context) (neo4j.AuthToken, error) {
+		token := neo4j.BearerAuth(tokens[calls])
+		calls++
+		return token, nil
+	}
+
+	var managers []auth.TokenManager
+	restore := newDriver
+	newDriver = func(target string, manager auth.TokenManager, configurers ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+		managers = append(managers, manager)
+		return &fakeDriver{}, nil
+	}
+	defer func() { newDriver = restore }()
+
+	n4j, err := NewNeo4j(WithAuthTokenProvider(provider))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Reconnect as would happen after the original token expired
+	if err := n4j.Reconnect(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(managers) != 2 {
+		t.Fatalf("Expected 2 driver connections, got %d", len(managers))
+	}
+
+	ctx := context.Background()
+	for i, want := range tokens {
+		token, err := managers[i].GetAuthToken(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if token.Tokens["credentials"] != want {
+			t.Errorf("Expected connection %d to use %q, got %v", i+1, want, token.Tokens["credentials"])
+		}
+	}
+}
+
+func TestDefaultParamsMergedIntoQuery(t *testing.T) {
+	defaults := map[string]interface{}{"tenant": "acme", "limit": 10}
+	n4j, driver := newFakeNeo4j(WithDefaultParams(defaults))
+
+	callerParams := map[string]interface{}{"limit": 5, "name": "Alice"}
+	_, err := n4j.Query(context.Background(), "MATCH (n {tenant: $tenant}) RETURN n LIMIT $limit", callerParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	params := driver.queries[0].params
+	if params["tenant"] != "acme" {
+		t.Errorf("Expected default tenant param, got %v", params)
+	}
+	if params["limit"] != 5 || params["name"] != "Alice" {
+		t.Errorf("Expected caller params to take precedence, got %v", params)
+	}
+	if _, ok := callerParams["tenant"]; ok {
+		t.Error("Caller params should not be modified")
+	}
+}
+
+func TestDefaultParamsMergedIntoImport(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithDefaultParams(map[string]interface{}{"tenant": "acme"}))
+
+	err := n4j.AddNodes(context.Background(), []graphs.Node{graphs.NewNode("alice", "Person")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	params := driver.queries[0].params
+	nodes, _ := params["nodes"].([]map[string]interface{})
+	if params["tenant"] != "acme" || len(nodes) != 1 || nodes[0]["id"] != "alice" {
+		t.Errorf("Expected default and node params, got %v", params)
+	}
+}
+
+// recordingBoltLogger collects Bolt messages
+type recordingBoltLogger struct {
+	messages []string
+}
+
+func (l *recordingBoltLogger) LogClientMessage(context string, msg string, args ...any) {
+	l.messages = append(l.messages, "C: "+msg)
+}
+
+func (l *recordingBoltLogger) LogServerMessage(context string, msg string, args ...any) {
+	l.messages = append(l.messages, "S: "+msg)
+}
+
+func TestBoltLoggerReachesSessionConfig(t *testing.T) {
+	logger := &recordingBoltLogger{}
+	n4j, driver := newFakeNeo4j(WithBoltLogging(logger))
+
+	ctx := context.Background()
+	if _, err := n4j.Query(ctx, "RETURN 1", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := n4j.QueryReadOnly(ctx, "RETURN 1", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := n4j.AddNodes(ctx, []graphs.Node{graphs.NewNode("alice", "Person")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.sessions) != 3 {
+		t.Fatalf("Expected 3 sessions, got %d", len(driver.sessions))
+	}
+	for i, config := range driver.sessions {
+		if config.BoltLogger != logger {
+			t.Errorf("Expected session %d to use the bolt logger", i)
+		}
+	}
+	if driver.sessions[1].AccessMode != neo4j.AccessModeRead {
+		t.Error("Expected the read-only session to keep its access mode")
+	}
+}
+
+func TestBoltLoggerDisabledByDefault(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	if _, err := n4j.Query(context.Background(), "RETURN 1", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if driver.sessions[0].BoltLogger != nil {
+		t.Error("Expected no bolt logger by default")
+	}
+}
+
+func TestConnectTimeoutFailsFastOnUnreachableHost(t *testing.T) {
+	restore := newDriver
+	newDriver = func(target string, manager auth.TokenManager, configurers ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+		return &fakeDriver{unreachable: true}, nil
+	}
+	defer func() { newDriver = restore }()
+
+	timeout := 50 * time.Millisecond
+	start := time.Now()
+	_, err := New(WithConnectTimeout(timeout))
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrConnectionFailed) {
+		t.Fatalf("Expected ErrConnectionFailed, got %v", err)
+	}
+	if elapsed > 10*timeout {
+		t.Errorf("Expected New to return within the connect timeout, took %v", elapsed)
+	}
+}
+
+func TestConnectTimeoutReachesDriverConfig(t *testing.T) {
+	n4j, _ := newFakeNeo4j(WithConnectTimeout(3 * time.Second))
+
+	var config neo4j.Config
+	n4j.configureDriver(&config)
+
+	if config.SocketConnectTimeout != 3*time.Second {
+		t.Errorf("Expected socket connect timeout of 3s, got %v", config.SocketConnectTimeout)
+	}
+}
+
+func TestMaxTransactionRetryTimeReachesDriverConfig(t *testing.T) {
+	var configs []neo4j.Config
+	restore := newDriver
+	newDriver = func(target string, manager auth.TokenManager, configurers ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+		config := neo4j.Config{MaxTransactionRetryTime: 30 * time.Second}
+		for _, configure := range configurers {
+			configure(&config)
+		}
+		configs = append(configs, config)
+		return &fakeDriver{}, nil
+	}
+	defer func() { newDriver = restore }()
+
+	if _, err := New(WithMaxTransactionRetryTime(5 * time.Second)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := New(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if configs[0].MaxTransactionRetryTime != 5*time.Second {
+		t.Errorf("Expected max transaction retry time of 5s, got %v", configs[0].MaxTransactionRetryTime)
+	}
+	if configs[1].MaxTransactionRetryTime != 30*time.Second {
+		t.Errorf("Expected the driver default to be kept, got %v", configs[1].MaxTransactionRetryTime)
+	}
+}
+
+// manyRecords returns a responder producing count records with an increasing id
+func manyRecords(count int) func(string, map[string]interface{}) ([]*neo4j.Record, error) {
+	return func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		records := make([]*neo4j.Record, count)
+		for i := range records {
+			records[i] = newRecord("id", int64(i), "embedding", make([]interface{}, 200))
+		}
+		return records, nil
+	}
+}
+
+func TestQueryStreamYieldsAllRecords(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithSanitize(true))
+	driver.respond = manyRecords(10000)
+
+	count := 0
+	for record, err := range n4j.QueryStream(context.Background(), "MATCH (n) RETURN n.id AS id", nil) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if record["id"] != int64(count) {
+			t.Fatalf("Expected record %d in order, got %v", count, record["id"])
+		}
+		if _, ok := record["embedding"]; ok {
+			t.Fatal("Expected oversized lists to be sanitized from each record")
+		}
+		count++
+	}
+
+	if count != 10000 {
+		t.Errorf("Expected 10000 records, got %d", count)
+	}
+	if driver.closedSessions != 1 {
+		t.Errorf("Expected the session to be closed, got %d closed sessions", driver.closedSessions)
+	}
+}
+
+func TestQueryStreamStopsOnBreak(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = manyRecords(1000)
+
+	count := 0
+	for _, err := range n4j.QueryStream(context.Background(), "MATCH (n) RETURN n.id AS id", nil) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	if count != 10 {
+		t.Errorf("Expected iteration to stop after 10 records, got %d", count)
+	}
+	if driver.closedSessions != 1 {
+		t.Errorf("Expected the session to be closed after break, got %d closed sessions", driver.closedSessions)
+	}
+}
+
+func TestQueryStreamStopsOnCancel(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = manyRecords(1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	var streamErr error
+	for _, err := range n4j.QueryStream(ctx, "MATCH (n) RETURN n.id AS id", nil) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		count++
+		if count == 5 {
+			cancel()
+		}
+	}
+
+	if !errors.Is(streamErr, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", streamErr)
+	}
+	if count != 5 {
+		t.Errorf("Expected no records after cancellation, got %d", count)
+	}
+	if driver.closedSessions != 1 {
+		t.Errorf("Expected the session to be closed after cancellation, got %d closed sessions", driver.closedSessions)
+	}
+}
+
+// flakyDriverFactory returns a driver factory counting the drivers it creates, the first
+// failures of which fail the connectivity check
+func flakyDriverFactory(failures int, created *int) func(string, auth.TokenManager, ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+	return func(target string, manager auth.TokenManager, configurers ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+		*created++
+		driver := &fakeDriver{}
+		if *created <= failures {
+			driver.setConnectivityErr(errors.New("connection refused"))
+		}
+		return driver, nil
+	}
+}
+
+func TestConnectRetries(t *testing.T) {
+	var created int
+	restore := newDriver
+	newDriver = flakyDriverFactory(2, &created)
+	defer func() { newDriver = restore }()
+
+	n4j, err := New(WithConnectRetries(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected New to eventually connect, got %v", err)
+	}
+	defer n4j.Close()
+
+	if created != 3 {
+		t.Errorf("Expected 3 connection attempts, got %d", created)
+	}
+}
+
+func TestConnectRetriesExhausted(t *testing.T) {
+	var created int
+	restore := newDriver
+	newDriver = flakyDriverFactory(5, &created)
+	defer func() { newDriver = restore }()
+
+	_, err := New(WithConnectRetries(3, time.Millisecond))
+	if !errors.Is(err, ErrConnectionFailed) || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("Expected ErrConnectionFailed with the last error, got %v", err)
+	}
+	if created != 3 {
+		t.Errorf("Expected 3 connection attempts, got %d", created)
+	}
+}
+
+func TestConnectRetriesHonorContext(t *testing.T) {
+	var created int
+	restore := newDriver
+	newDriver = flakyDriverFactory(5, &created)
+	defer func() { newDriver = restore }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewWithContext(ctx, WithConnectRetries(5, time.Hour))
+	if !errors.Is(err, ErrConnectionFailed) || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Fatalf("Expected the context deadline to end the retries, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected retries to stop with the context, took %v", elapsed)
+	}
+	if created != 1 {
+		t.Errorf("Expected a single attempt before the deadline, got %d", created)
+	}
+}
+
+func TestConnectWithoutRetries(t *testing.T) {
+	var created int
+	restore := newDriver
+	newDriver = flakyDriverFactory(1, &created)
+	defer func() { newDriver = restore }()
+
+	if _, err := New(); !errors.Is(err, ErrConnectionFailed) {
+		t.Fatalf("Expected ErrConnectionFailed, got %v", err)
+	}
+	if created != 1 {
+		t.Errorf("Expected a single connection attempt by default, got %d", created)
+	}
+}
@@ -0,0 +1,31 @@
+package neo4j
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+	"github.com/0xDezzy/langchaingo-graphs/graphs/graphstoretest"
+)
+
+// TestConformance runs the GraphStore conformance suite against the database configured
+// through the environment. The database is cleared before every subtest.
+func TestConformance(t *testing.T) {
+	if os.Getenv(Neo4jURIEnvVarName) == "" {
+		t.Skipf("%s not set", Neo4jURIEnvVarName)
+	}
+
+	graphstoretest.Run(t, func(t *testing.T) graphs.GraphStore {
+		store, err := New()
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close() })
+
+		if err := store.ClearDatabase(context.Background(), graphs.WithConfirm(true)); err != nil {
+			t.Fatalf("ClearDatabase: %v", err)
+		}
+		return store
+	})
+}
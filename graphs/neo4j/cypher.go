@@ -0,0 +1,144 @@
+package neo4j
+
+import (
+	"fmt"
+	"strings"
+)
+
+// quoteIdentifier quotes a label, relationship type or property key for use in Cypher
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// nodePattern returns the pattern matching nodes bound to variable, labeled with label if set
+func nodePattern(variable, label string) string {
+	if label == "" {
+		return "(" + variable + ")"
+	}
+	return "(" + variable + ":" + quoteIdentifier(label) + ")"
+}
+
+// relationshipPattern returns the pattern matching relationships of relType bound to
+// variable, directed from the source to the target node pattern
+func relationshipPattern(source, variable, relType, target string) string {
+	rel := variable
+	if relType != "" {
+		rel += ":" + quoteIdentifier(relType)
+	}
+	return source + "-[" + rel + "]->" + target
+}
+
+// cypherClause is a clause of a query built by cypherBuilder. The ORDER BY, SKIP and
+// LIMIT modifiers only apply to WITH and RETURN clauses.
+type cypherClause struct {
+	text    string
+	where   []string
+	orderBy []string
+	skip    int
+	limit   int
+}
+
+// cypherBuilder assembles a Cypher query clause by clause, collecting its parameters.
+// Labels and relationship types are quoted by nodePattern and relationshipPattern, and
+// SKIP is always written before LIMIT whatever the order of the calls.
+type cypherBuilder struct {
+	clauses []*cypherClause
+	params  map[string]interface{}
+}
+
+// newCypherBuilder creates an empty query builder
+func newCypherBuilder() *cypherBuilder {
+	return &cypherBuilder{params: make(map[string]interface{})}
+}
+
+// add appends a clause and returns the builder
+func (b *cypherBuilder) add(keyword string, items []string) *cypherBuilder {
+	b.clauses = append(b.clauses, &cypherClause{text: keyword + " " + strings.Join(items, ", ")})
+	return b
+}
+
+// last returns the clause modifiers apply to, or nil before the first clause
+func (b *cypherBuilder) last() *cypherClause {
+	if len(b.clauses) == 0 {
+		return nil
+	}
+	return b.clauses[len(b.clauses)-1]
+}
+
+// Match appends a MATCH clause over the given patterns
+func (b *cypherBuilder) Match(patterns ...string) *cypherBuilder {
+	return b.add("MATCH", patterns)
+}
+
+// Where adds a condition to the previous clause. Conditions of the same clause are
+// combined with AND.
+func (b *cypherBuilder) Where(condition string) *cypherBuilder {
+	if clause := b.last(); clause != nil {
+		clause.where = append(clause.where, condition)
+	}
+	return b
+}
+
+// With appends a WITH clause projecting the given items
+func (b *cypherBuilder) With(items ...string) *cypherBuilder {
+	return b.add("WITH", items)
+}
+
+// Return appends a RETURN clause projecting the given items
+func (b *cypherBuilder) Return(items ...string) *cypherBuilder {
+	return b.add("RETURN", items)
+}
+
+// OrderBy orders the rows of the previous WITH or RETURN clause
+func (b *cypherBuilder) OrderBy(items ...string) *cypherBuilder {
+	if clause := b.last(); clause != nil {
+		clause.orderBy = append(clause.orderBy, items...)
+	}
+	return b
+}
+
+// Skip skips the first n rows of the previous WITH or RETURN clause. Values of
+// zero or less are ignored.
+func (b *cypherBuilder) Skip(n int) *cypherBuilder {
+	if clause := b.last(); clause != nil && n > 0 {
+		clause.skip = n
+	}
+	return b
+}
+
+// Limit limits the previous WITH or RETURN clause to n rows. Values of zero or
+// less are ignored.
+func (b *cypherBuilder) Limit(n int) *cypherBuilder {
+	if clause := b.last(); clause != nil && n > 0 {
+		clause.limit = n
+	}
+	return b
+}
+
+// Param sets a query parameter and returns its placeholder
+func (b *cypherBuilder) Param(name string, value interface{}) string {
+	b.params[name] = value
+	return "$" + name
+}
+
+// Build returns the query and its parameters
+func (b *cypherBuilder) Build() (string, map[string]interface{}) {
+	parts := make([]string, 0, len(b.clauses))
+	for _, clause := range b.clauses {
+		part := clause.text
+		if len(clause.orderBy) > 0 {
+			part += " ORDER BY " + strings.Join(clause.orderBy, ", ")
+		}
+		if clause.skip > 0 {
+			part += fmt.Sprintf(" SKIP %d", clause.skip)
+		}
+		if clause.limit > 0 {
+			part += fmt.Sprintf(" LIMIT %d", clause.limit)
+		}
+		if len(clause.where) > 0 {
+			part += " WHERE " + strings.Join(clause.where, " AND ")
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " "), b.params
+}
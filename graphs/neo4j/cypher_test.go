@@ -0,0 +1,85 @@
+package neo4j
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCypherBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() *cypherBuilder
+		expected string
+	}{
+		{
+			name: "match and return",
+			build: func() *cypherBuilder {
+				return newCypherBuilder().Match(nodePattern("n", "Person")).Return("n")
+			},
+			expected: "MATCH (n:`Person`) RETURN n",
+		},
+		{
+			name: "skip precedes limit",
+			build: func() *cypherBuilder {
+				return newCypherBuilder().Match(nodePattern("n", "Person")).Return("n").Limit(10).Skip(20)
+			},
+			expected: "MATCH (n:`Person`) RETURN n SKIP 20 LIMIT 10",
+		},
+		{
+			name: "non-positive skip and limit are ignored",
+			build: func() *cypherBuilder {
+				return newCypherBuilder().Match(nodePattern("n", "")).Return("n").Skip(0).Limit(-1)
+			},
+			expected: "MATCH (n) RETURN n",
+		},
+		{
+			name: "conditions are combined",
+			build: func() *cypherBuilder {
+				return newCypherBuilder().Match(nodePattern("n", "Person")).
+					Where("n.age > 30").Where("n.city IS NOT NULL").
+					Return("n.id AS id").OrderBy("id")
+			},
+			expected: "MATCH (n:`Person`) WHERE n.age > 30 AND n.city IS NOT NULL RETURN n.id AS id ORDER BY id",
+		},
+		{
+			name: "modifiers apply to their projection",
+			build: func() *cypherBuilder {
+				return newCypherBuilder().Match(relationshipPattern("()", "r", "WORKS_AT", "()")).
+					With("r").Limit(5).Return("count(r) AS count")
+			},
+			expected: "MATCH ()-[r:`WORKS_AT`]->() WITH r LIMIT 5 RETURN count(r) AS count",
+		},
+		{
+			name: "identifiers are escaped",
+			build: func() *cypherBuilder {
+				return newCypherBuilder().Match(relationshipPattern(nodePattern("s", "Product Manager"), "r", "a`b", "(t)")).Return("r")
+			},
+			expected: "MATCH (s:`Product Manager`)-[r:`a``b`]->(t) RETURN r",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params := tt.build().Build()
+			if query != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, query)
+			}
+			if len(params) != 0 {
+				t.Errorf("Expected no parameters, got %v", params)
+			}
+		})
+	}
+}
+
+func TestCypherBuilderParams(t *testing.T) {
+	b := newCypherBuilder()
+	b.Match(nodePattern("n", "Person")).Where("n.id = " + b.Param("id", "alice")).Return("n")
+
+	query, params := b.Build()
+	if query != "MATCH (n:`Person`) WHERE n.id = $id RETURN n" {
+		t.Errorf("Unexpected query %q", query)
+	}
+	if !reflect.DeepEqual(params, map[string]interface{}{"id": "alice"}) {
+		t.Errorf("Unexpected parameters %v", params)
+	}
+}
@@ -0,0 +1,188 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	// DefaultCypherGenerationPrompt asks the LLM to translate a question into Cypher.
+	// {schema} and {question} are replaced with the graph schema and the question.
+	DefaultCypherGenerationPrompt = `Task: Generate a Cypher statement to query a graph database.
+Instructions:
+Use only the provided relationship types and properties in the schema.
+Do not use any other relationship types or properties that are not provided.
+Schema:
+{schema}
+Note: Do not include any explanations or apologies in your responses.
+Do not respond to any questions that might ask anything else than for you to construct a Cypher statement.
+Do not include any text except the generated Cypher statement.
+
+The question is:
+{question}`
+
+	// DefaultCypherQAPrompt asks the LLM to answer a question from query results.
+	// {context} and {question} are replaced with the records and the question.
+	DefaultCypherQAPrompt = `You are an assistant that helps to form nice and human understandable answers.
+The information part contains the provided information that you must use to construct an answer.
+The provided information is authoritative, you must never doubt it or try to use your internal knowledge to correct it.
+Make the answer sound as a response to the question. Do not mention that you based the result on the given information.
+If the provided information is empty, say that you don't know the answer.
+Information:
+{context}
+
+Question: {question}
+Helpful Answer:`
+
+	// DefaultCypherQATopK is the number of records passed to the answer prompt by default
+	DefaultCypherQATopK = 10
+)
+
+// cypherCodeBlock matches Cypher wrapped in a Markdown code block
+var cypherCodeBlock = regexp.MustCompile("(?s)```(?:cypher)?\\s*(.*?)```")
+
+// CypherQAOption configures a CypherQA.
+type CypherQAOption func(*CypherQA)
+
+// CypherQA answers natural language questions over a Neo4j graph. It asks an LLM to
+// generate Cypher from the graph schema and the question, runs the generated query and
+// asks the LLM again to phrase an answer from the returned records, like the
+// GraphCypherQAChain of langchain.
+type CypherQA struct {
+	llm   llms.Model
+	graph *Neo4j
+
+	cypherPrompt string
+	qaPrompt     string
+	topK         int
+	readOnly     bool
+	returnDirect bool
+}
+
+// CypherQAResult holds the outcome of a question answered by CypherQA.
+type CypherQAResult struct {
+	// Query is the Cypher generated for the question
+	Query string
+	// Records are the records returned by the query
+	Records []map[string]interface{}
+	// Answer is the answer phrased by the LLM, empty when results are returned directly
+	Answer string
+}
+
+// NewCypherQA creates a CypherQA using llm to generate Cypher and answers for graph.
+// Generated queries must be read-only unless WithQueryValidation(false) is given.
+func NewCypherQA(llm llms.Model, graph *Neo4j, opts ...CypherQAOption) *CypherQA {
+	qa := &CypherQA{
+		llm:          llm,
+		graph:        graph,
+		cypherPrompt: DefaultCypherGenerationPrompt,
+		qaPrompt:     DefaultCypherQAPrompt,
+		topK:         DefaultCypherQATopK,
+		readOnly:     true,
+	}
+	for _, opt := range opts {
+		opt(qa)
+	}
+	return qa
+}
+
+// WithQueryValidation sets whether generated queries must be read-only, which is the
+// default. When enabled, queries containing write clauses such as CREATE, DELETE, MERGE
+// or SET are rejected with ErrWriteQueryRejected and accepted queries run through
+// QueryReadOnly. Disabling it lets generated queries write to the graph through Query.
+func WithQueryValidation(readOnly bool) CypherQAOption {
+	return func(qa *CypherQA) {
+		qa.readOnly = readOnly
+	}
+}
+
+// WithCypherPrompt sets the prompt used to generate Cypher, see DefaultCypherGenerationPrompt.
+func WithCypherPrompt(prompt string) CypherQAOption {
+	return func(qa *CypherQA) {
+		qa.cypherPrompt = prompt
+	}
+}
+
+// WithQAPrompt sets the prompt used to answer from query results, see DefaultCypherQAPrompt.
+func WithQAPrompt(prompt string) CypherQAOption {
+	return func(qa *CypherQA) {
+		qa.qaPrompt = prompt
+	}
+}
+
+// WithTopK limits the number of records passed to the answer prompt.
+func WithTopK(topK int) CypherQAOption {
+	return func(qa *CypherQA) {
+		qa.topK = topK
+	}
+}
+
+// WithReturnDirect skips the answer prompt, returning the query records without an answer.
+func WithReturnDirect(returnDirect bool) CypherQAOption {
+	return func(qa *CypherQA) {
+		qa.returnDirect = returnDirect
+	}
+}
+
+// Run answers question by generating Cypher, executing it and, unless results are
+// returned directly, asking the LLM to phrase an answer from the records. The graph
+// schema is refreshed first when it has not been loaded yet.
+func (qa *CypherQA) Run(ctx context.Context, question string) (*CypherQAResult, error) {
+	schema := qa.graph.GetSchema()
+	if schema == "" {
+		if err := qa.graph.RefreshSchema(ctx); err != nil {
+			return nil, err
+		}
+		schema = qa.graph.GetSchema()
+	}
+
+	prompt := strings.NewReplacer("{schema}", schema, "{question}", question).Replace(qa.cypherPrompt)
+	generated, err := llms.GenerateFromSinglePrompt(ctx, qa.llm, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate cypher: %w", err)
+	}
+
+	result := &CypherQAResult{Query: extractCypher(generated)}
+	if result.Query == "" {
+		return result, nil
+	}
+
+	var output map[string]interface{}
+	if qa.readOnly {
+		output, err = qa.graph.QueryReadOnly(ctx, result.Query, nil)
+	} else {
+		output, err = qa.graph.Query(ctx, result.Query, nil)
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Records, _ = output["records"].([]map[string]interface{})
+
+	if qa.returnDirect {
+		return result, nil
+	}
+
+	records := result.Records
+	if qa.topK > 0 && len(records) > qa.topK {
+		records = records[:qa.topK]
+	}
+	prompt = strings.NewReplacer("{context}", fmt.Sprintf("%v", records), "{question}", question).Replace(qa.qaPrompt)
+	result.Answer, err = llms.GenerateFromSinglePrompt(ctx, qa.llm, prompt)
+	if err != nil {
+		return result, fmt.Errorf("failed to generate answer: %w", err)
+	}
+
+	return result, nil
+}
+
+// extractCypher returns the Cypher in an LLM response, unwrapping Markdown code blocks
+func extractCypher(text string) string {
+	if match := cypherCodeBlock.FindStringSubmatch(text); match != nil {
+		text = match[1]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,164 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// fakeLLM answers prompts with scripted responses in order and records the prompts
+type fakeLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (f *fakeLLM) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	var prompt strings.Builder
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				prompt.WriteString(text.Text)
+			}
+		}
+	}
+	f.prompts = append(f.prompts, prompt.String())
+	if len(f.responses) == 0 {
+		return nil, errors.New("no scripted response")
+	}
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: response}}}, nil
+}
+
+func (f *fakeLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func TestCypherQARun(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	n4j.schemaCache = "Node properties:\nPerson {name: STRING}"
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("name", "Bob")}, nil
+	}
+	llm := &fakeLLM{responses: []string{
+		"```cypher\nMATCH (p:Person)-[:KNOWS]->(f) RETURN f.name AS name\n```",
+		"Alice knows Bob.",
+	}}
+
+	result, err := NewCypherQA(llm, n4j).Run(context.Background(), "Who does Alice know?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Query != "MATCH (p:Person)-[:KNOWS]->(f) RETURN f.name AS name" {
+		t.Errorf("Unexpected query %q", result.Query)
+	}
+	if len(driver.queries) != 1 || driver.queries[0].query != result.Query {
+		t.Errorf("Expected the generated query to be executed, got %v", driver.queries)
+	}
+	if len(result.Records) != 1 || result.Records[0]["name"] != "Bob" {
+		t.Errorf("Unexpected records %v", result.Records)
+	}
+	if result.Answer != "Alice knows Bob." {
+		t.Errorf("Unexpected answer %q", result.Answer)
+	}
+
+	if len(llm.prompts) != 2 {
+		t.Fatalf("Expected 2 LLM calls, got %d", len(llm.prompts))
+	}
+	if !strings.Contains(llm.prompts[0], "Person {name: STRING}") || !strings.Contains(llm.prompts[0], "Who does Alice know?") {
+		t.Errorf("Expected schema and question in the cypher prompt:\n%s", llm.prompts[0])
+	}
+	if !strings.Contains(llm.prompts[1], "Bob") || !strings.Contains(llm.prompts[1], "Who does Alice know?") {
+		t.Errorf("Expected records and question in the answer prompt:\n%s", llm.prompts[1])
+	}
+}
+
+func TestCypherQAReturnDirect(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	n4j.schemaCache = "Node properties:\nPerson {name: STRING}"
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("count", int64(3))}, nil
+	}
+	llm := &fakeLLM{responses: []string{"MATCH (p:Person) RETURN count(p) AS count"}}
+
+	result, err := NewCypherQA(llm, n4j, WithReturnDirect(true)).Run(context.Background(), "How many people are there?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(llm.prompts) != 1 {
+		t.Errorf("Expected a single LLM call, got %d", len(llm.prompts))
+	}
+	if result.Answer != "" || len(result.Records) != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestCypherQAQueryValidation(t *testing.T) {
+	for _, query := range []string{
+		"CREATE (p:Person {name: 'Eve'})",
+		"MATCH (p:Person) DETACH DELETE p",
+		"MERGE (p:Person {name: 'Eve'})",
+		"MATCH (p:Person) SET p.admin = true",
+	} {
+		n4j, driver := newFakeNeo4j()
+		n4j.schemaCache = "Node properties:\nPerson {name: STRING}"
+		llm := &fakeLLM{responses: []string{query}}
+
+		result, err := NewCypherQA(llm, n4j).Run(context.Background(), "Add Eve")
+		if !errors.Is(err, ErrWriteQueryRejected) {
+			t.Errorf("Expected ErrWriteQueryRejected for %q, got %v", query, err)
+		}
+		if result == nil || result.Query != query {
+			t.Errorf("Expected the rejected query in the result, got %+v", result)
+		}
+		if len(driver.queries) != 0 {
+			t.Errorf("Expected %q not to be executed", query)
+		}
+	}
+}
+
+func TestCypherQAReadOnlyByDefault(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	n4j.schemaCache = "Node properties:\nPerson {name: STRING}"
+	llm := &fakeLLM{responses: []string{"MATCH (p:Person) RETURN p.name AS name"}}
+
+	if _, err := NewCypherQA(llm, n4j, WithReturnDirect(true)).Run(context.Background(), "Who is there?"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.sessions) != 1 || driver.sessions[0].AccessMode != neo4j.AccessModeRead {
+		t.Errorf("Expected the query to run in a read session, got %+v", driver.sessions)
+	}
+}
+
+func TestCypherQAWithoutQueryValidation(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	n4j.schemaCache = "Node properties:\nPerson {name: STRING}"
+	query := "CREATE (p:Person {name: 'Eve'})"
+	llm := &fakeLLM{responses: []string{query}}
+
+	if _, err := NewCypherQA(llm, n4j, WithQueryValidation(false), WithReturnDirect(true)).Run(context.Background(), "Add Eve"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 1 || driver.queries[0].query != query {
+		t.Errorf("Expected the write to be executed, got %+v", driver.queries)
+	}
+}
+
+func TestExtractCypher(t *testing.T) {
+	tests := map[string]string{
+		"MATCH (n) RETURN n":                      "MATCH (n) RETURN n",
+		"```\nMATCH (n) RETURN n\n```":            "MATCH (n) RETURN n",
+		"Here:\n```cypher\nMATCH (n) RETURN n```": "MATCH (n) RETURN n",
+		"  \n": "",
+	}
+	for input, want := range tests {
+		if got := extractCypher(input); got != want {
+			t.Errorf("extractCypher(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
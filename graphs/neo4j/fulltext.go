@@ -0,0 +1,141 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// FullTextScoreProperty is the property holding the relevance score of nodes returned by FullTextSearch
+const FullTextScoreProperty = "score"
+
+// luceneSpecialCharacters lists the characters with a meaning in Lucene query syntax
+const luceneSpecialCharacters = `+-&|!(){}[]^"~*?:\/`
+
+// luceneOperators matches the boolean operators of Lucene query syntax, which are only
+// operators when upper case
+var luceneOperators = regexp.MustCompile(`\b(AND|OR|NOT)\b`)
+
+// CreateFullTextIndex creates a full-text index named name over the given properties
+// of nodes with any of the given labels, unless it already exists.
+func (n *Neo4j) CreateFullTextIndex(ctx context.Context, name string, labels []string, properties []string) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("%w: invalid name %q", ErrInvalidFullTextIndex, name)
+	}
+	if len(labels) == 0 || len(properties) == 0 {
+		return fmt.Errorf("%w: at least one label and one property are required", ErrInvalidFullTextIndex)
+	}
+
+	quotedLabels := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if err := validateLabel(label); err != nil {
+			return err
+		}
+		quotedLabels = append(quotedLabels, fmt.Sprintf("`%s`", label))
+	}
+	quotedProperties := make([]string, 0, len(properties))
+	for _, property := range properties {
+		if !identifierPattern.MatchString(property) {
+			return fmt.Errorf("%w: invalid property %q", ErrInvalidFullTextIndex, property)
+		}
+		quotedProperties = append(quotedProperties, fmt.Sprintf("n.`%s`", property))
+	}
+
+	query := fmt.Sprintf("CREATE FULLTEXT INDEX `%s` IF NOT EXISTS FOR (n:%s) ON EACH [%s]",
+		name, strings.Join(quotedLabels, "|"), strings.Join(quotedProperties, ", "))
+	if _, err := n.Query(ctx, query, nil); err != nil {
+		return fmt.Errorf("failed to create full-text index: %w", err)
+	}
+	return nil
+}
+
+// FullTextSearch returns up to limit nodes of the full-text index indexName matching
+// queryString, ordered by decreasing relevance, which is set on each node as
+// FullTextScoreProperty. Lucene special characters in queryString are escaped and the
+// AND, OR and NOT operators lower-cased, so it is matched as plain terms.
+// ErrIndexNotFound is returned when the index does not exist.
+func (n *Neo4j) FullTextSearch(ctx context.Context, indexName, queryString string, limit int, options ...graphs.Option) ([]graphs.Node, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := "CALL db.index.fulltext.queryNodes($indexName, $query) YIELD node, score RETURN node, score ORDER BY score DESC"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	params := map[string]interface{}{
+		"indexName": indexName,
+		"query":     escapeLuceneQuery(queryString),
+	}
+
+	result, err := session.Run(ctx, query, n.withDefaultParams(params))
+	if err != nil {
+		return nil, wrapFullTextError(indexName, err)
+	}
+
+	var nodes []graphs.Node
+	for result.Next(ctx) {
+		record := result.Record()
+		nodeVal, _ := record.Get("node")
+		node, ok := nodeVal.(neo4j.Node)
+		if !ok {
+			continue
+		}
+		score, _ := record.Get("score")
+
+		graphNode := n.convertNeo4jNodeToGraphNode(node, nil)
+		properties := make(map[string]interface{}, len(graphNode.Properties)+1)
+		for key, value := range graphNode.Properties {
+			properties[key] = value
+		}
+		properties[FullTextScoreProperty] = score
+		graphNode.Properties = properties
+
+		nodes = append(nodes, *graphNode)
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, wrapFullTextError(indexName, err)
+	}
+
+	return nodes, nil
+}
+
+// escapeLuceneQuery escapes the Lucene special characters of a query string and
+// lower-cases its boolean operators, turning them into plain terms
+func escapeLuceneQuery(query string) string {
+	query = luceneOperators.ReplaceAllStringFunc(query, strings.ToLower)
+	var sb strings.Builder
+	for _, r := range query {
+		if strings.ContainsRune(luceneSpecialCharacters, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// wrapFullTextError distinguishes a missing full-text index from other query failures
+func wrapFullTextError(indexName string, err error) error {
+	var neo4jErr *neo4j.Neo4jError
+	if errors.As(err, &neo4jErr) && strings.Contains(strings.ToLower(neo4jErr.Msg), "no such fulltext schema index") {
+		return fmt.Errorf("%w: %s", ErrIndexNotFound, indexName)
+	}
+	return fmt.Errorf("%w: %w", ErrQueryExecution, err)
+}
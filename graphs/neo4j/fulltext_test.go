@@ -0,0 +1,133 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+func TestCreateFullTextIndex(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	err := n4j.CreateFullTextIndex(context.Background(), "entity_names", []string{"Person", "Company"}, []string{"name", "alias"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "CREATE FULLTEXT INDEX `entity_names` IF NOT EXISTS FOR (n:`Person`|`Company`) ON EACH [n.`name`, n.`alias`]"
+	if driver.queries[0].query != expected {
+		t.Errorf("Expected query %q, got %q", expected, driver.queries[0].query)
+	}
+}
+
+func TestCreateFullTextIndexRejectsInvalidInput(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	if err := n4j.CreateFullTextIndex(ctx, "bad name", []string{"Person"}, []string{"name"}); !errors.Is(err, ErrInvalidFullTextIndex) {
+		t.Errorf("Expected ErrInvalidFullTextIndex for the name, got %v", err)
+	}
+	if err := n4j.CreateFullTextIndex(ctx, "names", nil, []string{"name"}); !errors.Is(err, ErrInvalidFullTextIndex) {
+		t.Errorf("Expected ErrInvalidFullTextIndex without labels, got %v", err)
+	}
+	if err := n4j.CreateFullTextIndex(ctx, "names", []string{"Person`)"}, []string{"name"}); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Expected ErrInvalidLabel, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected invalid indexes not to reach the database, got %d queries", len(driver.queries))
+	}
+}
+
+func TestFullTextSearch(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("node", neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice", "name": "Alice"}}, "score", 2.5),
+		}, nil
+	}
+
+	nodes, err := n4j.FullTextSearch(context.Background(), "entity_names", "alice", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "CALL db.index.fulltext.queryNodes($indexName, $query) YIELD node, score RETURN node, score ORDER BY score DESC LIMIT 10"
+	if driver.queries[0].query != expected {
+		t.Errorf("Expected query %q, got %q", expected, driver.queries[0].query)
+	}
+	if len(nodes) != 1 || nodes[0].ID != "alice" || nodes[0].Properties[FullTextScoreProperty] != 2.5 {
+		t.Errorf("Expected alice with her score, got %+v", nodes)
+	}
+}
+
+func TestFullTextSearchEscapesLuceneCharacters(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	_, err := n4j.FullTextSearch(context.Background(), "entity_names", `AT&T (US) "corp"~2 a+b:c*? x\y/z [1 TO 2] {!^-||}`, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `AT\&T \(US\) \"corp\"\~2 a\+b\:c\*\? x\\y\/z \[1 TO 2\] \{\!\^\-\|\|\}`
+	if got := driver.queries[0].params["query"]; got != expected {
+		t.Errorf("Expected escaped query %q, got %q", expected, got)
+	}
+}
+
+func TestFullTextSearchLowerCasesOperators(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	_, err := n4j.FullTextSearch(context.Background(), "entity_names", "Barnes AND Noble OR NOT ORACLE", 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Barnes and Noble or not ORACLE"
+	if got := driver.queries[0].params["query"]; got != expected {
+		t.Errorf("Expected operators to be lower-cased in %q, got %q", expected, got)
+	}
+}
+
+func TestFullTextSearchTimeout(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithTimeout(time.Hour))
+	ctx := context.Background()
+
+	start := time.Now()
+	if _, err := n4j.FullTextSearch(ctx, "entity_names", "alice", 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := n4j.FullTextSearch(ctx, "entity_names", "alice", 5, graphs.WithTimeout(100)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertDeadline(t, driver.queries[0], start, time.Hour)
+	assertDeadline(t, driver.queries[1], start, 100*time.Millisecond)
+}
+
+func TestFullTextSearchMissingIndex(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, &neo4j.Neo4jError{
+			Code: "Neo.ClientError.Procedure.ProcedureCallFailed",
+			Msg:  "Failed to invoke procedure `db.index.fulltext.queryNodes`: Caused by: java.lang.IllegalArgumentException: There is no such fulltext schema index: missing",
+		}
+	}
+
+	_, err := n4j.FullTextSearch(context.Background(), "missing", "alice", 5)
+	if !errors.Is(err, ErrIndexNotFound) {
+		t.Errorf("Expected ErrIndexNotFound, got %v", err)
+	}
+
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, errors.New("connection reset")
+	}
+	_, err = n4j.FullTextSearch(context.Background(), "entity_names", "alice", 5)
+	if errors.Is(err, ErrIndexNotFound) || !errors.Is(err, ErrQueryExecution) {
+		t.Errorf("Expected a generic query failure, got %v", err)
+	}
+}
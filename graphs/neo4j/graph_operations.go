@@ -3,8 +3,10 @@ package neo4j
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tmc/langchaingo/schema"
 	"github.com/0xDezzy/langchaingo-graphs/graphs"
 )
 
@@ -14,7 +16,16 @@ func (n *Neo4j) UpdateNode(ctx context.Context, nodeID string, properties map[st
 		return ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	defer n.invalidateQueryCache()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
 	query := `
@@ -24,7 +35,7 @@ func (n *Neo4j) UpdateNode(ctx context.Context, nodeID string, properties map[st
 	`
 	params := map[string]interface{}{
 		"id":         nodeID,
-		"properties": properties,
+		"properties": n.normalizeProperties(properties),
 	}
 
 	result, err := session.Run(ctx, query, params)
@@ -45,18 +56,27 @@ func (n *Neo4j) UpdateRelationship(ctx context.Context, sourceID, targetID, relT
 		return ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	defer n.invalidateQueryCache()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
 	query := fmt.Sprintf(`
 		MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId})
 		SET r += $properties
 		RETURN r
-	`, relType)
+	`, quoteIdentifier(relType))
 	params := map[string]interface{}{
 		"sourceId":   sourceID,
 		"targetId":   targetID,
-		"properties": properties,
+		"properties": n.normalizeProperties(properties),
 	}
 
 	result, err := session.Run(ctx, query, params)
@@ -71,18 +91,141 @@ func (n *Neo4j) UpdateRelationship(ctx context.Context, sourceID, targetID, relT
 	return nil
 }
 
+// RemoveNodeProperties removes the properties named by keys from a node in the Neo4j store.
+// Keys must be plain identifiers and cannot include "id", which identifies the node.
+func (n *Neo4j) RemoveNodeProperties(ctx context.Context, nodeID string, keys []string, options ...graphs.Option) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	for _, key := range keys {
+		if key == "id" {
+			return fmt.Errorf("%w: %q identifies the node", ErrInvalidPropertyKey, key)
+		}
+	}
+	removals, err := propertyRemovals("n", keys)
+	if err != nil {
+		return err
+	}
+	if removals == "" {
+		return nil
+	}
+	defer n.invalidateQueryCache()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf(`
+		MATCH (n {id: $id})
+		REMOVE %s
+		RETURN n
+	`, removals)
+	params := map[string]interface{}{
+		"id": nodeID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return fmt.Errorf("failed to remove properties of node %s: %w", nodeID, err)
+	}
+
+	if !result.Next(ctx) {
+		return fmt.Errorf("node %s not found", nodeID)
+	}
+
+	return nil
+}
+
+// RemoveRelationshipProperties removes the properties named by keys from a relationship
+// in the Neo4j store. Keys must be plain identifiers.
+func (n *Neo4j) RemoveRelationshipProperties(ctx context.Context, sourceID, targetID, relType string, keys []string, options ...graphs.Option) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	if err := validateRelType(relType); err != nil {
+		return err
+	}
+	removals, err := propertyRemovals("r", keys)
+	if err != nil {
+		return err
+	}
+	if removals == "" {
+		return nil
+	}
+	defer n.invalidateQueryCache()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf(`
+		MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId})
+		REMOVE %s
+		RETURN r
+	`, quoteIdentifier(relType), removals)
+	params := map[string]interface{}{
+		"sourceId": sourceID,
+		"targetId": targetID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return fmt.Errorf("failed to remove properties of relationship %s-%s->%s: %w", sourceID, relType, targetID, err)
+	}
+
+	if !result.Next(ctx) {
+		return fmt.Errorf("relationship %s-%s->%s not found", sourceID, relType, targetID)
+	}
+
+	return nil
+}
+
+// propertyRemovals builds the items of a REMOVE clause dropping keys from variable
+func propertyRemovals(variable string, keys []string) (string, error) {
+	items := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if !identifierPattern.MatchString(key) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPropertyKey, key)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, fmt.Sprintf("%s.`%s`", variable, key))
+	}
+	return strings.Join(items, ", "), nil
+}
+
 // RemoveNode removes a node and all its relationships from the Neo4j store
 func (n *Neo4j) RemoveNode(ctx context.Context, nodeID string, options ...graphs.Option) error {
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
+	defer n.invalidateQueryCache()
 
 	opts := graphs.NewOptions()
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
 	var query string
@@ -116,13 +259,16 @@ func (n *Neo4j) RemoveNodes(ctx context.Context, nodeIDs []string, options ...gr
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
+	defer n.invalidateQueryCache()
 
 	opts := graphs.NewOptions()
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
 	var query string
@@ -153,19 +299,171 @@ func (n *Neo4j) RemoveNodes(ctx context.Context, nodeIDs []string, options ...gr
 	return nil
 }
 
+// ClearDatabase deletes every node and relationship. It refuses to run with
+// ErrNotConfirmed unless the WithConfirm option is set. Graphs with more nodes than
+// the batch size are deleted in batches of that many nodes, each committed in its own
+// transaction so that the deletion never has to hold the whole graph in memory.
+func (n *Neo4j) ClearDatabase(ctx context.Context, options ...graphs.Option) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	if !opts.Confirm {
+		return fmt.Errorf("%w: clearing the database requires WithConfirm(true)", ErrNotConfirmed)
+	}
+	defer n.invalidateQueryCache()
+
+	count, err := n.runCount(ctx, "MATCH (n) RETURN count(n) AS count", options)
+	if err != nil {
+		return fmt.Errorf("failed to count nodes: %w", err)
+	}
+
+	ctx, cancel := n.withBatchTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || count <= int64(batchSize) {
+		err := n.withRetry(ctx, func() error {
+			runCtx, cancel := n.withQueryTimeout(ctx)
+			defer cancel()
+			result, err := session.Run(runCtx, "MATCH (n) DETACH DELETE n", nil)
+			if err != nil {
+				return err
+			}
+			_, err = result.Consume(runCtx)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to clear database: %w", err)
+		}
+		return nil
+	}
+
+	query := "MATCH (n) WITH n LIMIT $batchSize DETACH DELETE n RETURN count(*) AS deleted"
+	params := map[string]interface{}{"batchSize": batchSize}
+	for {
+		var deleted int64
+		err := n.withRetry(ctx, func() error {
+			runCtx, cancel := n.withQueryTimeout(ctx)
+			defer cancel()
+			result, err := session.Run(runCtx, query, n.withDefaultParams(params))
+			if err != nil {
+				return err
+			}
+			record, err := result.Single(runCtx)
+			if err != nil {
+				return err
+			}
+			deletedVal, _ := record.Get("deleted")
+			deleted, _ = deletedVal.(int64)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to clear database: %w", err)
+		}
+		if deleted < int64(batchSize) {
+			return nil
+		}
+	}
+}
+
+// ReverseRelationship flips the direction of an existing relationship.
+// The relationship is deleted and recreated from target to source with its
+// properties intact, both steps running in a single transaction.
+func (n *Neo4j) ReverseRelationship(ctx context.Context, sourceID, targetID, relType string, options ...graphs.Option) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	defer n.invalidateQueryCache()
+
+	if err := validateRelType(relType); err != nil {
+		return err
+	}
+
+	deleteQuery := fmt.Sprintf(`
+		MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId})
+		WITH r, properties(r) AS properties
+		DELETE r
+		RETURN properties
+	`, relType)
+	createQuery := fmt.Sprintf(`
+		MATCH (s {id: $sourceId}), (t {id: $targetId})
+		CREATE (t)-[r:%s]->(s)
+		SET r = $properties
+	`, relType)
+
+	return n.txManager.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
+		params := map[string]interface{}{
+			"sourceId": sourceID,
+			"targetId": targetID,
+		}
+
+		result, err := tx.Run(ctx, deleteQuery, params)
+		if err != nil {
+			return fmt.Errorf("failed to remove relationship %s-%s->%s: %w", sourceID, relType, targetID, err)
+		}
+
+		records, err := result.Collect(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to remove relationship %s-%s->%s: %w", sourceID, relType, targetID, err)
+		}
+		if len(records) == 0 {
+			return fmt.Errorf("relationship %s-%s->%s not found", sourceID, relType, targetID)
+		}
+
+		for _, record := range records {
+			properties, _ := record.Values[0].(map[string]interface{})
+			createParams := map[string]interface{}{
+				"sourceId":   sourceID,
+				"targetId":   targetID,
+				"properties": properties,
+			}
+			if _, err := tx.Run(ctx, createQuery, createParams); err != nil {
+				return fmt.Errorf("failed to create relationship %s-%s->%s: %w", targetID, relType, sourceID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // RemoveRelationship removes a specific relationship from the Neo4j store
 func (n *Neo4j) RemoveRelationship(ctx context.Context, sourceID, targetID, relType string, options ...graphs.Option) error {
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	defer n.invalidateQueryCache()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
 	query := fmt.Sprintf(`
 		MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId})
 		DELETE r
-	`, relType)
+	`, quoteIdentifier(relType))
 	params := map[string]interface{}{
 		"sourceId": sourceID,
 		"targetId": targetID,
@@ -179,24 +477,105 @@ func (n *Neo4j) RemoveRelationship(ctx context.Context, sourceID, targetID, relT
 	return nil
 }
 
-// RemoveRelationships removes multiple relationships from the Neo4j store
+// RemoveRelationships removes multiple relationships from the Neo4j store in one session,
+// with one UNWIND query per relationship type and batch. Types are validated before
+// anything is removed, and a failed batch is reported with the identifiers it held.
 func (n *Neo4j) RemoveRelationships(ctx context.Context, relationships []graphs.RelationshipIdentifier, options ...graphs.Option) error {
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
-	defer session.Close(ctx)
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withBatchTimeout(ctx, opts)
+	defer cancel()
 
+	if len(relationships) == 0 {
+		return nil
+	}
+
+	// Relationship types cannot be parameterized, so identifiers are grouped by type
+	var types []string
+	groups := make(map[string][]graphs.RelationshipIdentifier)
 	for _, rel := range relationships {
-		if err := n.RemoveRelationship(ctx, rel.SourceID, rel.TargetID, rel.Type, options...); err != nil {
-			return err
+		if err := validateRelType(rel.Type); err != nil {
+			return fmt.Errorf("failed to remove relationship %s-%s->%s: %w", rel.SourceID, rel.Type, rel.TargetID, err)
+		}
+		if _, ok := groups[rel.Type]; !ok {
+			types = append(types, rel.Type)
+		}
+		groups[rel.Type] = append(groups[rel.Type], rel)
+	}
+
+	defer n.invalidateQueryCache()
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(relationships)
+	}
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	for _, relType := range types {
+		group := groups[relType]
+		query := fmt.Sprintf(`
+			UNWIND $relationships AS rel
+			MATCH (s {id: rel.sourceId})-[r:%s]->(t {id: rel.targetId})
+			DELETE r
+		`, relType)
+
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+
+			relData := make([]map[string]interface{}, 0, end-start)
+			for _, rel := range group[start:end] {
+				relData = append(relData, map[string]interface{}{
+					"sourceId": rel.SourceID,
+					"targetId": rel.TargetID,
+				})
+			}
+			params := map[string]interface{}{"relationships": relData}
+
+			err := n.withRetry(ctx, func() error {
+				runCtx, cancel := n.withQueryTimeout(ctx)
+				defer cancel()
+				result, err := session.Run(runCtx, query, params)
+				if err != nil {
+					return err
+				}
+				_, err = result.Consume(runCtx)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to remove relationships %s: %w", describeIdentifiers(group[start:end]), err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// describeIdentifiers names the relationships of a batch, abbreviating long batches
+func describeIdentifiers(identifiers []graphs.RelationshipIdentifier) string {
+	const shown = 3
+
+	var names []string
+	for i, rel := range identifiers {
+		if i == shown {
+			names = append(names, fmt.Sprintf("and %d more", len(identifiers)-shown))
+			break
+		}
+		names = append(names, fmt.Sprintf("%s-%s->%s", rel.SourceID, rel.Type, rel.TargetID))
+	}
+	return strings.Join(names, ", ")
+}
+
 // GetNode retrieves a node by its ID
 func (n *Neo4j) GetNode(ctx context.Context, nodeID string, options ...graphs.Option) (*graphs.Node, error) {
 	if n.driver == nil {
@@ -207,11 +586,13 @@ func (n *Neo4j) GetNode(ctx context.Context, nodeID string, options ...graphs.Op
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
-	query := "MATCH (n {id: $id}) RETURN n"
+	query := "MATCH (n {id: $id}) RETURN " + n.nodeReturn("n")
 	params := map[string]interface{}{
 		"id": nodeID,
 	}
@@ -228,101 +609,73 @@ func (n *Neo4j) GetNode(ctx context.Context, nodeID string, options ...graphs.Op
 	record := result.Record()
 	nodeValue := record.Values[0]
 
-	if node, ok := nodeValue.(neo4j.Node); ok {
-		return n.convertNeo4jNodeToGraphNode(node), nil
+	if node, ok := asNode(nodeValue); ok {
+		return n.convertNeo4jNodeToGraphNode(node, opts), nil
 	}
 
 	return nil, fmt.Errorf("unexpected node type returned")
 }
 
-// GetNodes retrieves multiple nodes by their IDs
-func (n *Neo4j) GetNodes(ctx context.Context, nodeIDs []string, options ...graphs.Option) ([]graphs.Node, error) {
+// GetNodeSources retrieves the source documents mentioning a node, as linked by imports
+// with IncludeSource. Document text becomes the page content and the remaining stored
+// properties other than the document id become its metadata.
+func (n *Neo4j) GetNodeSources(ctx context.Context, nodeID string, options ...graphs.Option) ([]schema.Document, error) {
 	if n.driver == nil {
 		return nil, ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
-	query := "UNWIND $ids AS id MATCH (n {id: id}) RETURN n"
+	query := "MATCH (d:Document)-[:MENTIONS]->(n {id: $id}) RETURN d ORDER BY d.id"
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
 	params := map[string]interface{}{
-		"ids": nodeIDs,
+		"id": nodeID,
 	}
 
 	result, err := session.Run(ctx, query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nodes: %w", err)
+		return nil, fmt.Errorf("failed to get sources of node %s: %w", nodeID, err)
 	}
 
-	var nodes []graphs.Node
+	var docs []schema.Document
 	for result.Next(ctx) {
-		record := result.Record()
-		if len(record.Values) > 0 {
-			nodeValue := record.Values[0]
-			if node, ok := nodeValue.(neo4j.Node); ok {
-				nodes = append(nodes, *n.convertNeo4jNodeToGraphNode(node))
-			}
+		if node, ok := result.Record().Values[0].(neo4j.Node); ok {
+			docs = append(docs, documentFromNode(node))
 		}
 	}
 
-	return nodes, nil
+	return docs, nil
 }
 
-// GetRelationships retrieves relationships between nodes
-func (n *Neo4j) GetRelationships(ctx context.Context, sourceID, targetID string, relType string, options ...graphs.Option) ([]graphs.Relationship, error) {
-	if n.driver == nil {
-		return nil, ErrDriverNotInitialized
-	}
-
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
-	defer session.Close(ctx)
-
-	var query string
-	var params map[string]interface{}
-
-	if relType != "" {
-		query = fmt.Sprintf("MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId}) RETURN s, r, t", relType)
-		params = map[string]interface{}{
-			"sourceId": sourceID,
-			"targetId": targetID,
-		}
-	} else {
-		query = "MATCH (s {id: $sourceId})-[r]->(t {id: $targetId}) RETURN s, r, t"
-		params = map[string]interface{}{
-			"sourceId": sourceID,
-			"targetId": targetID,
-		}
-	}
-
-	result, err := session.Run(ctx, query, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get relationships: %w", err)
-	}
-
-	var relationships []graphs.Relationship
-	for result.Next(ctx) {
-		record := result.Record()
-		sourceNodeVal, _ := record.Get("s")
-		sourceNode := sourceNodeVal.(neo4j.Node)
-		relationshipVal, _ := record.Get("r")
-		relationship := relationshipVal.(neo4j.Relationship)
-		targetNodeVal, _ := record.Get("t")
-		targetNode := targetNodeVal.(neo4j.Node)
-
-		rel := graphs.Relationship{
-			Source:     *n.convertNeo4jNodeToGraphNode(sourceNode),
-			Target:     *n.convertNeo4jNodeToGraphNode(targetNode),
-			Type:       relationship.Type,
-			Properties: relationship.Props,
+// documentFromNode rebuilds a source document from its stored Document node
+func documentFromNode(node neo4j.Node) schema.Document {
+	doc := schema.Document{Metadata: make(map[string]any)}
+	for key, value := range node.Props {
+		switch key {
+		case "id":
+		case "text":
+			doc.PageContent, _ = value.(string)
+		default:
+			doc.Metadata[key] = value
 		}
-		relationships = append(relationships, rel)
 	}
-
-	return relationships, nil
+	return doc
 }
 
-// GetNodesByType retrieves all nodes of a specific type
-func (n *Neo4j) GetNodesByType(ctx context.Context, nodeType string, options ...graphs.Option) ([]graphs.Node, error) {
+// GetNodes retrieves multiple nodes by their IDs. Nodes are returned in the order the
+// database yields them and IDs without a node are omitted; use GetNodesOrdered to align
+// the results with nodeIDs.
+func (n *Neo4j) GetNodes(ctx context.Context, nodeIDs []string, options ...graphs.Option) ([]graphs.Node, error) {
 	if n.driver == nil {
 		return nil, ErrDriverNotInitialized
 	}
@@ -331,21 +684,20 @@ func (n *Neo4j) GetNodesByType(ctx context.Context, nodeType string, options ...
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
-	query := fmt.Sprintf("MATCH (n:`%s`) RETURN n", nodeType)
-	if opts.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
-	}
-	if opts.Offset > 0 {
-		query += fmt.Sprintf(" SKIP %d", opts.Offset)
+	query := "UNWIND $ids AS id MATCH (n {id: id}) RETURN " + n.nodeReturn("n")
+	params := map[string]interface{}{
+		"ids": nodeIDs,
 	}
 
-	result, err := session.Run(ctx, query, nil)
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nodes by type %s: %w", nodeType, err)
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
 	}
 
 	var nodes []graphs.Node
@@ -353,8 +705,8 @@ func (n *Neo4j) GetNodesByType(ctx context.Context, nodeType string, options ...
 		record := result.Record()
 		if len(record.Values) > 0 {
 			nodeValue := record.Values[0]
-			if node, ok := nodeValue.(neo4j.Node); ok {
-				nodes = append(nodes, *n.convertNeo4jNodeToGraphNode(node))
+			if node, ok := asNode(nodeValue); ok {
+				nodes = append(nodes, *n.convertNeo4jNodeToGraphNode(node, opts))
 			}
 		}
 	}
@@ -362,8 +714,42 @@ func (n *Neo4j) GetNodesByType(ctx context.Context, nodeType string, options ...
 	return nodes, nil
 }
 
-// GetRelationshipsByType retrieves all relationships of a specific type
-func (n *Neo4j) GetRelationshipsByType(ctx context.Context, relType string, options ...graphs.Option) ([]graphs.Relationship, error) {
+// GetNodesOrdered retrieves multiple nodes by their IDs like GetNodes, returning a slice
+// aligned with nodeIDs, with nil entries for nodes that do not exist, together with the
+// missing IDs in the order they were requested.
+func (n *Neo4j) GetNodesOrdered(ctx context.Context, nodeIDs []string, options ...graphs.Option) ([]*graphs.Node, []string, error) {
+	nodes, err := n.GetNodes(ctx, nodeIDs, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[string]*graphs.Node, len(nodes))
+	for i := range nodes {
+		byID[nodes[i].ID] = &nodes[i]
+	}
+
+	ordered := make([]*graphs.Node, len(nodeIDs))
+	var missing []string
+	reported := make(map[string]bool)
+	for i, id := range nodeIDs {
+		if node, ok := byID[id]; ok {
+			ordered[i] = node
+			continue
+		}
+		if !reported[id] {
+			reported[id] = true
+			missing = append(missing, id)
+		}
+	}
+	return ordered, missing, nil
+}
+
+// GetNodesWithContext retrieves the nodes with the given IDs together with their
+// relationships and neighbor nodes, one hop away in either direction, in a single query.
+// The returned GraphDocument lists the requested nodes that exist in the order of
+// nodeIDs, followed by their neighbors; each relationship keeps its stored orientation
+// and appears once, even when it connects two requested nodes.
+func (n *Neo4j) GetNodesWithContext(ctx context.Context, nodeIDs []string, options ...graphs.Option) (*graphs.GraphDocument, error) {
 	if n.driver == nil {
 		return nil, ErrDriverNotInitialized
 	}
@@ -372,40 +758,505 @@ func (n *Neo4j) GetRelationshipsByType(ctx context.Context, relType string, opti
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
-	defer session.Close(ctx)
+	doc := graphs.NewGraphDocument(schema.Document{})
 
-	query := fmt.Sprintf("MATCH (s)-[r:%s]->(t) RETURN s, r, t", relType)
+	ids := make([]string, 0, len(nodeIDs))
+	requested := make(map[string]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		if !requested[id] {
+			requested[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return &doc, nil
+	}
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := "UNWIND $ids AS id MATCH (n {id: id}) OPTIONAL MATCH p = (n)-[]-() " +
+		"RETURN " + n.nodeReturn("n") + ", collect(p) AS paths"
+	result, err := session.Run(ctx, query, map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes with context: %w", err)
+	}
+
+	// Rows come back in no particular order, so centers are added in the order of ids
+	// once all rows are read
+	centers := make(map[string]graphs.Node, len(ids))
+	var neighbors []graphs.Node
+	seenNodes := make(map[string]bool)
+	seenRels := make(map[string]bool)
+	for result.Next(ctx) {
+		record := result.Record()
+		nodeValue, _ := record.Get("n")
+		node, ok := asNode(nodeValue)
+		if !ok {
+			continue
+		}
+		center := n.convertNeo4jNodeToGraphNode(node, opts)
+		if !seenNodes[center.ID] {
+			seenNodes[center.ID] = true
+			centers[center.ID] = *center
+		}
+
+		pathsValue, _ := record.Get("paths")
+		paths, _ := pathsValue.([]interface{})
+		for _, pathValue := range paths {
+			path, ok := pathValue.(neo4j.Path)
+			if !ok || len(path.Nodes) != 2 || len(path.Relationships) != 1 {
+				continue
+			}
+			rel := path.Relationships[0]
+			source, target := path.Nodes[0], path.Nodes[1]
+			if rel.StartElementId == target.ElementId && rel.EndElementId == source.ElementId {
+				source, target = target, source
+			}
+
+			relationship := graphs.Relationship{
+				Source:     *n.convertNeo4jNodeToGraphNode(source, opts),
+				Target:     *n.convertNeo4jNodeToGraphNode(target, opts),
+				Type:       rel.Type,
+				Properties: filterProperties(rel.Props, opts),
+			}
+			key := fmt.Sprintf("%s|%s|%s|%s", rel.ElementId, relationship.Source.ID, rel.Type, relationship.Target.ID)
+			if !seenRels[key] {
+				seenRels[key] = true
+				doc.AddRelationship(relationship)
+			}
+
+			neighbor := relationship.Target
+			if neighbor.ID == center.ID {
+				neighbor = relationship.Source
+			}
+			if !seenNodes[neighbor.ID] && !requested[neighbor.ID] {
+				seenNodes[neighbor.ID] = true
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get nodes with context: %w", err)
+	}
+
+	for _, id := range ids {
+		if center, ok := centers[id]; ok {
+			doc.AddNode(center)
+		}
+	}
+	for _, neighbor := range neighbors {
+		doc.AddNode(neighbor)
+	}
+	return &doc, nil
+}
+
+// GetRelationships retrieves relationships between nodes. The Direction option selects
+// whether relationships stored from sourceID to targetID, from targetID to sourceID or
+// either way are matched; each returned relationship keeps its stored orientation, so
+// its Source may be the node identified by targetID.
+func (n *Neo4j) GetRelationships(ctx context.Context, sourceID, targetID string, relType string, options ...graphs.Option) ([]graphs.Relationship, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := relationshipMatchQuery(relType, opts.Direction)
+	params := map[string]interface{}{
+		"sourceId": sourceID,
+		"targetId": targetID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+
+	var relationships []graphs.Relationship
+	for result.Next(ctx) {
+		// Records with a null endpoint, e.g. one deleted concurrently, are skipped
+		if rel, ok := n.convertRecordToRelationship(result.Record(), opts); ok {
+			relationships = append(relationships, rel)
+		}
+	}
+
+	if len(relationships) == 0 && opts.FullPathDepth > 0 {
+		return n.getConnectingPath(ctx, session, sourceID, targetID, relType, opts)
+	}
+
+	return relationships, nil
+}
+
+// getConnectingPath retrieves the relationships of a shortest path between the nodes,
+// bounded by opts.FullPathDepth and following opts.Direction, in order from the source
+func (n *Neo4j) getConnectingPath(ctx context.Context, session neo4j.SessionWithContext, sourceID, targetID string, relType string, opts *graphs.Options) ([]graphs.Relationship, error) {
+	query := fmt.Sprintf(`
+		MATCH (a {id: $sourceId}), (b {id: $targetId})
+		MATCH p = shortestPath((a)%s(b))
+		UNWIND relationships(p) AS r
+		RETURN startNode(r) AS s, r, endNode(r) AS t
+	`, pathPattern(relType, opts.FullPathDepth, opts.Direction))
+	params := map[string]interface{}{
+		"sourceId": sourceID,
+		"targetId": targetID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connecting path: %w", err)
+	}
+
+	var relationships []graphs.Relationship
+	for result.Next(ctx) {
+		if rel, ok := n.convertRecordToRelationship(result.Record(), opts); ok {
+			relationships = append(relationships, rel)
+		}
+	}
+
+	return relationships, nil
+}
+
+// pathPattern returns a variable-length relationship pattern of up to maxDepth hops
+func pathPattern(relType string, maxDepth int, direction graphs.Direction) string {
+	rel := fmt.Sprintf("[*..%d]", maxDepth)
+	if relType != "" {
+		rel = fmt.Sprintf("[:%s*..%d]", quoteIdentifier(relType), maxDepth)
+	}
+
+	switch direction {
+	case graphs.DirectionIncoming:
+		return "<-" + rel + "-"
+	case graphs.DirectionBoth:
+		return "-" + rel + "-"
+	default:
+		return "-" + rel + "->"
+	}
+}
+
+// RelationshipsExist checks which of the identified relationships exist in a single query.
+// Every identifier is present in the returned map. An identifier with an empty Type
+// matches a relationship of any type between its nodes. Types are normalized the way
+// imports store them, so "works at" matches a stored WORKS_AT relationship.
+func (n *Neo4j) RelationshipsExist(ctx context.Context, ids []graphs.RelationshipIdentifier, options ...graphs.Option) (map[graphs.RelationshipIdentifier]bool, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	exists := make(map[graphs.RelationshipIdentifier]bool, len(ids))
+	for _, id := range ids {
+		exists[id] = false
+	}
+	if len(ids) == 0 {
+		return exists, nil
+	}
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := `
+		UNWIND $pairs AS p
+		OPTIONAL MATCH (s {id: p.source})-[r]->(t {id: p.target})
+		WHERE p.type = "" OR type(r) = p.type
+		RETURN p.index AS index, count(r) > 0 AS exists
+	`
+	params := map[string]interface{}{
+		"pairs": relationshipIdentifierData(ids),
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check relationships: %w", err)
+	}
+
+	for result.Next(ctx) {
+		record := result.Record()
+		index, _ := record.Values[0].(int64)
+		found, _ := record.Values[1].(bool)
+		if found && index >= 0 && index < int64(len(ids)) {
+			exists[ids[index]] = true
+		}
+	}
+
+	return exists, nil
+}
+
+// relationshipIdentifierData prepares relationship identifiers as query parameters. The
+// index of every identifier is passed through so results map back to the unnormalized type.
+func relationshipIdentifierData(ids []graphs.RelationshipIdentifier) []map[string]interface{} {
+	data := make([]map[string]interface{}, 0, len(ids))
+	for i, id := range ids {
+		data = append(data, map[string]interface{}{
+			"index":  i,
+			"source": id.SourceID,
+			"target": id.TargetID,
+			"type":   normalizeRelType(id.Type),
+		})
+	}
+	return data
+}
+
+// relationshipMatchQuery returns the query matching relationships between $sourceId and $targetId
+// in the given direction. Source and target of the results always follow the stored direction.
+func relationshipMatchQuery(relType string, direction graphs.Direction) string {
+	rel := "r"
+	if relType != "" {
+		rel = "r:" + quoteIdentifier(relType)
+	}
+
+	switch direction {
+	case graphs.DirectionIncoming:
+		return fmt.Sprintf("MATCH (a {id: $sourceId})<-[%s]-(b {id: $targetId}) RETURN startNode(r) AS s, r, endNode(r) AS t", rel)
+	case graphs.DirectionBoth:
+		return fmt.Sprintf("MATCH (a {id: $sourceId})-[%s]-(b {id: $targetId}) RETURN startNode(r) AS s, r, endNode(r) AS t", rel)
+	default:
+		return fmt.Sprintf("MATCH (s {id: $sourceId})-[%s]->(t {id: $targetId}) RETURN s, r, t", rel)
+	}
+}
+
+// GetRelationshipsBatch retrieves the relationships for many source/target pairs in a single query.
+// A pair with an empty Type matches relationships of any type between its nodes. Types are
+// normalized as in RelationshipsExist.
+func (n *Neo4j) GetRelationshipsBatch(ctx context.Context, pairs []graphs.RelationshipIdentifier, options ...graphs.Option) ([]graphs.Relationship, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	pairData := relationshipIdentifierData(pairs)
+
+	query := `
+		UNWIND $pairs AS p
+		MATCH (s {id: p.source})-[r]->(t {id: p.target})
+		WHERE p.type = "" OR type(r) = p.type
+		RETURN s, r, t
+	`
+	params := map[string]interface{}{
+		"pairs": pairData,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relationships: %w", err)
+	}
+
+	var relationships []graphs.Relationship
+	for result.Next(ctx) {
+		if rel, ok := n.convertRecordToRelationship(result.Record(), opts); ok {
+			relationships = append(relationships, rel)
+		}
+	}
+
+	return relationships, nil
+}
+
+// GetNodesByType retrieves all nodes of a specific type
+func (n *Neo4j) GetNodesByType(ctx context.Context, nodeType string, options ...graphs.Option) ([]graphs.Node, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query, params := newCypherBuilder().
+		Match(nodePattern("n", nodeType)).
+		Return(n.nodeReturn("n")).
+		Skip(opts.Offset).
+		Limit(opts.Limit).
+		Build()
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes by type %s: %w", nodeType, err)
+	}
+
+	var nodes []graphs.Node
+	for result.Next(ctx) {
+		record := result.Record()
+		if len(record.Values) > 0 {
+			nodeValue := record.Values[0]
+			if node, ok := asNode(nodeValue); ok {
+				nodes = append(nodes, *n.convertNeo4jNodeToGraphNode(node, opts))
+			}
+		}
+	}
+
+	return nodes, nil
+}
+
+// GetNodeIDsByType retrieves the IDs of all nodes of a specific type, ordered by ID.
+// Only the IDs are returned by the query, which makes it much cheaper than
+// GetNodesByType for scanning or diffing large node sets. Limit and offset apply
+// to the ordered IDs.
+func (n *Neo4j) GetNodeIDsByType(ctx context.Context, nodeType string, options ...graphs.Option) ([]string, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	if err := validateLabel(nodeType); err != nil {
+		return nil, err
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf("MATCH (n:%s) RETURN n.id AS id ORDER BY id", quoteIdentifier(nodeType))
+	if opts.Offset > 0 {
+		query += fmt.Sprintf(" SKIP %d", opts.Offset)
+	}
 	if opts.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
 	}
+
+	result, err := session.Run(ctx, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node ids by type %s: %w", nodeType, err)
+	}
+
+	var ids []string
+	for result.Next(ctx) {
+		record := result.Record()
+		if len(record.Values) > 0 {
+			if id, ok := record.Values[0].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// GetNodesWhere retrieves nodes of a specific type that satisfy a WHERE predicate.
+// The predicate must reference the node as n and take its values from params.
+func (n *Neo4j) GetNodesWhere(ctx context.Context, nodeType string, predicate string, params map[string]interface{}, options ...graphs.Option) ([]graphs.Node, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	if err := validateLabel(nodeType); err != nil {
+		return nil, err
+	}
+	if err := validatePredicate(predicate, params); err != nil {
+		return nil, err
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf("MATCH (n:%s) WHERE %s RETURN %s", quoteIdentifier(nodeType), predicate, n.nodeReturn("n"))
 	if opts.Offset > 0 {
 		query += fmt.Sprintf(" SKIP %d", opts.Offset)
 	}
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
 
-	result, err := session.Run(ctx, query, nil)
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get relationships by type %s: %w", relType, err)
+		return nil, fmt.Errorf("failed to get nodes by predicate for type %s: %w", nodeType, err)
 	}
 
-	var relationships []graphs.Relationship
+	var nodes []graphs.Node
 	for result.Next(ctx) {
 		record := result.Record()
-		sourceNodeVal, _ := record.Get("s")
-		sourceNode := sourceNodeVal.(neo4j.Node)
-		relationshipVal, _ := record.Get("r")
-		relationship := relationshipVal.(neo4j.Relationship)
-		targetNodeVal, _ := record.Get("t")
-		targetNode := targetNodeVal.(neo4j.Node)
+		if len(record.Values) > 0 {
+			nodeValue := record.Values[0]
+			if node, ok := asNode(nodeValue); ok {
+				nodes = append(nodes, *n.convertNeo4jNodeToGraphNode(node, opts))
+			}
+		}
+	}
+
+	return nodes, nil
+}
+
+// GetRelationshipsByType retrieves all relationships of a specific type
+func (n *Neo4j) GetRelationshipsByType(ctx context.Context, relType string, options ...graphs.Option) ([]graphs.Relationship, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query, params := newCypherBuilder().
+		Match(relationshipPattern("(s)", "r", relType, "(t)")).
+		Return("s", "r", "t").
+		Skip(opts.Offset).
+		Limit(opts.Limit).
+		Build()
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relationships by type %s: %w", relType, err)
+	}
 
-		rel := graphs.Relationship{
-			Source:     *n.convertNeo4jNodeToGraphNode(sourceNode),
-			Target:     *n.convertNeo4jNodeToGraphNode(targetNode),
-			Type:       relationship.Type,
-			Properties: relationship.Props,
+	var relationships []graphs.Relationship
+	for result.Next(ctx) {
+		// Records with a null endpoint, e.g. one deleted concurrently, are skipped
+		if rel, ok := n.convertRecordToRelationship(result.Record(), opts); ok {
+			relationships = append(relationships, rel)
 		}
-		relationships = append(relationships, rel)
 	}
 
 	return relationships, nil
@@ -417,7 +1268,14 @@ func (n *Neo4j) NodeExists(ctx context.Context, nodeID string, options ...graphs
 		return false, ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
 	query := "MATCH (n {id: $id}) RETURN count(n) > 0 as exists"
@@ -440,16 +1298,29 @@ func (n *Neo4j) NodeExists(ctx context.Context, nodeID string, options ...graphs
 	return false, nil
 }
 
-// RelationshipExists checks if a relationship exists in the Neo4j store
+// RelationshipExists checks if a relationship exists in the Neo4j store. As in
+// RelationshipsExist, the type is normalized the way imports store it, and an empty
+// type matches relationships of any type.
 func (n *Neo4j) RelationshipExists(ctx context.Context, sourceID, targetID, relType string, options ...graphs.Option) (bool, error) {
 	if n.driver == nil {
 		return false, ErrDriverNotInitialized
 	}
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
-	query := fmt.Sprintf("MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId}) RETURN count(r) > 0 as exists", relType)
+	pattern := "[r]"
+	if relType = normalizeRelType(relType); relType != "" {
+		pattern = fmt.Sprintf("[r:%s]", quoteIdentifier(relType))
+	}
+	query := fmt.Sprintf("MATCH (s {id: $sourceId})-%s->(t {id: $targetId}) RETURN count(r) > 0 as exists", pattern)
 	params := map[string]interface{}{
 		"sourceId": sourceID,
 		"targetId": targetID,
@@ -470,19 +1341,141 @@ func (n *Neo4j) RelationshipExists(ctx context.Context, sourceID, targetID, relT
 	return false, nil
 }
 
-// convertNeo4jNodeToGraphNode converts a Neo4j node to a graphs.Node
-func (n *Neo4j) convertNeo4jNodeToGraphNode(node neo4j.Node) *graphs.Node {
-	// Get the first label as the node type (Neo4j nodes can have multiple labels)
-	var nodeType string
-	if len(node.Labels) > 0 {
-		// Skip the base entity label if present
-		for _, label := range node.Labels {
-			if label != BASE_ENTITY_LABEL {
-				nodeType = label
-				break
-			}
+// CountNodes counts the nodes of a type in the Neo4j store, or all nodes if nodeType is empty
+func (n *Neo4j) CountNodes(ctx context.Context, nodeType string, options ...graphs.Option) (int64, error) {
+	query := "MATCH (n) RETURN count(n) AS count"
+	if nodeType != "" {
+		if err := validateLabel(nodeType); err != nil {
+			return 0, err
+		}
+		query = fmt.Sprintf("MATCH (n:%s) RETURN count(n) AS count", nodeType)
+	}
+
+	count, err := n.runCount(ctx, query, options)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count nodes: %w", err)
+	}
+	return count, nil
+}
+
+// CountRelationships counts the relationships of a type in the Neo4j store, or all
+// relationships if relType is empty
+func (n *Neo4j) CountRelationships(ctx context.Context, relType string, options ...graphs.Option) (int64, error) {
+	query := "MATCH ()-[r]->() RETURN count(r) AS count"
+	if relType != "" {
+		if err := validateRelType(relType); err != nil {
+			return 0, err
+		}
+		query = fmt.Sprintf("MATCH ()-[r:%s]->() RETURN count(r) AS count", relType)
+	}
+
+	count, err := n.runCount(ctx, query, options)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count relationships: %w", err)
+	}
+	return count, nil
+}
+
+// DistinctPropertyValues returns up to limit distinct values of a property across the nodes
+// of a type, in ascending order, for example to offer them as filters. Nodes without the
+// property are ignored, and a limit of zero or less returns every distinct value.
+func (n *Neo4j) DistinctPropertyValues(ctx context.Context, nodeType, propertyKey string, limit int, options ...graphs.Option) ([]interface{}, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	if err := validateLabel(nodeType); err != nil {
+		return nil, err
+	}
+	if !identifierPattern.MatchString(propertyKey) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPropertyKey, propertyKey)
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf(`
+		MATCH (n:%s)
+		WHERE n.%s IS NOT NULL
+		RETURN DISTINCT n.%s AS value
+		ORDER BY value`, nodeType, propertyKey, propertyKey)
+	params := map[string]interface{}{}
+	if limit > 0 {
+		query += "\n\t\tLIMIT $limit"
+		params["limit"] = limit
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get distinct values of %s.%s: %w", nodeType, propertyKey, err)
+	}
+
+	var values []interface{}
+	for result.Next(ctx) {
+		value, _ := result.Record().Get("value")
+		values = append(values, value)
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get distinct values of %s.%s: %w", nodeType, propertyKey, err)
+	}
+
+	return values, nil
+}
+
+// runCount runs a query returning a single count column
+func (n *Neo4j) runCount(ctx context.Context, query string, options []graphs.Option) (int64, error) {
+	if n.driver == nil {
+		return 0, ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	record, err := result.Single(ctx)
+	if err != nil {
+		return 0, err
+	}
+	countVal, _ := record.Get("count")
+	count, _ := countVal.(int64)
+	return count, nil
+}
+
+// convertNeo4jNodeToGraphNode converts a Neo4j node to a graphs.Node, keeping the
+// properties selected by the include and exclude options of opts, which may be nil
+func (n *Neo4j) convertNeo4jNodeToGraphNode(node neo4j.Node, opts *graphs.Options) *graphs.Node {
+	// Derive the node type and labels from the labels other than the base entity label
+	// (Neo4j nodes can have multiple labels)
+	labels := make([]string, 0, len(node.Labels))
+	for _, label := range node.Labels {
+		if label != BASE_ENTITY_LABEL {
+			labels = append(labels, label)
 		}
 	}
+	var nodeType string
+	if n.nodeTypeSelector != nil {
+		nodeType = n.nodeTypeSelector(labels)
+	} else if len(labels) > 0 {
+		nodeType = labels[0]
+	}
 
 	// Get node ID from properties
 	nodeID := ""
@@ -492,11 +1485,42 @@ func (n *Neo4j) convertNeo4jNodeToGraphNode(node neo4j.Node) *graphs.Node {
 		}
 	}
 
-	return &graphs.Node{
+	graphNode := &graphs.Node{
 		ID:         nodeID,
 		Type:       nodeType,
-		Properties: node.Props,
+		Properties: filterProperties(node.Props, opts),
+	}
+	if len(labels) > 0 {
+		graphNode.Labels = labels
 	}
+	return graphNode
+}
+
+// convertRecordToRelationship converts a record with s, r and t values to a graphs.Relationship,
+// filtering the properties of the relationship and its endpoints like convertNeo4jNodeToGraphNode
+func (n *Neo4j) convertRecordToRelationship(record *neo4j.Record, opts *graphs.Options) (graphs.Relationship, bool) {
+	sourceNodeVal, _ := record.Get("s")
+	sourceNode, ok := sourceNodeVal.(neo4j.Node)
+	if !ok {
+		return graphs.Relationship{}, false
+	}
+	relationshipVal, _ := record.Get("r")
+	relationship, ok := relationshipVal.(neo4j.Relationship)
+	if !ok {
+		return graphs.Relationship{}, false
+	}
+	targetNodeVal, _ := record.Get("t")
+	targetNode, ok := targetNodeVal.(neo4j.Node)
+	if !ok {
+		return graphs.Relationship{}, false
+	}
+
+	return graphs.Relationship{
+		Source:     *n.convertNeo4jNodeToGraphNode(sourceNode, opts),
+		Target:     *n.convertNeo4jNodeToGraphNode(targetNode, opts),
+		Type:       relationship.Type,
+		Properties: filterProperties(relationship.Props, opts),
+	}, true
 }
 
 // GetStructuredSchema returns the structured schema information.
@@ -0,0 +1,1491 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+func TestGetNodesWhere(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("n", neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice", "age": int64(42)}}),
+		}, nil
+	}
+
+	params := map[string]interface{}{"minAge": 30}
+	nodes, err := n4j.GetNodesWhere(context.Background(), "Person", "n.age >= $minAge", params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(nodes) != 1 || nodes[0].ID != "alice" || nodes[0].Type != "Person" {
+		t.Errorf("Unexpected nodes: %+v", nodes)
+	}
+
+	if len(driver.queries) != 1 {
+		t.Fatalf("Expected 1 query, got %d", len(driver.queries))
+	}
+	expected := "MATCH (n:`Person`) WHERE n.age >= $minAge RETURN n"
+	if driver.queries[0].query != expected {
+		t.Errorf("Expected query %q, got %q", expected, driver.queries[0].query)
+	}
+	if driver.queries[0].params["minAge"] != 30 {
+		t.Errorf("Expected params to be passed through, got %v", driver.queries[0].params)
+	}
+}
+
+func TestGetNodesWhereRejectsUnsafePredicates(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	tests := []struct {
+		name      string
+		predicate string
+		params    map[string]interface{}
+	}{
+		{"detach delete", "n.age > 1 DETACH DELETE n", nil},
+		{"semicolon", "n.age > 1; MATCH (m) DELETE m", nil},
+		{"call", "n.id IN [x IN $ids | x] CALL db.labels()", map[string]interface{}{"ids": []string{"a"}}},
+		{"with clause", "n.name STARTS WITH $p WITH n MATCH (m) DETACH DELETE m", map[string]interface{}{"p": "A"}},
+		{"bare with", "n.age > 1 WITH n", nil},
+		{"string literal", "n.name = 'alice'", nil},
+		{"missing parameter", "n.name = $name", nil},
+		{"no node reference", "1 = 1", nil},
+		{"empty", "  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := n4j.GetNodesWhere(context.Background(), "Person", tt.predicate, tt.params)
+			if !errors.Is(err, ErrInvalidPredicate) {
+				t.Errorf("Expected ErrInvalidPredicate, got %v", err)
+			}
+		})
+	}
+
+	if len(driver.queries) != 0 {
+		t.Errorf("Rejected predicates should not reach the database, got %d queries", len(driver.queries))
+	}
+}
+
+func TestValidatePredicateAllowsStringOperators(t *testing.T) {
+	params := map[string]interface{}{"p": "Al"}
+	for _, predicate := range []string{
+		"n.name STARTS WITH $p",
+		"n.name ENDS WITH $p",
+		"n.name starts  with $p OR n.name ends with $p",
+	} {
+		if err := validatePredicate(predicate, params); err != nil {
+			t.Errorf("validatePredicate(%q): unexpected error: %v", predicate, err)
+		}
+	}
+}
+
+func TestValidatePredicateReportsKeyword(t *testing.T) {
+	err := validatePredicate("n.age > 1 detach delete n", nil)
+	if err == nil || !strings.Contains(err.Error(), "DETACH") {
+		t.Errorf("Expected error naming DETACH, got %v", err)
+	}
+}
+
+func TestReverseRelationship(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "DELETE r") {
+			return []*neo4j.Record{newRecord("properties", map[string]interface{}{"since": "2020"})}, nil
+		}
+		return nil, nil
+	}
+
+	if err := n4j.ReverseRelationship(context.Background(), "alice", "bob", "KNOWS"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(driver.queries))
+	}
+	for _, q := range driver.queries {
+		if !q.inTx {
+			t.Errorf("Expected query to run inside a transaction: %s", q.query)
+		}
+	}
+
+	if !strings.Contains(driver.queries[0].query, "(s {id: $sourceId})-[r:KNOWS]->(t {id: $targetId})") ||
+		!strings.Contains(driver.queries[0].query, "DELETE r") {
+		t.Errorf("Expected first query to delete the original relationship, got %s", driver.queries[0].query)
+	}
+	if !strings.Contains(driver.queries[1].query, "CREATE (t)-[r:KNOWS]->(s)") {
+		t.Errorf("Expected second query to create the reversed relationship, got %s", driver.queries[1].query)
+	}
+
+	props, ok := driver.queries[1].params["properties"].(map[string]interface{})
+	if !ok || props["since"] != "2020" {
+		t.Errorf("Expected properties to be preserved, got %v", driver.queries[1].params["properties"])
+	}
+	if driver.commits != 1 || driver.rollbacks != 0 {
+		t.Errorf("Expected a single commit, got %d commits and %d rollbacks", driver.commits, driver.rollbacks)
+	}
+}
+
+func TestReverseRelationshipNotFound(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	err := n4j.ReverseRelationship(context.Background(), "alice", "bob", "KNOWS")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected only the delete query to run, got %d queries", len(driver.queries))
+	}
+	if driver.rollbacks != 1 {
+		t.Errorf("Expected the transaction to roll back, got %d rollbacks", driver.rollbacks)
+	}
+}
+
+func TestReverseRelationshipInvalidType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	err := n4j.ReverseRelationship(context.Background(), "alice", "bob", "KNOWS]->() DETACH DELETE s //")
+	if !errors.Is(err, ErrInvalidRelType) {
+		t.Fatalf("Expected ErrInvalidRelType, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestRelationshipOperationsQuoteType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+	relType := "KNOWS`]->() DETACH DELETE s //"
+
+	// The fake returns no rows, so the updates report the relationship as missing
+	_ = n4j.UpdateRelationship(ctx, "alice", "bob", relType, map[string]interface{}{"since": 2020})
+	_ = n4j.RemoveRelationship(ctx, "alice", "bob", relType)
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(driver.queries))
+	}
+	for _, q := range driver.queries {
+		if !strings.Contains(q.query, "[r:`KNOWS``]->() DETACH DELETE s //`]") {
+			t.Errorf("Expected the relationship type to be quoted, got %s", q.query)
+		}
+	}
+}
+
+func TestRelationshipExistsNormalizesType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	for _, relType := range []string{"works at", "KNOWS`]->() DETACH DELETE s //", ""} {
+		if _, err := n4j.RelationshipExists(ctx, "alice", "bob", relType); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{
+		"-[r:`WORKS_AT`]->",
+		"-[r:`KNOWS]->()_DETACH_DELETE_S_//`]->",
+		"-[r]->",
+	}
+	for i, want := range expected {
+		if !strings.Contains(driver.queries[i].query, want) {
+			t.Errorf("Expected query %d to contain %s, got %s", i, want, driver.queries[i].query)
+		}
+	}
+}
+
+func TestGetRelationshipsBatch(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	bob := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "bob"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "KNOWS", Props: map[string]interface{}{"since": "2020"}}, "t", bob),
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+			newRecord("s", bob, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+		}, nil
+	}
+
+	pairs := []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "bob", Type: "KNOWS"},
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"},
+		{SourceID: "bob", TargetID: "acme"},
+	}
+
+	relationships, err := n4j.GetRelationshipsBatch(context.Background(), pairs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 1 {
+		t.Fatalf("Expected a single query for all pairs, got %d", len(driver.queries))
+	}
+	if !strings.Contains(driver.queries[0].query, "UNWIND $pairs AS p") {
+		t.Errorf("Expected an UNWIND query, got %s", driver.queries[0].query)
+	}
+	pairData, ok := driver.queries[0].params["pairs"].([]map[string]interface{})
+	if !ok || len(pairData) != 3 {
+		t.Fatalf("Expected 3 pairs in params, got %v", driver.queries[0].params["pairs"])
+	}
+	if pairData[2]["type"] != "" {
+		t.Errorf("Expected empty type for untyped pair, got %v", pairData[2]["type"])
+	}
+
+	if len(relationships) != 3 {
+		t.Fatalf("Expected 3 relationships, got %d", len(relationships))
+	}
+	if relationships[0].Source.ID != "alice" || relationships[0].Target.ID != "bob" || relationships[0].Type != "KNOWS" {
+		t.Errorf("Unexpected first relationship: %+v", relationships[0])
+	}
+}
+
+func TestGetRelationshipsDirection(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []graphs.Option
+		relType   string
+		wantMatch string
+	}{
+		{"default outgoing", nil, "WORKS_AT", "(s {id: $sourceId})-[r:`WORKS_AT`]->(t {id: $targetId})"},
+		{"incoming", []graphs.Option{graphs.WithDirection(graphs.DirectionIncoming)}, "WORKS_AT", "(a {id: $sourceId})<-[r:`WORKS_AT`]-(b {id: $targetId})"},
+		{"both", []graphs.Option{graphs.WithDirection(graphs.DirectionBoth)}, "WORKS_AT", "(a {id: $sourceId})-[r:`WORKS_AT`]-(b {id: $targetId})"},
+		{"both without type", []graphs.Option{graphs.WithDirection(graphs.DirectionBoth)}, "", "(a {id: $sourceId})-[r]-(b {id: $targetId})"},
+		{"quoted type", nil, "WORKS`]->() DETACH DELETE s //", "-[r:`WORKS``]->() DETACH DELETE s //`]->"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n4j, driver := newFakeNeo4j()
+			if _, err := n4j.GetRelationships(context.Background(), "acme", "alice", tt.relType, tt.options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			query := driver.queries[0].query
+			if !strings.Contains(query, tt.wantMatch) {
+				t.Errorf("Expected pattern %q, got %s", tt.wantMatch, query)
+			}
+		})
+	}
+}
+
+func TestGetRelationshipsIncomingKeepsStoredDirection(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+		}, nil
+	}
+
+	rels, err := n4j.GetRelationships(context.Background(), "acme", "alice", "WORKS_AT",
+		graphs.WithDirection(graphs.DirectionIncoming))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Source.ID != "alice" || rels[0].Target.ID != "acme" {
+		t.Errorf("Expected the stored alice->acme relationship, got %+v", rels)
+	}
+	if !strings.Contains(driver.queries[0].query, "startNode(r) AS s") {
+		t.Errorf("Expected endpoints to follow the stored direction, got %s", driver.queries[0].query)
+	}
+}
+
+func TestGetRelationshipsBothKeepsStoredDirections(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	bob := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "bob"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		// A symmetric relationship stored once in each direction
+		return []*neo4j.Record{
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "FRIEND_OF"}, "t", bob),
+			newRecord("s", bob, "r", neo4j.Relationship{Type: "FRIEND_OF"}, "t", alice),
+		}, nil
+	}
+
+	rels, err := n4j.GetRelationships(context.Background(), "alice", "bob", "FRIEND_OF",
+		graphs.WithDirection(graphs.DirectionBoth))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rels) != 2 {
+		t.Fatalf("Expected both stored relationships, got %d", len(rels))
+	}
+	if rels[0].Source.ID != "alice" || rels[0].Target.ID != "bob" || rels[1].Source.ID != "bob" || rels[1].Target.ID != "alice" {
+		t.Errorf("Expected alice->bob and bob->alice, got %s->%s and %s->%s",
+			rels[0].Source.ID, rels[0].Target.ID, rels[1].Source.ID, rels[1].Target.ID)
+	}
+	if !strings.Contains(driver.queries[0].query, "startNode(r) AS s") {
+		t.Errorf("Expected endpoints to follow the stored direction, got %s", driver.queries[0].query)
+	}
+}
+
+func TestGetRelationshipsOutgoingKeepsArgumentOrder(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+		}, nil
+	}
+
+	rels, err := n4j.GetRelationships(context.Background(), "alice", "acme", "WORKS_AT",
+		graphs.WithDirection(graphs.DirectionOutgoing))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Source.ID != "alice" || rels[0].Target.ID != "acme" {
+		t.Errorf("Expected the alice->acme relationship, got %+v", rels)
+	}
+}
+
+func TestGetRelationshipsSkipsMalformedRecords(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("s", nil, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+			newRecord("s", alice, "r", "not a relationship", "t", acme),
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", nil),
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+		}, nil
+	}
+	ctx := context.Background()
+
+	rels, err := n4j.GetRelationships(ctx, "alice", "acme", "WORKS_AT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Source.ID != "alice" || rels[0].Target.ID != "acme" {
+		t.Errorf("Expected only the well-formed relationship, got %+v", rels)
+	}
+
+	rels, err = n4j.GetRelationshipsByType(ctx, "WORKS_AT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rels) != 1 {
+		t.Errorf("Expected only the well-formed relationship, got %+v", rels)
+	}
+}
+
+func TestGetNodeSources(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("d", neo4j.Node{Labels: []string{"Document"}, Props: map[string]interface{}{
+				"id":     "doc-1",
+				"text":   "Alice works at Acme.",
+				"source": "news.txt",
+				"page":   int64(3),
+			}}),
+			newRecord("d", neo4j.Node{Labels: []string{"Document"}, Props: map[string]interface{}{
+				"id":   "doc-2",
+				"text": "Alice knows Bob.",
+			}}),
+		}, nil
+	}
+
+	docs, err := n4j.GetNodeSources(context.Background(), "alice", graphs.WithLimit(10))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := driver.queries[0]
+	if !strings.Contains(q.query, "MATCH (d:Document)-[:MENTIONS]->(n {id: $id})") || !strings.HasSuffix(q.query, "LIMIT 10") {
+		t.Errorf("Unexpected query: %s", q.query)
+	}
+	if q.params["id"] != "alice" {
+		t.Errorf("Expected the node id param, got %v", q.params)
+	}
+
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documents, got %d", len(docs))
+	}
+	if docs[0].PageContent != "Alice works at Acme." {
+		t.Errorf("Expected page content from text, got %q", docs[0].PageContent)
+	}
+	expected := map[string]any{"source": "news.txt", "page": int64(3)}
+	if !reflect.DeepEqual(docs[0].Metadata, expected) {
+		t.Errorf("Expected metadata %v, got %v", expected, docs[0].Metadata)
+	}
+	if len(docs[1].Metadata) != 0 {
+		t.Errorf("Expected empty metadata, got %v", docs[1].Metadata)
+	}
+}
+
+func TestNodeTypeSelector(t *testing.T) {
+	node := neo4j.Node{
+		Labels: []string{BASE_ENTITY_LABEL, "Person", "Employee"},
+		Props:  map[string]interface{}{"id": "alice"},
+	}
+
+	n4j, _ := newFakeNeo4j()
+	if got := n4j.convertNeo4jNodeToGraphNode(node, nil).Type; got != "Person" {
+		t.Errorf("Expected the first non-base label by default, got %q", got)
+	}
+
+	var received []string
+	n4j, _ = newFakeNeo4j(WithNodeTypeSelector(func(labels []string) string {
+		received = labels
+		return labels[len(labels)-1]
+	}))
+	if got := n4j.convertNeo4jNodeToGraphNode(node, nil).Type; got != "Employee" {
+		t.Errorf("Expected the selected label, got %q", got)
+	}
+	if !reflect.DeepEqual(received, []string{"Person", "Employee"}) {
+		t.Errorf("Expected the selector to receive labels without the base label, got %v", received)
+	}
+}
+
+func TestConvertNeo4jNodeLabels(t *testing.T) {
+	n4j, _ := newFakeNeo4j()
+
+	node := n4j.convertNeo4jNodeToGraphNode(neo4j.Node{
+		Labels: []string{BASE_ENTITY_LABEL, "Person", "Employee"},
+		Props:  map[string]interface{}{"id": "alice"},
+	}, nil)
+	if node.Type != "Person" || !reflect.DeepEqual(node.Labels, []string{"Person", "Employee"}) {
+		t.Errorf("Expected type Person with every non-base label, got %q and %v", node.Type, node.Labels)
+	}
+
+	node = n4j.convertNeo4jNodeToGraphNode(neo4j.Node{
+		Labels: []string{BASE_ENTITY_LABEL},
+		Props:  map[string]interface{}{"id": "bare"},
+	}, nil)
+	if node.Type != "" || node.Labels != nil {
+		t.Errorf("Expected no type or labels, got %q and %v", node.Type, node.Labels)
+	}
+}
+
+func TestRelationshipsExist(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		var records []*neo4j.Record
+		for _, p := range params["pairs"].([]map[string]interface{}) {
+			found := p["target"] != "ghost" && (p["type"] == "" || p["type"] == "KNOWS" || p["type"] == "WORKS_AT")
+			records = append(records, newRecord("index", int64(p["index"].(int)), "exists", found))
+		}
+		return records, nil
+	}
+
+	ids := []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "bob", Type: "KNOWS"},
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"},
+		{SourceID: "alice", TargetID: "ghost", Type: "KNOWS"},
+		{SourceID: "bob", TargetID: "acme"},
+		{SourceID: "bob", TargetID: "acme", Type: "works at"},
+	}
+
+	exists, err := n4j.RelationshipsExist(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 1 {
+		t.Fatalf("Expected a single query for all identifiers, got %d", len(driver.queries))
+	}
+	if !strings.Contains(driver.queries[0].query, "UNWIND $pairs AS p") {
+		t.Errorf("Expected an UNWIND query, got %s", driver.queries[0].query)
+	}
+
+	expected := map[graphs.RelationshipIdentifier]bool{
+		ids[0]: true,
+		ids[1]: true,
+		ids[2]: false,
+		ids[3]: true,
+		ids[4]: true,
+	}
+	if !reflect.DeepEqual(exists, expected) {
+		t.Errorf("Expected %v, got %v", expected, exists)
+	}
+}
+
+func TestRelationshipsExistEmpty(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	exists, err := n4j.RelationshipsExist(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(exists) != 0 || len(driver.queries) != 0 {
+		t.Errorf("Expected no query and an empty map, got %v and %d queries", exists, len(driver.queries))
+	}
+}
+
+func TestGetNodeIDsByType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("id", "alice"), newRecord("id", "bob")}, nil
+	}
+
+	ids, err := n4j.GetNodeIDsByType(context.Background(), "Person")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []string{"alice", "bob"}) {
+		t.Errorf("Expected [alice bob], got %v", ids)
+	}
+	expected := "MATCH (n:`Person`) RETURN n.id AS id ORDER BY id"
+	if driver.queries[0].query != expected {
+		t.Errorf("Expected only ids to be projected with %q, got %q", expected, driver.queries[0].query)
+	}
+}
+
+func TestGetNodeIDsByTypeLimitAndOffset(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	_, err := n4j.GetNodeIDsByType(context.Background(), "Person", graphs.WithLimit(10), graphs.WithOffset(20))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "MATCH (n:`Person`) RETURN n.id AS id ORDER BY id SKIP 20 LIMIT 10"
+	if driver.queries[0].query != expected {
+		t.Errorf("Expected query %q, got %q", expected, driver.queries[0].query)
+	}
+}
+
+func TestGetNodesByTypeRejectInvalidLabels(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	for _, label := range []string{"", "Person`) DETACH DELETE n //"} {
+		if _, err := n4j.GetNodeIDsByType(ctx, label); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("GetNodeIDsByType(%q): expected ErrInvalidLabel, got %v", label, err)
+		}
+		if _, err := n4j.GetNodesWhere(ctx, label, "n.age >= $minAge", map[string]interface{}{"minAge": 30}); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("GetNodesWhere(%q): expected ErrInvalidLabel, got %v", label, err)
+		}
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestGetRelationshipsReturnFullPath(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	bob := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "bob"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if !strings.Contains(query, "shortestPath") {
+			return nil, nil
+		}
+		return []*neo4j.Record{
+			newRecord("s", alice, "r", neo4j.Relationship{Type: "KNOWS"}, "t", bob),
+			newRecord("s", bob, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme),
+		}, nil
+	}
+
+	rels, err := n4j.GetRelationships(context.Background(), "alice", "acme", "", graphs.WithReturnFullPath(3))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected a direct lookup followed by a path lookup, got %d queries", len(driver.queries))
+	}
+	if !strings.Contains(driver.queries[1].query, "shortestPath((a)-[*..3]->(b))") {
+		t.Errorf("Expected a bounded shortest path query, got %s", driver.queries[1].query)
+	}
+
+	if len(rels) != 2 {
+		t.Fatalf("Expected the 2 path relationships, got %+v", rels)
+	}
+	if rels[0].Source.ID != "alice" || rels[0].Type != "KNOWS" || rels[0].Target.ID != "bob" {
+		t.Errorf("Expected alice-KNOWS->bob first, got %+v", rels[0])
+	}
+	if rels[1].Source.ID != "bob" || rels[1].Type != "WORKS_AT" || rels[1].Target.ID != "acme" {
+		t.Errorf("Expected bob-WORKS_AT->acme second, got %+v", rels[1])
+	}
+}
+
+func TestGetRelationshipsReturnFullPathSkippedForDirectMatch(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme)}, nil
+	}
+
+	rels, err := n4j.GetRelationships(context.Background(), "alice", "acme", "WORKS_AT",
+		graphs.WithReturnFullPath(3), graphs.WithDirection(graphs.DirectionBoth))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rels) != 1 || len(driver.queries) != 1 {
+		t.Errorf("Expected the direct relationship without a path lookup, got %d relationships and %d queries", len(rels), len(driver.queries))
+	}
+}
+
+func TestPathPattern(t *testing.T) {
+	tests := []struct {
+		relType   string
+		direction graphs.Direction
+		want      string
+	}{
+		{"", graphs.DirectionOutgoing, "-[*..2]->"},
+		{"KNOWS", graphs.DirectionIncoming, "<-[:`KNOWS`*..4]-"},
+		{"KNOWS", graphs.DirectionBoth, "-[:`KNOWS`*..4]-"},
+		{"KNOWS`*..1]-() DETACH DELETE a //", graphs.DirectionOutgoing, "-[:`KNOWS``*..1]-() DETACH DELETE a //`*..4]->"},
+	}
+	for _, tt := range tests {
+		depth := 2
+		if tt.relType != "" {
+			depth = 4
+		}
+		if got := pathPattern(tt.relType, depth, tt.direction); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestRemoveNodeProperties(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("n", neo4j.Node{})}, nil
+	}
+
+	if err := n4j.RemoveNodeProperties(context.Background(), "alice", []string{"age", "email", "age"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 1 {
+		t.Fatalf("Expected 1 query, got %d", len(driver.queries))
+	}
+	q := driver.queries[0]
+	if !strings.Contains(q.query, "MATCH (n {id: $id})") || !strings.Contains(q.query, "REMOVE n.`age`, n.`email`\n") {
+		t.Errorf("Unexpected query %s", q.query)
+	}
+	if q.params["id"] != "alice" {
+		t.Errorf("Expected id parameter alice, got %v", q.params["id"])
+	}
+}
+
+func TestRemoveNodePropertiesNotFound(t *testing.T) {
+	n4j, _ := newFakeNeo4j()
+
+	err := n4j.RemoveNodeProperties(context.Background(), "ghost", []string{"age"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+}
+
+func TestRemoveRelationshipProperties(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("r", neo4j.Relationship{})}, nil
+	}
+
+	if err := n4j.RemoveRelationshipProperties(context.Background(), "alice", "bob", "KNOWS", []string{"since"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := driver.queries[0]
+	if !strings.Contains(q.query, "(s {id: $sourceId})-[r:`KNOWS`]->(t {id: $targetId})") || !strings.Contains(q.query, "REMOVE r.`since`\n") {
+		t.Errorf("Unexpected query %s", q.query)
+	}
+	if q.params["sourceId"] != "alice" || q.params["targetId"] != "bob" {
+		t.Errorf("Unexpected params %v", q.params)
+	}
+}
+
+func TestRemovePropertiesValidatesKeys(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	for _, key := range []string{"", "id", "age` REMOVE n.`name", "first name", "1st"} {
+		if err := n4j.RemoveNodeProperties(ctx, "alice", []string{"email", key}); !errors.Is(err, ErrInvalidPropertyKey) {
+			t.Errorf("Expected ErrInvalidPropertyKey for %q, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "since` REMOVE r.`weight", "first name"} {
+		if err := n4j.RemoveRelationshipProperties(ctx, "alice", "bob", "KNOWS", []string{key}); !errors.Is(err, ErrInvalidPropertyKey) {
+			t.Errorf("Expected ErrInvalidPropertyKey for relationship key %q, got %v", key, err)
+		}
+	}
+	if err := n4j.RemoveRelationshipProperties(ctx, "alice", "bob", "KNOWS]->() DELETE s //", []string{"since"}); !errors.Is(err, ErrInvalidRelType) {
+		t.Errorf("Expected ErrInvalidRelType, got %v", err)
+	}
+	if err := n4j.RemoveNodeProperties(ctx, "alice", nil); err != nil {
+		t.Errorf("Expected no error for no keys, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestOperationTimeoutSetsDeadline(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	start := time.Now()
+	if _, err := n4j.GetNode(ctx, "alice", graphs.WithTimeout(250)); err == nil {
+		t.Fatal("Expected the fake node to be missing")
+	}
+	if err := n4j.AddNodes(ctx, []graphs.Node{graphs.NewNode("alice", "Person")}, graphs.WithTimeout(60000)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := n4j.NodeExists(ctx, "alice"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) < 3 {
+		t.Fatalf("Expected at least 3 queries, got %d", len(driver.queries))
+	}
+	assertDeadline(t, driver.queries[0], start, 250*time.Millisecond)
+	for _, q := range driver.queries[1 : len(driver.queries)-1] {
+		assertDeadline(t, q, start, time.Minute)
+	}
+	if last := driver.queries[len(driver.queries)-1]; !last.deadline.IsZero() {
+		t.Errorf("Expected no deadline without a timeout, got %v for %s", last.deadline, last.query)
+	}
+}
+
+func TestOperationTimeoutOverridesStoreTimeout(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithTimeout(time.Hour))
+	ctx := context.Background()
+
+	start := time.Now()
+	if _, err := n4j.GetNodes(ctx, []string{"alice"}, graphs.WithTimeout(100)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := n4j.UpdateNode(ctx, "alice", map[string]interface{}{"age": 30}); err == nil {
+		t.Fatal("Expected the fake node to be missing")
+	}
+
+	assertDeadline(t, driver.queries[0], start, 100*time.Millisecond)
+	// Zero per-call timeout falls back to the store timeout
+	assertDeadline(t, driver.queries[1], start, time.Hour)
+}
+
+func TestStoreTimeoutAppliesPerBatchQuery(t *testing.T) {
+	timeout := 40 * time.Millisecond
+	n4j, driver := newFakeNeo4j(WithTimeout(timeout))
+	var started []time.Time
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		started = append(started, time.Now())
+		time.Sleep(timeout / 2)
+		return nil, nil
+	}
+
+	nodes := []graphs.Node{graphs.NewNode("a", "Person"), graphs.NewNode("b", "Person"), graphs.NewNode("c", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes, graphs.WithBatchSize(1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 3 {
+		t.Fatalf("Expected 3 batches, got %d", len(driver.queries))
+	}
+	for i, q := range driver.queries {
+		assertDeadline(t, q, started[i], timeout-5*time.Millisecond)
+	}
+}
+
+// assertDeadline checks that q ran with a deadline timeout after start
+func assertDeadline(t *testing.T, q recordedQuery, start time.Time, timeout time.Duration) {
+	t.Helper()
+	if q.deadline.IsZero() {
+		t.Errorf("Expected a deadline for %s", q.query)
+		return
+	}
+	if remaining := q.deadline.Sub(start); remaining < timeout || remaining > timeout+time.Second {
+		t.Errorf("Expected a deadline %v after start, got %v for %s", timeout, remaining, q.query)
+	}
+}
+
+func TestPropertySelection(t *testing.T) {
+	props := map[string]interface{}{
+		"id":        "alice",
+		"name":      "Alice",
+		"age":       int64(30),
+		"embedding": []interface{}{0.1, 0.2},
+	}
+
+	tests := []struct {
+		name    string
+		options []graphs.Option
+		want    map[string]interface{}
+	}{
+		{"no selection", nil, props},
+		{"include", []graphs.Option{graphs.WithIncludeProperties([]string{"name", "missing"})},
+			map[string]interface{}{"name": "Alice"}},
+		{"exclude", []graphs.Option{graphs.WithExcludeProperties([]string{"embedding", "missing"})},
+			map[string]interface{}{"id": "alice", "name": "Alice", "age": int64(30)}},
+		{"include takes precedence", []graphs.Option{
+			graphs.WithIncludeProperties([]string{"name", "embedding"}),
+			graphs.WithExcludeProperties([]string{"embedding", "age"}),
+		}, map[string]interface{}{"name": "Alice", "embedding": []interface{}{0.1, 0.2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n4j, driver := newFakeNeo4j()
+			driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+				return []*neo4j.Record{newRecord("n", neo4j.Node{Labels: []string{"Person"}, Props: props})}, nil
+			}
+
+			node, err := n4j.GetNode(context.Background(), "alice", tt.options...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if node.ID != "alice" {
+				t.Errorf("Expected the node ID regardless of selection, got %q", node.ID)
+			}
+			if !reflect.DeepEqual(node.Properties, tt.want) {
+				t.Errorf("Expected properties %v, got %v", tt.want, node.Properties)
+			}
+
+			nodes, err := n4j.GetNodesByType(context.Background(), "Person", tt.options...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(nodes) != 1 || !reflect.DeepEqual(nodes[0].Properties, tt.want) {
+				t.Errorf("Expected properties %v from GetNodesByType, got %+v", tt.want, nodes)
+			}
+		})
+	}
+	if len(props) != 4 {
+		t.Errorf("Expected the stored properties to be left untouched, got %v", props)
+	}
+}
+
+func TestRelationshipPropertySelection(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice", "embedding": []interface{}{0.1}}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme", "name": "Acme"}}
+	worksAt := neo4j.Relationship{Type: "WORKS_AT", Props: map[string]interface{}{"since": "2020", "embedding": []interface{}{0.3}}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("s", alice, "r", worksAt, "t", acme)}, nil
+	}
+	exclude := graphs.WithExcludeProperties([]string{"embedding"})
+
+	rels, err := n4j.GetRelationships(context.Background(), "alice", "acme", "WORKS_AT", exclude)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	byType, err := n4j.GetRelationshipsByType(context.Background(), "WORKS_AT", exclude)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, rel := range append(rels, byType...) {
+		if !reflect.DeepEqual(rel.Properties, map[string]interface{}{"since": "2020"}) {
+			t.Errorf("Expected the embedding to be excluded from the relationship, got %v", rel.Properties)
+		}
+		if _, ok := rel.Source.Properties["embedding"]; ok || rel.Source.ID != "alice" {
+			t.Errorf("Expected the embedding to be excluded from the source, got %+v", rel.Source)
+		}
+		if rel.Target.Properties["name"] != "Acme" {
+			t.Errorf("Expected other endpoint properties to be kept, got %+v", rel.Target)
+		}
+	}
+}
+
+// largePropertySchema reports embedding as a list of LIST_LIMIT or more elements on every
+// Chunk and tags as a short list
+func largePropertySchema() map[string]interface{} {
+	return map[string]interface{}{
+		"node_props": map[string]interface{}{
+			"Chunk": []interface{}{
+				map[string]interface{}{"property": "text", "type": "STRING"},
+				map[string]interface{}{"property": "embedding", "type": "LIST", "min_size": int64(1536), "max_size": int64(1536)},
+				map[string]interface{}{"property": "tags", "type": "LIST", "min_size": int64(1), "max_size": int64(4)},
+			},
+		},
+	}
+}
+
+func TestServerSideSanitize(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithServerSideSanitize(true))
+	n4j.structuredSchema = largePropertySchema()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("n", map[string]interface{}{
+			"id":               "chunk-1",
+			"text":             "Alice works at Acme.",
+			"tags":             []interface{}{"people"},
+			"embedding":        nil,
+			projectedLabelsKey: []interface{}{"Chunk"},
+		})}, nil
+	}
+	ctx := context.Background()
+
+	nodes, err := n4j.GetNodesByType(ctx, "Chunk")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query := driver.queries[0].query
+	if !strings.Contains(query, "RETURN n {.*, `embedding`: null, `__labels`: labels(n)} AS n") {
+		t.Errorf("Expected embedding to be excluded in the query, got %s", query)
+	}
+	if strings.Contains(query, "`tags`") || strings.Contains(query, "`text`") {
+		t.Errorf("Expected small properties to be kept, got %s", query)
+	}
+
+	if len(nodes) != 1 {
+		t.Fatalf("Expected 1 node, got %d", len(nodes))
+	}
+	node := nodes[0]
+	if node.ID != "chunk-1" || node.Type != "Chunk" {
+		t.Errorf("Expected chunk-1 of type Chunk, got %s of type %s", node.ID, node.Type)
+	}
+	if _, ok := node.Properties["embedding"]; ok {
+		t.Errorf("Expected embedding to be absent, got %v", node.Properties)
+	}
+	if _, ok := node.Properties[projectedLabelsKey]; ok {
+		t.Errorf("Expected labels not to leak into properties, got %v", node.Properties)
+	}
+	if node.Properties["text"] != "Alice works at Acme." || node.Properties["tags"] == nil {
+		t.Errorf("Expected other properties to be kept, got %v", node.Properties)
+	}
+
+	for _, get := range []func() error{
+		func() error { _, err := n4j.GetNode(ctx, "chunk-1"); return err },
+		func() error { _, err := n4j.GetNodes(ctx, []string{"chunk-1"}); return err },
+		func() error { _, _, err := n4j.GetNodesByTypePaged(ctx, "Chunk", "", 10); return err },
+	} {
+		if err := get(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if query := driver.queries[len(driver.queries)-1].query; !strings.Contains(query, "`embedding`: null") {
+			t.Errorf("Expected embedding to be excluded in the query, got %s", query)
+		}
+	}
+}
+
+func TestServerSideSanitizeDisabled(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		n4j, driver := newFakeNeo4j(WithServerSideSanitize(enabled))
+		if !enabled {
+			n4j.structuredSchema = largePropertySchema()
+		}
+
+		// Without the option or without size statistics the full node is returned
+		if _, err := n4j.GetNodesByType(context.Background(), "Chunk"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if query := driver.queries[0].query; query != "MATCH (n:`Chunk`) RETURN n" {
+			t.Errorf("Expected an unprojected query, got %s", query)
+		}
+	}
+}
+
+func TestRemoveRelationshipsBatchesByType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	err := n4j.RemoveRelationships(context.Background(), []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "bob", Type: "KNOWS"},
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"},
+		{SourceID: "bob", TargetID: "carol", Type: "KNOWS"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 || len(driver.sessions) != 1 {
+		t.Fatalf("Expected 2 queries in 1 session, got %d in %d", len(driver.queries), len(driver.sessions))
+	}
+	for i, want := range []struct {
+		relType string
+		sources []string
+	}{
+		{"KNOWS", []string{"alice", "bob"}},
+		{"WORKS_AT", []string{"alice"}},
+	} {
+		q := driver.queries[i]
+		if !strings.Contains(q.query, "UNWIND $relationships AS rel") ||
+			!strings.Contains(q.query, "MATCH (s {id: rel.sourceId})-[r:"+want.relType+"]->(t {id: rel.targetId})") {
+			t.Errorf("Expected query %d to delete %s relationships, got %s", i, want.relType, q.query)
+		}
+		relData := q.params["relationships"].([]map[string]interface{})
+		if len(relData) != len(want.sources) {
+			t.Fatalf("Expected query %d to carry %d relationships, got %d", i, len(want.sources), len(relData))
+		}
+		for j, source := range want.sources {
+			if relData[j]["sourceId"] != source {
+				t.Errorf("Expected source %s at query %d position %d, got %v", source, i, j, relData[j]["sourceId"])
+			}
+		}
+	}
+}
+
+func TestRemoveRelationshipsReportsFailedIdentifiers(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "WORKS_AT") {
+			return nil, errors.New("connection reset")
+		}
+		return nil, nil
+	}
+
+	err := n4j.RemoveRelationships(context.Background(), []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "bob", Type: "KNOWS"},
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "alice-WORKS_AT->acme") || strings.Contains(err.Error(), "bob") {
+		t.Errorf("Expected the failed identifier to be reported, got %v", err)
+	}
+}
+
+func TestRemoveRelationshipsReportsPullErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.pullErr = func(query string) error {
+		if strings.Contains(query, "WORKS_AT") {
+			return errors.New("delete failed")
+		}
+		return nil
+	}
+
+	err := n4j.RemoveRelationships(context.Background(), []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "bob", Type: "KNOWS"},
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "alice-WORKS_AT->acme") {
+		t.Errorf("Expected the failure raised while pulling results to be reported, got %v", err)
+	}
+}
+
+func TestRemoveRelationshipsInvalidType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	err := n4j.RemoveRelationships(context.Background(), []graphs.RelationshipIdentifier{
+		{SourceID: "alice", TargetID: "bob", Type: "KNOWS"},
+		{SourceID: "alice", TargetID: "acme", Type: "WORKS_AT]->() DETACH DELETE s //"},
+	})
+	if !errors.Is(err, ErrInvalidRelType) || !strings.Contains(err.Error(), "alice-WORKS_AT") {
+		t.Errorf("Expected ErrInvalidRelType naming the identifier, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected nothing to be removed, got %d queries", len(driver.queries))
+	}
+}
+
+// BenchmarkRemoveRelationships1k measures removing 1k relationships of two types
+// against the fake driver, counting round-trips rather than server time.
+//
+// One session and query per relationship (before batching):
+//
+//	BenchmarkRemoveRelationships1k  868  1264185 ns/op  1000 queries/op  1001 sessions/op  1214460 B/op  9036 allocs/op
+//
+// One UNWIND query per type and batch of 100 in a single session (after):
+//
+//	BenchmarkRemoveRelationships1k  2592  475519 ns/op    10 queries/op     1 sessions/op   514997 B/op  4091 allocs/op
+func BenchmarkRemoveRelationships1k(b *testing.B) {
+	relationships := make([]graphs.RelationshipIdentifier, 1000)
+	for i := range relationships {
+		relationships[i] = graphs.RelationshipIdentifier{
+			SourceID: fmt.Sprintf("node-%d", i),
+			TargetID: fmt.Sprintf("node-%d", i+1),
+			Type:     []string{"KNOWS", "WORKS_AT"}[i%2],
+		}
+	}
+
+	for i := 0; i < b.N; i++ {
+		n4j, driver := newFakeNeo4j()
+		if err := n4j.RemoveRelationships(context.Background(), relationships); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		b.ReportMetric(float64(len(driver.queries)), "queries/op")
+		b.ReportMetric(float64(len(driver.sessions)), "sessions/op")
+	}
+}
+
+func TestCountNodes(t *testing.T) {
+	tests := []struct {
+		nodeType  string
+		wantQuery string
+	}{
+		{"", "MATCH (n) RETURN count(n) AS count"},
+		{"Person", "MATCH (n:Person) RETURN count(n) AS count"},
+	}
+
+	for _, tt := range tests {
+		n4j, driver := newFakeNeo4j()
+		driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+			return []*neo4j.Record{newRecord("count", int64(3_000_000_000))}, nil
+		}
+
+		count, err := n4j.CountNodes(context.Background(), tt.nodeType)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if count != 3_000_000_000 {
+			t.Errorf("Expected 3000000000 nodes, got %d", count)
+		}
+		if driver.queries[0].query != tt.wantQuery {
+			t.Errorf("Expected %q, got %q", tt.wantQuery, driver.queries[0].query)
+		}
+	}
+}
+
+func TestCountRelationships(t *testing.T) {
+	tests := []struct {
+		relType   string
+		wantQuery string
+	}{
+		{"", "MATCH ()-[r]->() RETURN count(r) AS count"},
+		{"WORKS_AT", "MATCH ()-[r:WORKS_AT]->() RETURN count(r) AS count"},
+	}
+
+	for _, tt := range tests {
+		n4j, driver := newFakeNeo4j()
+		driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+			return []*neo4j.Record{newRecord("count", int64(42))}, nil
+		}
+
+		count, err := n4j.CountRelationships(context.Background(), tt.relType)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if count != 42 {
+			t.Errorf("Expected 42 relationships, got %d", count)
+		}
+		if driver.queries[0].query != tt.wantQuery {
+			t.Errorf("Expected %q, got %q", tt.wantQuery, driver.queries[0].query)
+		}
+	}
+}
+
+func TestCountRejectsInvalidTypes(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	if _, err := n4j.CountNodes(ctx, "Person) DETACH DELETE n //"); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Expected ErrInvalidLabel, got %v", err)
+	}
+	if _, err := n4j.CountRelationships(ctx, "KNOWS]->() DELETE r //"); !errors.Is(err, ErrInvalidRelType) {
+		t.Errorf("Expected ErrInvalidRelType, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestDistinctPropertyValues(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		values := []string{"Berlin", "London", "Paris"}
+		if limit, ok := params["limit"].(int); ok && limit < len(values) {
+			values = values[:limit]
+		}
+		var records []*neo4j.Record
+		for _, value := range values {
+			records = append(records, newRecord("value", value))
+		}
+		return records, nil
+	}
+	ctx := context.Background()
+
+	values, err := n4j.DistinctPropertyValues(ctx, "Person", "city", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"Berlin", "London"}) {
+		t.Errorf("Expected the first 2 cities, got %v", values)
+	}
+
+	q := driver.queries[0]
+	for _, want := range []string{"MATCH (n:Person)", "WHERE n.city IS NOT NULL", "RETURN DISTINCT n.city AS value", "LIMIT $limit"} {
+		if !strings.Contains(q.query, want) {
+			t.Errorf("Expected %q in query, got %s", want, q.query)
+		}
+	}
+	if q.params["limit"] != 2 {
+		t.Errorf("Expected the limit to be passed as a parameter, got %v", q.params)
+	}
+
+	values, err = n4j.DistinctPropertyValues(ctx, "Person", "city", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(values) != 3 || strings.Contains(driver.queries[1].query, "LIMIT") {
+		t.Errorf("Expected every value without a limit, got %v from %s", values, driver.queries[1].query)
+	}
+}
+
+func TestDistinctPropertyValuesRejectsInvalidIdentifiers(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	if _, err := n4j.DistinctPropertyValues(ctx, "Person) DETACH DELETE n //", "city", 10); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Expected ErrInvalidLabel, got %v", err)
+	}
+	if _, err := n4j.DistinctPropertyValues(ctx, "Person", "city RETURN n", 10); !errors.Is(err, ErrInvalidPropertyKey) {
+		t.Errorf("Expected ErrInvalidPropertyKey, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestGetNodesWithContext(t *testing.T) {
+	alice := neo4j.Node{ElementId: "1", Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	bob := neo4j.Node{ElementId: "2", Labels: []string{"Person"}, Props: map[string]interface{}{"id": "bob"}}
+	acme := neo4j.Node{ElementId: "3", Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	knows := neo4j.Relationship{ElementId: "10", StartElementId: "1", EndElementId: "2", Type: "KNOWS", Props: map[string]interface{}{"since": "2020"}}
+	worksAt := neo4j.Relationship{ElementId: "11", StartElementId: "2", EndElementId: "3", Type: "WORKS_AT", Props: map[string]interface{}{}}
+
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		// Paths start at the matched node whatever the stored direction
+		rows := map[string]*neo4j.Record{
+			"alice": newRecord("n", alice, "paths", []interface{}{
+				neo4j.Path{Nodes: []neo4j.Node{alice, bob}, Relationships: []neo4j.Relationship{knows}},
+			}),
+			"bob": newRecord("n", bob, "paths", []interface{}{
+				neo4j.Path{Nodes: []neo4j.Node{bob, alice}, Relationships: []neo4j.Relationship{knows}},
+				neo4j.Path{Nodes: []neo4j.Node{bob, acme}, Relationships: []neo4j.Relationship{worksAt}},
+			}),
+		}
+		var records []*neo4j.Record
+		for _, id := range params["ids"].([]string) {
+			if record, ok := rows[id]; ok {
+				records = append(records, record)
+			}
+		}
+		return records, nil
+	}
+
+	doc, err := n4j.GetNodesWithContext(context.Background(), []string{"bob", "alice", "ghost", "bob"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := driver.queries[0]
+	if !strings.Contains(q.query, "OPTIONAL MATCH p = (n)-[]-()") || !strings.Contains(q.query, "collect(p) AS paths") {
+		t.Errorf("Expected a single query collecting 1-hop paths, got %s", q.query)
+	}
+	if !reflect.DeepEqual(q.params["ids"], []string{"bob", "alice", "ghost"}) {
+		t.Errorf("Expected the deduplicated IDs, got %v", q.params["ids"])
+	}
+
+	var ids []string
+	for _, node := range doc.Nodes {
+		ids = append(ids, node.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"bob", "alice", "acme"}) {
+		t.Errorf("Expected the requested nodes followed by their neighbors, got %v", ids)
+	}
+	if len(doc.Relationships) != 2 {
+		t.Fatalf("Expected 2 relationships, got %d", len(doc.Relationships))
+	}
+	if rel := doc.FindRelationship("alice", "bob", "KNOWS"); rel == nil || rel.Properties["since"] != "2020" {
+		t.Errorf("Expected KNOWS to keep its stored orientation, got %+v", doc.Relationships)
+	}
+	if doc.FindRelationship("bob", "acme", "WORKS_AT") == nil {
+		t.Errorf("Expected the WORKS_AT relationship, got %+v", doc.Relationships)
+	}
+}
+
+func TestGetNodesWithContextKeepsRequestOrder(t *testing.T) {
+	alice := neo4j.Node{ElementId: "1", Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	bob := neo4j.Node{ElementId: "2", Labels: []string{"Person"}, Props: map[string]interface{}{"id": "bob"}}
+	carol := neo4j.Node{ElementId: "3", Labels: []string{"Person"}, Props: map[string]interface{}{"id": "carol"}}
+
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(string, map[string]interface{}) ([]*neo4j.Record, error) {
+		// The aggregation returns rows in whatever order the planner picks
+		return []*neo4j.Record{
+			newRecord("n", carol, "paths", []interface{}{}),
+			newRecord("n", bob, "paths", []interface{}{}),
+			newRecord("n", alice, "paths", []interface{}{}),
+		}, nil
+	}
+
+	doc, err := n4j.GetNodesWithContext(context.Background(), []string{"alice", "carol", "bob"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, node := range doc.Nodes {
+		ids = append(ids, node.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"alice", "carol", "bob"}) {
+		t.Errorf("Expected the nodes in request order, got %v", ids)
+	}
+}
+
+func TestGetNodesWithContextEmpty(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	doc, err := n4j.GetNodesWithContext(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if doc.GetNodeCount() != 0 || len(driver.queries) != 0 {
+		t.Errorf("Expected an empty document without querying, got %d nodes and %d queries", doc.GetNodeCount(), len(driver.queries))
+	}
+}
+
+func TestGetByTypePagination(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []graphs.Option
+		suffix  string
+	}{
+		{"offset and limit", []graphs.Option{graphs.WithLimit(10), graphs.WithOffset(5)}, " SKIP 5 LIMIT 10"},
+		{"offset only", []graphs.Option{graphs.WithOffset(5)}, " SKIP 5"},
+		{"limit only", []graphs.Option{graphs.WithLimit(10)}, " LIMIT 10"},
+		{"neither", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n4j, driver := newFakeNeo4j()
+			ctx := context.Background()
+
+			if _, err := n4j.GetNodesByType(ctx, "Person", tt.options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := n4j.GetRelationshipsByType(ctx, "WORKS_AT", tt.options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expected := []string{
+				"MATCH (n:`Person`) RETURN n" + tt.suffix,
+				"MATCH (s)-[r:`WORKS_AT`]->(t) RETURN s, r, t" + tt.suffix,
+			}
+			for i, want := range expected {
+				if got := driver.queries[i].query; got != want {
+					t.Errorf("Expected %q, got %q", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetNodesOrdered(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		// The database yields nodes in its own order
+		return []*neo4j.Record{
+			newRecord("n", neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "carol"}}),
+			newRecord("n", neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}),
+		}, nil
+	}
+
+	ids := []string{"alice", "ghost", "carol", "nobody", "ghost"}
+	nodes, missing, err := n4j.GetNodesOrdered(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(nodes) != len(ids) {
+		t.Fatalf("Expected %d entries, got %d", len(ids), len(nodes))
+	}
+	for i, want := range []string{"alice", "", "carol", "", ""} {
+		switch {
+		case want == "" && nodes[i] != nil:
+			t.Errorf("Expected a nil entry at %d, got %+v", i, nodes[i])
+		case want != "" && (nodes[i] == nil || nodes[i].ID != want):
+			t.Errorf("Expected %s at %d, got %+v", want, i, nodes[i])
+		}
+	}
+	if !reflect.DeepEqual(missing, []string{"ghost", "nobody"}) {
+		t.Errorf("Expected missing ghost and nobody, got %v", missing)
+	}
+}
+
+func TestGetNodesOrderedAllFound(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("n", neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}),
+		}, nil
+	}
+
+	nodes, missing, err := n4j.GetNodesOrdered(context.Background(), []string{"alice"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0] == nil || missing != nil {
+		t.Errorf("Expected alice and no missing IDs, got %v and %v", nodes, missing)
+	}
+}
+
+func TestClearDatabaseRequiresConfirm(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	for _, options := range [][]graphs.Option{nil, {graphs.WithConfirm(false)}} {
+		if err := n4j.ClearDatabase(context.Background(), options...); !errors.Is(err, ErrNotConfirmed) {
+			t.Errorf("Expected ErrNotConfirmed, got %v", err)
+		}
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries without confirmation, got %d", len(driver.queries))
+	}
+}
+
+func TestClearDatabase(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodes   int64
+		queries []string
+	}{
+		{"single query below the batch size", 50, []string{"MATCH (n) DETACH DELETE n"}},
+		{"batched above the batch size", 250, []string{
+			"MATCH (n) WITH n LIMIT $batchSize DETACH DELETE n RETURN count(*) AS deleted",
+			"MATCH (n) WITH n LIMIT $batchSize DETACH DELETE n RETURN count(*) AS deleted",
+			"MATCH (n) WITH n LIMIT $batchSize DETACH DELETE n RETURN count(*) AS deleted",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n4j, driver := newFakeNeo4j()
+			remaining := tt.nodes
+			driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+				if strings.Contains(query, "RETURN count(n) AS count") {
+					return []*neo4j.Record{newRecord("count", remaining)}, nil
+				}
+				if batchSize, ok := params["batchSize"].(int); ok {
+					deleted := remaining
+					if deleted > int64(batchSize) {
+						deleted = int64(batchSize)
+					}
+					remaining -= deleted
+					return []*neo4j.Record{newRecord("deleted", deleted)}, nil
+				}
+				remaining = 0
+				return nil, nil
+			}
+
+			if err := n4j.ClearDatabase(context.Background(), graphs.WithConfirm(true), graphs.WithBatchSize(100)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var deletes []string
+			for _, q := range driver.queries[1:] {
+				deletes = append(deletes, q.query)
+				if q.inTx {
+					t.Errorf("Expected each batch to be committed on its own, got an explicit transaction")
+				}
+			}
+			if !reflect.DeepEqual(deletes, tt.queries) {
+				t.Errorf("Expected %v, got %v", tt.queries, deletes)
+			}
+			if remaining != 0 {
+				t.Errorf("Expected every node to be deleted, %d remain", remaining)
+			}
+		})
+	}
+}
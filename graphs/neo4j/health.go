@@ -0,0 +1,79 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Ping verifies that the database can be reached with the current driver.
+func (n *Neo4j) Ping(ctx context.Context) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+	return n.driver.VerifyConnectivity(ctx)
+}
+
+// Reconnect replaces the driver with a new one created from the options the store was
+// built with, for recovering from lost connectivity without recreating the store.
+// The old driver is closed once the new one is connected; if connecting fails, the
+// old driver is kept and the error is returned. Reconnect must not be called
+// concurrently with other operations on the store.
+func (n *Neo4j) Reconnect(ctx context.Context) error {
+	n.stopHealthCheck()
+	defer n.startHealthCheck()
+
+	old := n.driver
+	if err := n.connect(ctx); err != nil {
+		n.driver = old
+		return fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+	}
+	if old != nil {
+		old.Close(ctx)
+	}
+	return nil
+}
+
+// startHealthCheck starts pinging the driver in the background if a health check interval is set
+func (n *Neo4j) startHealthCheck() {
+	if n.healthCheckInterval <= 0 || n.driver == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.healthCheckCancel = cancel
+	n.healthCheckDone = make(chan struct{})
+	go n.runHealthCheck(ctx, n.healthCheckDone)
+}
+
+// runHealthCheck verifies connectivity every interval until ctx is canceled,
+// reporting failures to the unhealthy callback
+func (n *Neo4j) runHealthCheck(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(n.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, n.healthCheckInterval)
+			err := n.driver.VerifyConnectivity(pingCtx)
+			cancel()
+			if err != nil && ctx.Err() == nil && n.onUnhealthy != nil {
+				n.onUnhealthy(err)
+			}
+		}
+	}
+}
+
+// stopHealthCheck stops the background health check and waits for it to exit
+func (n *Neo4j) stopHealthCheck() {
+	if n.healthCheckCancel == nil {
+		return
+	}
+	n.healthCheckCancel()
+	<-n.healthCheckDone
+}
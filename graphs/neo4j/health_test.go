@@ -0,0 +1,138 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+)
+
+func TestHealthCheckReportsUnhealthyAndStopsOnClose(t *testing.T) {
+	unhealthy := make(chan error, 10)
+	n4j, driver := newFakeNeo4j(WithHealthCheck(5*time.Millisecond, func(err error) {
+		unhealthy <- err
+	}))
+	n4j.startHealthCheck()
+
+	// Healthy pings must not invoke the callback
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case err := <-unhealthy:
+		t.Fatalf("Unexpected unhealthy callback: %v", err)
+	default:
+	}
+
+	connectionLost := errors.New("connection lost")
+	driver.setConnectivityErr(connectionLost)
+
+	select {
+	case err := <-unhealthy:
+		if !errors.Is(err, connectionLost) {
+			t.Errorf("Expected the ping error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the unhealthy callback to fire")
+	}
+
+	if err := n4j.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case <-n4j.healthCheckDone:
+	default:
+		t.Fatal("Expected the health check goroutine to stop on Close")
+	}
+
+	// Drain callbacks from pings that completed before Close, then check none follow
+	for len(unhealthy) > 0 {
+		<-unhealthy
+	}
+	time.Sleep(20 * time.Millisecond)
+	if len(unhealthy) != 0 {
+		t.Error("Expected no callbacks after Close")
+	}
+}
+
+func TestHealthCheckDisabledByDefault(t *testing.T) {
+	n4j, _ := newFakeNeo4j()
+	n4j.startHealthCheck()
+
+	if n4j.healthCheckCancel != nil {
+		t.Error("Expected no health check without an interval")
+	}
+	if err := n4j.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	if err := n4j.Ping(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	connectionLost := errors.New("connection lost")
+	driver.setConnectivityErr(connectionLost)
+	if err := n4j.Ping(context.Background()); !errors.Is(err, connectionLost) {
+		t.Errorf("Expected the connectivity error, got %v", err)
+	}
+}
+
+func TestReconnectReplacesDriver(t *testing.T) {
+	n4j, old := newFakeNeo4j(WithURI("bolt://db.example.com:7687"))
+	old.setConnectivityErr(errors.New("connection lost"))
+
+	var targets []string
+	replacement := &fakeDriver{}
+	restore := newDriver
+	newDriver = func(target string, manager auth.TokenManager, configurers ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+		targets = append(targets, target)
+		return replacement, nil
+	}
+	defer func() { newDriver = restore }()
+
+	if err := n4j.Reconnect(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(targets) != 1 || targets[0] != "bolt://db.example.com:7687" {
+		t.Errorf("Expected to reconnect with the original URI, got %v", targets)
+	}
+	if n4j.driver != replacement {
+		t.Error("Expected the driver to be replaced")
+	}
+	if !old.closed {
+		t.Error("Expected the old driver to be closed")
+	}
+	if err := n4j.Ping(context.Background()); err != nil {
+		t.Errorf("Expected the new driver to be healthy, got %v", err)
+	}
+}
+
+func TestReconnectKeepsDriverOnFailure(t *testing.T) {
+	n4j, old := newFakeNeo4j()
+
+	replacement := &fakeDriver{}
+	replacement.setConnectivityErr(errors.New("connection refused"))
+	restore := newDriver
+	newDriver = func(target string, manager auth.TokenManager, configurers ...func(*neo4j.Config)) (neo4j.DriverWithContext, error) {
+		return replacement, nil
+	}
+	defer func() { newDriver = restore }()
+
+	err := n4j.Reconnect(context.Background())
+	if !errors.Is(err, ErrConnectionFailed) {
+		t.Fatalf("Expected ErrConnectionFailed, got %v", err)
+	}
+	if n4j.driver != old || old.closed {
+		t.Error("Expected the old driver to be kept open")
+	}
+	if !replacement.closed {
+		t.Error("Expected the failed driver to be closed")
+	}
+}
@@ -14,11 +14,14 @@ func (n *Neo4j) AddGraphDocument(ctx context.Context, docs []graphs.GraphDocumen
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
+	defer n.invalidateQueryCache()
 
 	opts := graphs.NewOptions()
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withBatchTimeout(ctx, opts)
+	defer cancel()
 
 	// Create batches for efficient processing
 	batchSize := opts.BatchSize
@@ -32,8 +35,8 @@ func (n *Neo4j) AddGraphDocument(ctx context.Context, docs []graphs.GraphDocumen
 			end = len(docs)
 		}
 
-		batch := docs[i:end]
-		if err := n.processBatch(ctx, batch, opts); err != nil {
+		// Every query of the batch is retried on its own by Query
+		if err := n.processBatch(ctx, docs[i:end], opts); err != nil {
 			return err
 		}
 	}
@@ -41,9 +44,36 @@ func (n *Neo4j) AddGraphDocument(ctx context.Context, docs []graphs.GraphDocumen
 	return nil
 }
 
+// AddGraphDocumentAtomic adds a single graph document in one transaction, so either all of
+// its nodes and relationships are written or, if any part of the import fails, none are.
+// The base entity constraint is created beforehand since schema changes cannot share a
+// transaction with data writes.
+func (n *Neo4j) AddGraphDocumentAtomic(ctx context.Context, doc graphs.GraphDocument, options ...graphs.Option) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	if err := n.ensureBaseEntityConstraint(ctx); err != nil {
+		return fmt.Errorf("failed to ensure base entity constraint: %w", err)
+	}
+
+	docs := n.documentsWithoutSelfLoops([]graphs.GraphDocument{doc}, opts)
+	return n.txManager.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
+		return n.txManager.processBatchInTransaction(ctx, tx, docs, opts)
+	})
+}
+
 // processBatch processes a batch of graph documents
 func (n *Neo4j) processBatch(ctx context.Context, docs []graphs.GraphDocument, opts *graphs.Options) error {
-	// Import nodes first
+	// Import nodes of every document first so relationships can
+	// reference nodes defined in any document of the batch
 	for _, doc := range docs {
 		if err := n.importNodes(ctx, doc, opts); err != nil {
 			return err
@@ -51,8 +81,9 @@ func (n *Neo4j) processBatch(ctx context.Context, docs []graphs.GraphDocument, o
 	}
 
 	// Then import relationships
+	nodeTypes := batchNodeTypes(docs)
 	for _, doc := range docs {
-		if err := n.importRelationships(ctx, doc, opts); err != nil {
+		if err := n.importRelationships(ctx, doc, nodeTypes, opts); err != nil {
 			return err
 		}
 	}
@@ -60,6 +91,86 @@ func (n *Neo4j) processBatch(ctx context.Context, docs []graphs.GraphDocument, o
 	return nil
 }
 
+// batchNodeTypes maps the ID of every node in a batch of documents to its type
+func batchNodeTypes(docs []graphs.GraphDocument) map[string]string {
+	nodeTypes := make(map[string]string)
+	for _, doc := range docs {
+		for _, node := range doc.Nodes {
+			if node.Type != "" {
+				nodeTypes[node.ID] = node.Type
+			}
+		}
+	}
+	return nodeTypes
+}
+
+// nodeImportData prepares node parameters for the import query
+func (n *Neo4j) nodeImportData(nodes []graphs.Node) []map[string]interface{} {
+	var nodeData []map[string]interface{}
+	for _, node := range nodes {
+		nodeData = append(nodeData, map[string]interface{}{
+			"id":         node.ID,
+			"type":       cleanString(node.Type),
+			"labels":     nodeLabels(node),
+			"properties": n.normalizeProperties(node.Properties),
+		})
+	}
+	return nodeData
+}
+
+// nodeLabels returns the labels written for a node: its type followed by its other
+// labels, without duplicates, empty labels or the base entity label
+func nodeLabels(node graphs.Node) []string {
+	labels := make([]string, 0, len(node.Labels)+1)
+	seen := map[string]bool{"": true, BASE_ENTITY_LABEL: true}
+	for _, label := range append([]string{node.Type}, node.Labels...) {
+		label = cleanString(label)
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// hasExtraLabels reports whether any of the nodes has labels besides its type
+func hasExtraLabels(nodes []graphs.Node) bool {
+	for _, node := range nodes {
+		if len(nodeLabels(node)) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// relationshipImportData prepares relationship parameters for the import query.
+// Endpoints given by ID only take their type from the node defined in the batch.
+// Every relationship carries its index so the query can count how many were written.
+func (n *Neo4j) relationshipImportData(relationships []graphs.Relationship, nodeTypes map[string]string) []map[string]interface{} {
+	var relData []map[string]interface{}
+	for i, rel := range relationships {
+		sourceType := rel.Source.Type
+		if sourceType == "" {
+			sourceType = nodeTypes[rel.Source.ID]
+		}
+		targetType := rel.Target.Type
+		if targetType == "" {
+			targetType = nodeTypes[rel.Target.ID]
+		}
+
+		relData = append(relData, map[string]interface{}{
+			"index":        i,
+			"source":       rel.Source.ID,
+			"source_label": cleanString(sourceType),
+			"target":       rel.Target.ID,
+			"target_label": cleanString(targetType),
+			"type":         normalizeRelType(rel.Type),
+			"properties":   n.normalizeProperties(rel.Properties),
+		})
+	}
+	return relData
+}
+
 // importNodes imports nodes from a graph document
 func (n *Neo4j) importNodes(ctx context.Context, doc graphs.GraphDocument, opts *graphs.Options) error {
 	if len(doc.Nodes) == 0 {
@@ -75,14 +186,7 @@ func (n *Neo4j) importNodes(ctx context.Context, doc graphs.GraphDocument, opts
 	query := n.getNodeImportQuery(opts.IncludeSource)
 
 	// Prepare node data
-	var nodeData []map[string]interface{}
-	for _, node := range doc.Nodes {
-		nodeData = append(nodeData, map[string]interface{}{
-			"id":         node.ID,
-			"type":       cleanString(node.Type),
-			"properties": node.Properties,
-		})
-	}
+	nodeData := n.nodeImportData(doc.Nodes)
 
 	// Prepare parameters
 	params := map[string]interface{}{
@@ -103,38 +207,64 @@ func (n *Neo4j) importNodes(ctx context.Context, doc graphs.GraphDocument, opts
 	return err
 }
 
+// withoutSelfLoops drops the self-loops of relationships, logging how many were dropped
+func (n *Neo4j) withoutSelfLoops(relationships []graphs.Relationship) []graphs.Relationship {
+	kept := graphs.DropSelfLoops(relationships)
+	if dropped := len(relationships) - len(kept); dropped > 0 {
+		n.logf("neo4j: dropped %d self-loop relationships", dropped)
+	}
+	return kept
+}
+
+// documentsWithoutSelfLoops returns copies of docs without self-loops when opts.DropSelfLoops
+// is set. Transactional imports filter documents once up front, as the driver may run the
+// transaction function several times.
+func (n *Neo4j) documentsWithoutSelfLoops(docs []graphs.GraphDocument, opts *graphs.Options) []graphs.GraphDocument {
+	if !opts.DropSelfLoops {
+		return docs
+	}
+	filtered := make([]graphs.GraphDocument, len(docs))
+	for i, doc := range docs {
+		doc.Relationships = n.withoutSelfLoops(doc.Relationships)
+		filtered[i] = doc
+	}
+	return filtered
+}
+
 // importRelationships imports relationships from a graph document
-func (n *Neo4j) importRelationships(ctx context.Context, doc graphs.GraphDocument, opts *graphs.Options) error {
-	if len(doc.Relationships) == 0 {
+func (n *Neo4j) importRelationships(ctx context.Context, doc graphs.GraphDocument, nodeTypes map[string]string, opts *graphs.Options) error {
+	relationships := doc.Relationships
+	if opts.DropSelfLoops {
+		relationships = n.withoutSelfLoops(relationships)
+	}
+	if len(relationships) == 0 {
 		return nil
 	}
 
 	// Generate query using the appropriate method
-	query := n.getRelImportQuery()
+	query := n.getRelImportQuery(opts.AssumeEndpointsExist)
 
 	// Prepare relationship data
-	var relData []map[string]interface{}
-	for _, rel := range doc.Relationships {
-		relData = append(relData, map[string]interface{}{
-			"source":       rel.Source.ID,
-			"source_label": cleanString(rel.Source.Type),
-			"target":       rel.Target.ID,
-			"target_label": cleanString(rel.Target.Type),
-			"type":         cleanString(strings.ReplaceAll(strings.ToUpper(rel.Type), " ", "_")),
-			"properties":   rel.Properties,
-		})
-	}
+	relData := n.relationshipImportData(relationships, nodeTypes)
 
 	params := map[string]interface{}{
 		"relationships": relData,
 	}
 
 	// Execute query
-	_, err := n.Query(ctx, query, params)
-	if err != nil && isAPOCError(err) {
-		return wrapAPOCError(err)
+	result, err := n.Query(ctx, query, params)
+	if err != nil {
+		if isAPOCError(err) {
+			return wrapAPOCError(err)
+		}
+		return err
 	}
-	return err
+
+	if opts.AssumeEndpointsExist {
+		records, _ := result["records"].([]map[string]interface{})
+		return checkImportedRelationships(records, len(relData))
+	}
+	return nil
 }
 
 // getNodeImportQuery generates the appropriate node import query based on base entity label setting
@@ -162,13 +292,16 @@ func (n *Neo4j) getNodeImportQuery(includeSource bool) string {
 		} else {
 			queryParts = append(queryParts, "WITH source, node")
 		}
-		queryParts = append(queryParts, "CALL apoc.create.addLabels(source, [node.type]) YIELD node AS n")
+		queryParts = append(queryParts, "CALL apoc.create.addLabels(source, node.labels) YIELD node AS n")
 	} else {
 		// Use dynamic labels approach
 		if includeSource {
 			queryParts = append(queryParts, "WITH d, node")
 		}
-		queryParts = append(queryParts, "CALL apoc.merge.node([node.type], {id: node.id}, node.properties, {}) YIELD node AS n")
+		// Merge on the type alone so that nodes stored before gaining labels are matched
+		queryParts = append(queryParts,
+			"CALL apoc.merge.node([node.type], {id: node.id}, node.properties, {}) YIELD node AS merged",
+			"CALL apoc.create.addLabels(merged, node.labels) YIELD node AS n")
 	}
 
 	if includeSource {
@@ -181,8 +314,26 @@ func (n *Neo4j) getNodeImportQuery(includeSource bool) string {
 	return strings.Join(queryParts, " ")
 }
 
-// getRelImportQuery generates the appropriate relationship import query based on base entity label setting
-func (n *Neo4j) getRelImportQuery() string {
+// getRelImportQuery generates the appropriate relationship import query based on base entity label setting.
+// When matchEndpoints is set, endpoints must already exist and relationships without both are skipped.
+// Endpoints are matched through the indexed base entity label when it is enabled. Each relationship
+// is counted once even if an id matches several nodes, so duplicates cannot hide a missing endpoint.
+func (n *Neo4j) getRelImportQuery(matchEndpoints bool) string {
+	if matchEndpoints {
+		if n.baseEntityLabel {
+			return fmt.Sprintf("UNWIND $relationships AS rel "+
+				"MATCH (source:`%s` {id: rel.source}) WHERE rel.source_label = '' OR rel.source_label IN labels(source) "+
+				"MATCH (target:`%s` {id: rel.target}) WHERE rel.target_label = '' OR rel.target_label IN labels(target) "+
+				"CALL apoc.merge.relationship(source, rel.type, {}, rel.properties, target) YIELD rel AS r "+
+				"RETURN count(DISTINCT rel.index) AS relationships_created", BASE_ENTITY_LABEL, BASE_ENTITY_LABEL)
+		}
+		return "UNWIND $relationships AS rel " +
+			"MATCH (source {id: rel.source}) WHERE rel.source_label = '' OR rel.source_label IN labels(source) " +
+			"MATCH (target {id: rel.target}) WHERE rel.target_label = '' OR rel.target_label IN labels(target) " +
+			"CALL apoc.merge.relationship(source, rel.type, {}, rel.properties, target) YIELD rel AS r " +
+			"RETURN count(DISTINCT rel.index) AS relationships_created"
+	}
+
 	if n.baseEntityLabel {
 		return fmt.Sprintf("UNWIND $relationships AS rel "+
 			"MERGE (source:%s {id: rel.source}) "+
@@ -199,56 +350,24 @@ func (n *Neo4j) getRelImportQuery() string {
 	}
 }
 
-// getSessionConfig returns the session configuration for this Neo4j instance
-func (n *Neo4j) getSessionConfig() neo4j.SessionConfig {
-	return neo4j.SessionConfig{DatabaseName: n.database}
-}
-
-// getNodeAddQuery generates the appropriate node addition query based on merge mode
-func (n *Neo4j) getNodeAddQuery(mode graphs.MergeMode) string {
-	switch mode {
-	case graphs.MergeModeCreate:
-		if n.baseEntityLabel {
-			return fmt.Sprintf("CREATE (n:`%s`:`%s` {id: $id, type: $type}) SET n += $properties", BASE_ENTITY_LABEL, "$type")
-		}
-		return "CREATE (n {id: $id}) SET n:($type) SET n += $properties"
-	case graphs.MergeModeUpdate:
-		return "MATCH (n {id: $id}) SET n += $properties"
-	case graphs.MergeModeReplace:
-		if n.baseEntityLabel {
-			return fmt.Sprintf("MERGE (n:`%s` {id: $id}) SET n:$type SET n = $properties", BASE_ENTITY_LABEL)
-		}
-		return "MERGE (n {id: $id}) SET n:($type) SET n = $properties"
-	default: // MergeModeUpsert
-		if n.baseEntityLabel {
-			return fmt.Sprintf("MERGE (n:`%s` {id: $id}) SET n:$type SET n += $properties", BASE_ENTITY_LABEL)
-		}
-		return "MERGE (n {id: $id}) SET n:($type) SET n += $properties"
+// checkImportedRelationships verifies the relationship import query wrote every relationship.
+// Relationships whose endpoints could not be matched are dropped by the query.
+func checkImportedRelationships(records []map[string]interface{}, expected int) error {
+	var imported int64
+	if len(records) > 0 {
+		imported, _ = records[0]["relationships_created"].(int64)
 	}
+	if imported < int64(expected) {
+		return fmt.Errorf("%w: only %d of %d relationships were imported", ErrEndpointNotFound, imported, expected)
+	}
+	return nil
 }
 
-// getRelationshipAddQuery generates the appropriate relationship addition query based on merge mode
-func (n *Neo4j) getRelationshipAddQuery(mode graphs.MergeMode) string {
-	switch mode {
-	case graphs.MergeModeCreate:
-		return `
-			MATCH (s {id: $sourceId}), (t {id: $targetId})
-			CREATE (s)-[r:` + "`$relType`" + `]->(t)
-			SET r = $properties`
-	case graphs.MergeModeUpdate:
-		return `
-			MATCH (s {id: $sourceId})-[r:` + "`$relType`" + `]->(t {id: $targetId})
-			SET r += $properties`
-	case graphs.MergeModeReplace:
-		return `
-			MATCH (s {id: $sourceId}), (t {id: $targetId})
-			MERGE (s)-[r:` + "`$relType`" + `]->(t)
-			SET r = $properties`
-	default: // MergeModeUpsert
-		return `
-			MATCH (s {id: $sourceId}), (t {id: $targetId})
-			MERGE (s)-[r:` + "`$relType`" + `]->(t)
-			SET r += $properties`
+// getSessionConfig returns the session configuration for this Neo4j instance
+func (n *Neo4j) getSessionConfig() neo4j.SessionConfig {
+	return neo4j.SessionConfig{
+		DatabaseName: n.database,
+		BoltLogger:   n.boltLogger,
 	}
 }
 
@@ -277,111 +396,566 @@ func (n *Neo4j) ensureBaseEntityConstraint(ctx context.Context) error {
 	return err
 }
 
-// AddNodes adds individual nodes to the Neo4j store
+// AddNodes adds individual nodes to the Neo4j store. Nodes are labeled with their type,
+// quoted so that types such as "Product Manager" work on plain Neo4j, and their Labels.
+// Labels besides the type require APOC. A node without a type is rejected with
+// ErrInvalidLabel before anything is written.
 func (n *Neo4j) AddNodes(ctx context.Context, nodes []graphs.Node, options ...graphs.Option) error {
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
+	defer n.invalidateQueryCache()
 
 	opts := graphs.NewOptions()
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withBatchTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	if opts.ForceLabel != "" {
+		if err := validateLabel(opts.ForceLabel); err != nil {
+			return err
+		}
+	}
+	for _, node := range nodes {
+		if node.Type == "" {
+			return fmt.Errorf("%w: node %s has no type", ErrInvalidLabel, node.ID)
+		}
+	}
+
+	opts.MergeMode = n.idempotentMergeMode(opts.MergeMode)
+
+	if opts.MergePropertiesFunc != nil && opts.MergeMode == graphs.MergeModeUpsert {
+		return n.addNodesWithMergeFunc(ctx, nodes, opts)
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(nodes)
+	}
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
-	for _, node := range nodes {
-		var query string
-		switch opts.MergeMode {
-		case graphs.MergeModeCreate:
-			if n.baseEntityLabel {
-				query = fmt.Sprintf("CREATE (n:`%s`:`%s` {id: $id}) SET n += $properties", node.Type, BASE_ENTITY_LABEL)
-			} else {
-				query = fmt.Sprintf("CREATE (n:`%s` {id: $id}) SET n += $properties", node.Type)
+	// One UNWIND query per node type and batch
+	types, groups := groupNodesByType(nodes)
+	for _, nodeType := range types {
+		group := groups[nodeType]
+		query := n.batchNodeQuery(opts.MergeMode, nodeType)
+		query += forceLabelClause(opts.ForceLabel)
+
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+
+			batchQuery := query
+			if hasExtraLabels(group[start:end]) {
+				batchQuery += extraLabelsClause
 			}
-		case graphs.MergeModeUpdate:
-			query = fmt.Sprintf("MATCH (n:`%s` {id: $id}) SET n += $properties", node.Type)
-		case graphs.MergeModeReplace:
-			if n.baseEntityLabel {
-				query = fmt.Sprintf("MERGE (n:`%s`:`%s` {id: $id}) SET n = $properties", node.Type, BASE_ENTITY_LABEL)
-			} else {
-				query = fmt.Sprintf("MERGE (n:`%s` {id: $id}) SET n = $properties", node.Type)
+
+			nodeData := make([]map[string]interface{}, 0, end-start)
+			for _, node := range group[start:end] {
+				nodeData = append(nodeData, map[string]interface{}{
+					"id":         node.ID,
+					"type":       node.Type,
+					"labels":     nodeLabels(node),
+					"properties": n.normalizeProperties(node.Properties),
+				})
+			}
+			params := map[string]interface{}{"nodes": nodeData}
+
+			err := n.withRetry(ctx, func() error {
+				runCtx, cancel := n.withQueryTimeout(ctx)
+				defer cancel()
+				result, err := session.Run(runCtx, batchQuery, n.withDefaultParams(params))
+				if err != nil {
+					return err
+				}
+				_, err = result.Consume(runCtx)
+				return err
+			})
+			if err != nil && isAPOCError(err) {
+				return wrapAPOCError(err)
 			}
-		default: // MergeModeUpsert
-			if n.baseEntityLabel {
-				query = fmt.Sprintf("MERGE (n:`%s`:`%s` {id: $id}) SET n += $properties", node.Type, BASE_ENTITY_LABEL)
-			} else {
-				query = fmt.Sprintf("MERGE (n:`%s` {id: $id}) SET n += $properties", node.Type)
+			if err != nil {
+				return fmt.Errorf("failed to add %d %s nodes: %w", len(nodeData), nodeType, err)
 			}
 		}
+	}
 
-		params := map[string]interface{}{
-			"id":         node.ID,
-			"properties": node.Properties,
-		}
+	return nil
+}
 
-		if _, err := session.Run(ctx, query, params); err != nil {
-			return fmt.Errorf("failed to add node %s: %w", node.ID, err)
+// groupNodesByType groups nodes by type, returning the types in order of first appearance
+func groupNodesByType(nodes []graphs.Node) ([]string, map[string][]graphs.Node) {
+	var types []string
+	groups := make(map[string][]graphs.Node)
+	for _, node := range nodes {
+		if _, ok := groups[node.Type]; !ok {
+			types = append(types, node.Type)
 		}
+		groups[node.Type] = append(groups[node.Type], node)
 	}
+	return types, groups
+}
 
-	return nil
+// batchNodeQuery returns the UNWIND query writing a batch of $nodes of one type. The type
+// is quoted, so types such as "Product Manager" need no APOC.
+func (n *Neo4j) batchNodeQuery(mode graphs.MergeMode, nodeType string) string {
+	labels := quoteIdentifier(nodeType)
+	if n.baseEntityLabel {
+		labels += ":" + quoteIdentifier(BASE_ENTITY_LABEL)
+	}
+
+	switch mode {
+	case graphs.MergeModeCreate:
+		return fmt.Sprintf("UNWIND $nodes AS node CREATE (n:%s {id: node.id}) SET n += node.properties", labels)
+	case graphs.MergeModeUpdate:
+		return fmt.Sprintf("UNWIND $nodes AS node MATCH (n:%s {id: node.id}) SET n += node.properties", quoteIdentifier(nodeType))
+	case graphs.MergeModeReplace:
+		return fmt.Sprintf("UNWIND $nodes AS node MERGE (n:%s {id: node.id}) SET n = node.properties", labels)
+	default: // MergeModeUpsert
+		return fmt.Sprintf("UNWIND $nodes AS node MERGE (n:%s {id: node.id}) SET n += node.properties", labels)
+	}
 }
 
-// AddRelationships adds individual relationships to the Neo4j store
+// AddRelationships adds individual relationships to the Neo4j store. Relationships
+// whose source or target node does not exist are skipped and reported with
+// ErrEndpointNotFound once the others have been written, unless the
+// CreateMissingEndpoints option is set, in which case missing endpoints are created
+// first. As with AddNodes, types are quoted, and a relationship without a type is
+// rejected with ErrInvalidRelType before anything is written.
 func (n *Neo4j) AddRelationships(ctx context.Context, relationships []graphs.Relationship, options ...graphs.Option) error {
 	if n.driver == nil {
 		return ErrDriverNotInitialized
 	}
+	defer n.invalidateQueryCache()
 
 	opts := graphs.NewOptions()
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := n.withBatchTimeout(ctx, opts)
+	defer cancel()
 
-	session := n.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: n.database})
+	opts.MergeMode = n.idempotentMergeMode(opts.MergeMode)
+
+	if opts.DropSelfLoops {
+		relationships = n.withoutSelfLoops(relationships)
+	}
+
+	if len(relationships) == 0 {
+		return nil
+	}
+	for _, rel := range relationships {
+		if rel.Type == "" {
+			return fmt.Errorf("%w: relationship %s->%s has no type", ErrInvalidRelType, rel.Source.ID, rel.Target.ID)
+		}
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(relationships)
+	}
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
 	defer session.Close(ctx)
 
+	if opts.CreateMissingEndpoints {
+		if err := n.createMissingEndpoints(ctx, session, relationships, batchSize); err != nil {
+			return err
+		}
+	}
+
+	if opts.MergePropertiesFunc != nil && opts.MergeMode == graphs.MergeModeUpsert {
+		return n.addRelationshipsWithMergeFunc(ctx, relationships, opts)
+	}
+
+	// One UNWIND query per relationship type and batch
+	types, groups := groupRelationshipsByType(relationships)
+	var missing []string
+	for _, relType := range types {
+		group := groups[relType]
+		query := batchRelationshipQuery(opts.MergeMode, relType)
+
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+
+			relData := make([]map[string]interface{}, 0, end-start)
+			for _, rel := range group[start:end] {
+				relData = append(relData, map[string]interface{}{
+					"source":     rel.Source.ID,
+					"target":     rel.Target.ID,
+					"type":       rel.Type,
+					"properties": n.normalizeProperties(rel.Properties),
+				})
+			}
+			params := map[string]interface{}{"relationships": relData}
+
+			var records []*neo4j.Record
+			err := n.withRetry(ctx, func() error {
+				runCtx, cancel := n.withQueryTimeout(ctx)
+				defer cancel()
+				result, err := session.Run(runCtx, query, n.withDefaultParams(params))
+				if err != nil {
+					return err
+				}
+				records, err = result.Collect(runCtx)
+				return err
+			})
+			if err != nil && isAPOCError(err) {
+				return wrapAPOCError(err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to add %d %s relationships: %w", len(relData), relType, err)
+			}
+
+			for _, record := range records {
+				source, _ := record.Get("source")
+				target, _ := record.Get("target")
+				missing = append(missing, fmt.Sprintf("%v-%s->%v", source, relType, target))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrEndpointNotFound, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// createMissingEndpoints creates the endpoints of relationships that are not in the store,
+// with one UNWIND query per endpoint type and batch. A created endpoint is labeled with its
+// type and takes the properties of the endpoint embedded in the relationship; existing
+// nodes are left untouched. An endpoint referenced with several types takes the first
+// non-empty one.
+func (n *Neo4j) createMissingEndpoints(ctx context.Context, session neo4j.SessionWithContext, relationships []graphs.Relationship, batchSize int) error {
+	var endpoints []graphs.Node
+	index := make(map[string]int)
+	for _, rel := range relationships {
+		for _, endpoint := range []graphs.Node{rel.Source, rel.Target} {
+			if i, ok := index[endpoint.ID]; !ok {
+				index[endpoint.ID] = len(endpoints)
+				endpoints = append(endpoints, endpoint)
+			} else if endpoints[i].Type == "" {
+				endpoints[i] = endpoint
+			}
+		}
+	}
+
+	types, groups := groupNodesByType(endpoints)
+	for _, nodeType := range types {
+		group := groups[nodeType]
+		query := n.missingEndpointQuery(nodeType)
+
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+
+			nodeData := make([]map[string]interface{}, 0, end-start)
+			for _, node := range group[start:end] {
+				nodeData = append(nodeData, map[string]interface{}{
+					"id":         node.ID,
+					"type":       node.Type,
+					"properties": n.normalizeProperties(node.Properties),
+				})
+			}
+			params := map[string]interface{}{"nodes": nodeData}
+
+			err := n.withRetry(ctx, func() error {
+				runCtx, cancel := n.withQueryTimeout(ctx)
+				defer cancel()
+				result, err := session.Run(runCtx, query, n.withDefaultParams(params))
+				if err != nil {
+					return err
+				}
+				_, err = result.Consume(runCtx)
+				return err
+			})
+			if err != nil && isAPOCError(err) {
+				return wrapAPOCError(err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create %d missing %s endpoints: %w", len(nodeData), nodeType, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// missingEndpointQuery returns the UNWIND query creating the $nodes of one type that do not
+// exist yet, labeled with the quoted type
+func (n *Neo4j) missingEndpointQuery(nodeType string) string {
+	var labels []string
+	if nodeType != "" {
+		labels = append(labels, quoteIdentifier(nodeType))
+	}
+	if n.baseEntityLabel {
+		labels = append(labels, quoteIdentifier(BASE_ENTITY_LABEL))
+	}
+
+	set := "SET n += node.properties, n.id = node.id"
+	if len(labels) > 0 {
+		set = fmt.Sprintf("SET n:%s, n += node.properties, n.id = node.id", strings.Join(labels, ":"))
+	}
+	return "UNWIND $nodes AS node MERGE (n {id: node.id}) ON CREATE " + set
+}
+
+// groupRelationshipsByType groups relationships by type, returning the types in order of first appearance
+func groupRelationshipsByType(relationships []graphs.Relationship) ([]string, map[string][]graphs.Relationship) {
+	var types []string
+	groups := make(map[string][]graphs.Relationship)
 	for _, rel := range relationships {
-		var query string
-		switch opts.MergeMode {
-		case graphs.MergeModeCreate:
-			query = fmt.Sprintf(`
-				MATCH (s {id: $sourceId}), (t {id: $targetId})
-				CREATE (s)-[r:%s]->(t)
-				SET r = $properties
-			`, rel.Type)
-		case graphs.MergeModeUpdate:
-			query = fmt.Sprintf(`
-				MATCH (s {id: $sourceId})-[r:%s]->(t {id: $targetId})
-				SET r += $properties
-			`, rel.Type)
-		case graphs.MergeModeReplace:
-			query = fmt.Sprintf(`
-				MATCH (s {id: $sourceId}), (t {id: $targetId})
-				MERGE (s)-[r:%s]->(t)
-				SET r = $properties
-			`, rel.Type)
-		default: // MergeModeUpsert
-			query = fmt.Sprintf(`
-				MATCH (s {id: $sourceId}), (t {id: $targetId})
-				MERGE (s)-[r:%s]->(t)
-				SET r += $properties
-			`, rel.Type)
+		if _, ok := groups[rel.Type]; !ok {
+			types = append(types, rel.Type)
 		}
+		groups[rel.Type] = append(groups[rel.Type], rel)
+	}
+	return types, groups
+}
+
+// batchRelationshipQuery returns the UNWIND query writing a batch of $relationships of one type.
+// Relationships are only written when both endpoints exist, and the query returns the source
+// and target of those whose endpoints were not found.
+func batchRelationshipQuery(mode graphs.MergeMode, relType string) string {
+	quoted := quoteIdentifier(relType)
+	var write string
+	switch mode {
+	case graphs.MergeModeCreate:
+		write = fmt.Sprintf("CREATE (s)-[r:%s]->(t) SET r = rel.properties", quoted)
+	case graphs.MergeModeUpdate:
+		write = fmt.Sprintf("FOREACH (r IN [(s)-[x:%s]->(t) | x] | SET r += rel.properties)", quoted)
+	case graphs.MergeModeReplace:
+		write = fmt.Sprintf("MERGE (s)-[r:%s]->(t) SET r = rel.properties", quoted)
+	default: // MergeModeUpsert
+		write = fmt.Sprintf("MERGE (s)-[r:%s]->(t) SET r += rel.properties", quoted)
+	}
+
+	return fmt.Sprintf(`
+		UNWIND $relationships AS rel
+		OPTIONAL MATCH (s {id: rel.source})
+		OPTIONAL MATCH (t {id: rel.target})
+		FOREACH (_ IN CASE WHEN s IS NULL OR t IS NULL THEN [] ELSE [1] END |
+			%s
+		)
+		WITH rel, s, t
+		WHERE s IS NULL OR t IS NULL
+		RETURN rel.source AS source, rel.target AS target
+	`, write)
+}
+
+// upsertNodeQuery returns the UNWIND query merging a batch of $nodes of one type and
+// replacing their properties with node.properties, keeping their id, so that keys
+// dropped by a merge func are removed
+func (n *Neo4j) upsertNodeQuery(nodeType string) string {
+	labels := quoteIdentifier(nodeType)
+	if n.baseEntityLabel {
+		labels += ":" + quoteIdentifier(BASE_ENTITY_LABEL)
+	}
+	return fmt.Sprintf("UNWIND $nodes AS node MERGE (n:%s {id: node.id}) SET n = node.properties, n.id = node.id", labels)
+}
+
+// extraLabelsClause labels the nodes written by a batch query with their node.labels.
+// Labels cannot be parameterized, so it requires APOC and is only used for nodes with
+// labels besides their type.
+const extraLabelsClause = " WITH n, node CALL apoc.create.addLabels(n, node.labels) YIELD node AS labeled RETURN count(labeled) AS labeled"
+
+// forceLabelClause returns the clause adding a forced label to the node n, if any
+func forceLabelClause(label string) string {
+	if label == "" {
+		return ""
+	}
+	return fmt.Sprintf(" SET n:`%s`", label)
+}
+
+// addNodesWithMergeFunc upserts nodes, combining stored and incoming properties with the
+// MergePropertiesFunc of opts. Nodes are grouped by type and batched as in AddNodes, and
+// the stored properties of each batch are read and written back in the same transaction.
+func (n *Neo4j) addNodesWithMergeFunc(ctx context.Context, nodes []graphs.Node, opts *graphs.Options) error {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(nodes)
+	}
+
+	types, groups := groupNodesByType(nodes)
+	for _, nodeType := range types {
+		group := groups[nodeType]
+		readQuery := fmt.Sprintf("UNWIND $ids AS id MATCH (n:%s {id: id}) RETURN n.id AS id, properties(n) AS properties", quoteIdentifier(nodeType))
+		query := n.upsertNodeQuery(nodeType) + forceLabelClause(opts.ForceLabel)
+
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+			batch := group[start:end]
 
-		params := map[string]interface{}{
-			"sourceId":   rel.Source.ID,
-			"targetId":   rel.Target.ID,
-			"properties": rel.Properties,
+			batchQuery := query
+			if hasExtraLabels(batch) {
+				batchQuery += extraLabelsClause
+			}
+
+			ids := make([]string, 0, len(batch))
+			for _, node := range batch {
+				ids = append(ids, node.ID)
+			}
+
+			runCtx, cancel := n.withQueryTimeout(ctx)
+			err := n.txManager.WithTransaction(runCtx, func(tx neo4j.ManagedTransaction) error {
+				stored, err := readStoredProperties(runCtx, tx, readQuery, n.withDefaultParams(map[string]interface{}{"ids": ids}), "id")
+				if err != nil {
+					return err
+				}
+
+				nodeData := make([]map[string]interface{}, 0, len(batch))
+				for _, node := range batch {
+					// Later duplicates of a node merge with the result of earlier ones
+					merged := opts.MergePropertiesFunc(storedOrEmpty(stored, node.ID), node.Properties)
+					stored[node.ID] = merged
+					nodeData = append(nodeData, map[string]interface{}{
+						"id":         node.ID,
+						"type":       node.Type,
+						"labels":     nodeLabels(node),
+						"properties": n.normalizeProperties(merged),
+					})
+				}
+
+				result, err := tx.Run(runCtx, batchQuery, n.withDefaultParams(map[string]interface{}{"nodes": nodeData}))
+				if err != nil {
+					return err
+				}
+				_, err = result.Consume(runCtx)
+				return err
+			})
+			cancel()
+			if err != nil && isAPOCError(err) {
+				return wrapAPOCError(err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to add %d %s nodes: %w", len(batch), nodeType, err)
+			}
 		}
+	}
 
-		if _, err := session.Run(ctx, query, params); err != nil {
-			return fmt.Errorf("failed to add relationship %s-%s->%s: %w",
-				rel.Source.ID, rel.Type, rel.Target.ID, err)
+	return nil
+}
+
+// addRelationshipsWithMergeFunc upserts relationships, combining stored and incoming properties
+// with the MergePropertiesFunc of opts. Relationships are grouped by type and batched as in
+// AddRelationships, and the stored properties of each batch are read and written back in the
+// same transaction. Relationships whose endpoints do not exist are skipped and reported with
+// ErrEndpointNotFound once the others have been written.
+func (n *Neo4j) addRelationshipsWithMergeFunc(ctx context.Context, relationships []graphs.Relationship, opts *graphs.Options) error {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(relationships)
+	}
+
+	types, groups := groupRelationshipsByType(relationships)
+	var missing []string
+	for _, relType := range types {
+		group := groups[relType]
+		readQuery := fmt.Sprintf("UNWIND $relationships AS rel "+
+			"MATCH (s {id: rel.source})-[r:%s]->(t {id: rel.target}) "+
+			"RETURN rel.source + '->' + rel.target AS key, properties(r) AS properties", quoteIdentifier(relType))
+		query := batchRelationshipQuery(graphs.MergeModeReplace, relType)
+
+		for start := 0; start < len(group); start += batchSize {
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+			batch := group[start:end]
+
+			endpoints := make([]map[string]interface{}, 0, len(batch))
+			for _, rel := range batch {
+				endpoints = append(endpoints, map[string]interface{}{"source": rel.Source.ID, "target": rel.Target.ID})
+			}
+
+			var records []*neo4j.Record
+			runCtx, cancel := n.withQueryTimeout(ctx)
+			err := n.txManager.WithTransaction(runCtx, func(tx neo4j.ManagedTransaction) error {
+				stored, err := readStoredProperties(runCtx, tx, readQuery, n.withDefaultParams(map[string]interface{}{"relationships": endpoints}), "key")
+				if err != nil {
+					return err
+				}
+
+				relData := make([]map[string]interface{}, 0, len(batch))
+				for _, rel := range batch {
+					key := rel.Source.ID + "->" + rel.Target.ID
+					merged := opts.MergePropertiesFunc(storedOrEmpty(stored, key), rel.Properties)
+					stored[key] = merged
+					relData = append(relData, map[string]interface{}{
+						"source":     rel.Source.ID,
+						"target":     rel.Target.ID,
+						"type":       rel.Type,
+						"properties": n.normalizeProperties(merged),
+					})
+				}
+
+				result, err := tx.Run(runCtx, query, n.withDefaultParams(map[string]interface{}{"relationships": relData}))
+				if err != nil {
+					return err
+				}
+				records, err = result.Collect(runCtx)
+				return err
+			})
+			cancel()
+			if err != nil {
+				return fmt.Errorf("failed to add %d %s relationships: %w", len(batch), relType, err)
+			}
+
+			for _, record := range records {
+				source, _ := record.Get("source")
+				target, _ := record.Get("target")
+				missing = append(missing, fmt.Sprintf("%v-%s->%v", source, relType, target))
+			}
 		}
 	}
 
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrEndpointNotFound, strings.Join(missing, ", "))
+	}
 	return nil
 }
+
+// readStoredProperties runs a query returning a key column and a properties column and maps
+// every key to the stored properties
+func readStoredProperties(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]interface{}, key string) (map[string]map[string]interface{}, error) {
+	result, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]map[string]interface{})
+	for result.Next(ctx) {
+		record := result.Record()
+		k, _ := record.Get(key)
+		properties, _ := record.Get("properties")
+		if id, ok := k.(string); ok {
+			if props, ok := properties.(map[string]interface{}); ok {
+				stored[id] = props
+			}
+		}
+	}
+	return stored, result.Err()
+}
+
+// storedOrEmpty returns the properties stored under key, or an empty map when the entity
+// does not exist yet
+func storedOrEmpty(stored map[string]map[string]interface{}, key string) map[string]interface{} {
+	if properties, ok := stored[key]; ok {
+		return properties
+	}
+	return make(map[string]interface{})
+}
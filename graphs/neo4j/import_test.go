@@ -0,0 +1,1058 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+func TestAddGraphDocumentCrossDocumentRelationship(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	alice := graphs.NewNode("alice", "Person")
+	acme := graphs.NewNode("acme", "Company")
+
+	// The relationship lives in the first document but its target is only
+	// defined in the second one, and both endpoints are referenced by ID only
+	first := graphs.NewGraphDocument(schema.Document{PageContent: "first"})
+	first.AddNode(alice)
+	first.AddRelationship(graphs.NewRelationship(graphs.Node{ID: "alice"}, graphs.Node{ID: "acme"}, "works at"))
+
+	second := graphs.NewGraphDocument(schema.Document{PageContent: "second"})
+	second.AddNode(acme)
+
+	err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{first, second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 3 {
+		t.Fatalf("Expected 2 node imports and 1 relationship import, got %d queries", len(driver.queries))
+	}
+	for i, q := range driver.queries[:2] {
+		if _, ok := q.params["nodes"]; !ok {
+			t.Errorf("Expected query %d to import nodes, got %s", i, q.query)
+		}
+	}
+
+	relQuery := driver.queries[2]
+	relData, ok := relQuery.params["relationships"].([]map[string]interface{})
+	if !ok || len(relData) != 1 {
+		t.Fatalf("Expected the last query to import 1 relationship, got %v", relQuery.params)
+	}
+	if relData[0]["source_label"] != "Person" || relData[0]["target_label"] != "Company" {
+		t.Errorf("Expected endpoint labels resolved from the batch, got %v", relData[0])
+	}
+	if relData[0]["type"] != "WORKS_AT" {
+		t.Errorf("Expected normalized relationship type, got %v", relData[0]["type"])
+	}
+	if !strings.Contains(relQuery.query, "apoc.merge.relationship") {
+		t.Errorf("Expected relationship import query, got %s", relQuery.query)
+	}
+}
+
+func TestAddNodesWithMergePropertiesFunc(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "properties(n) AS properties") {
+			return []*neo4j.Record{newRecord("id", "alice", "properties", map[string]interface{}{
+				"name":    "Alice",
+				"updated": int64(200),
+			})}, nil
+		}
+		return nil, nil
+	}
+
+	// Keep the most recent update timestamp
+	keepLatest := func(existing, incoming map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{})
+		for k, v := range incoming {
+			merged[k] = v
+		}
+		if prev, ok := existing["updated"].(int64); ok {
+			if next, ok := incoming["updated"].(int64); !ok || prev > next {
+				merged["updated"] = prev
+			}
+		}
+		return merged
+	}
+
+	node := graphs.NewNode("alice", "Person")
+	node.SetProperty("name", "Alice Smith")
+	node.SetProperty("updated", int64(100))
+
+	err := n4j.AddNodes(context.Background(), []graphs.Node{node}, graphs.WithMergePropertiesFunc(keepLatest))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected a read and a write query, got %d", len(driver.queries))
+	}
+	for _, q := range driver.queries {
+		if !q.inTx {
+			t.Errorf("Expected %q to run inside a transaction", q.query)
+		}
+	}
+
+	props := driver.queries[1].params["nodes"].([]map[string]interface{})[0]["properties"].(map[string]interface{})
+	if props["updated"] != int64(200) {
+		t.Errorf("Expected existing newer timestamp to be preserved, got %v", props["updated"])
+	}
+	if props["name"] != "Alice Smith" {
+		t.Errorf("Expected incoming name to be applied, got %v", props["name"])
+	}
+}
+
+func TestAddRelationshipsWithMergePropertiesFunc(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "properties(r) AS properties") {
+			return []*neo4j.Record{newRecord("key", "alice->bob", "properties", map[string]interface{}{"since": "2010"})}, nil
+		}
+		return nil, nil
+	}
+
+	keepExisting := func(existing, incoming map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{})
+		for k, v := range incoming {
+			merged[k] = v
+		}
+		for k, v := range existing {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	rel := graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("bob", "Person"), "KNOWS")
+	rel.SetProperty("since", "2020")
+
+	err := n4j.AddRelationships(context.Background(), []graphs.Relationship{rel}, graphs.WithMergePropertiesFunc(keepExisting))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	props := driver.queries[len(driver.queries)-1].params["relationships"].([]map[string]interface{})[0]["properties"].(map[string]interface{})
+	if props["since"] != "2010" {
+		t.Errorf("Expected existing value to be preserved, got %v", props["since"])
+	}
+}
+
+func TestMergePropertiesFuncRemovesDroppedKeys(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "AS properties") {
+			return []*neo4j.Record{newRecord("id", "alice", "key", "alice->bob", "properties", map[string]interface{}{"name": "Alice", "draft": true})}, nil
+		}
+		return nil, nil
+	}
+
+	dropDraft := func(existing, incoming map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{})
+		for k, v := range existing {
+			merged[k] = v
+		}
+		for k, v := range incoming {
+			merged[k] = v
+		}
+		delete(merged, "draft")
+		return merged
+	}
+
+	node := graphs.NewNode("alice", "Person")
+	if err := n4j.AddNodes(context.Background(), []graphs.Node{node}, graphs.WithMergePropertiesFunc(dropDraft)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rel := graphs.NewRelationship(node, graphs.NewNode("bob", "Person"), "KNOWS")
+	if err := n4j.AddRelationships(context.Background(), []graphs.Relationship{rel}, graphs.WithMergePropertiesFunc(dropDraft)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var writes int
+	for _, q := range driver.queries {
+		if !strings.Contains(q.query, "MERGE") {
+			continue
+		}
+		writes++
+		if strings.Contains(q.query, "+=") || !(strings.Contains(q.query, "SET n = node.properties, n.id = node.id") || strings.Contains(q.query, "SET r = rel.properties")) {
+			t.Errorf("Expected the merged properties to replace the stored ones, got %s", q.query)
+		}
+		data, ok := q.params["nodes"].([]map[string]interface{})
+		if !ok {
+			data = q.params["relationships"].([]map[string]interface{})
+		}
+		if _, ok := data[0]["properties"].(map[string]interface{})["draft"]; ok {
+			t.Errorf("Expected the dropped key not to be written, got %v", data[0]["properties"])
+		}
+	}
+	if writes != 2 {
+		t.Errorf("Expected 2 writes, got %d", writes)
+	}
+}
+
+func TestMergePropertiesFuncBatchesByType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	keepIncoming := func(existing, incoming map[string]interface{}) map[string]interface{} { return incoming }
+
+	alice := graphs.NewNode("alice", "Person")
+	alice.Labels = []string{"Employee"}
+	nodes := []graphs.Node{alice, graphs.NewNode("bob", "Person"), graphs.NewNode("carol", "Person"), graphs.NewNode("acme", "Company")}
+	err := n4j.AddNodes(context.Background(), nodes, graphs.WithMergePropertiesFunc(keepIncoming), graphs.WithBatchSize(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A read and a write per batch: two Person batches and one Company batch
+	if len(driver.queries) != 6 {
+		t.Fatalf("Expected 6 queries, got %d", len(driver.queries))
+	}
+	if ids := driver.queries[0].params["ids"].([]string); !reflect.DeepEqual(ids, []string{"alice", "bob"}) {
+		t.Errorf("Expected the first batch to read alice and bob, got %v", ids)
+	}
+	if q := driver.queries[1].query; !strings.Contains(q, "MERGE (n:`Person` {id: node.id})") || !strings.Contains(q, "apoc.create.addLabels") {
+		t.Errorf("Expected the first batch to be written with its extra labels, got %s", q)
+	}
+	if q := driver.queries[3].query; strings.Contains(q, "apoc.create.addLabels") {
+		t.Errorf("Expected batches without extra labels not to need APOC, got %s", q)
+	}
+	if q := driver.queries[5].query; !strings.Contains(q, "MERGE (n:`Company` {id: node.id})") {
+		t.Errorf("Expected the Company batch last, got %s", q)
+	}
+}
+
+func TestMergePropertiesFuncRejectsEmptyTypes(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	keepIncoming := func(existing, incoming map[string]interface{}) map[string]interface{} { return incoming }
+
+	err := n4j.AddNodes(context.Background(), []graphs.Node{{ID: "alice"}}, graphs.WithMergePropertiesFunc(keepIncoming))
+	if !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Expected ErrInvalidLabel, got %v", err)
+	}
+	rels := []graphs.Relationship{graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("bob", "Person"), "")}
+	err = n4j.AddRelationships(context.Background(), rels, graphs.WithMergePropertiesFunc(keepIncoming))
+	if !errors.Is(err, ErrInvalidRelType) {
+		t.Errorf("Expected ErrInvalidRelType, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestAddNodesWithForceLabel(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	nodes := []graphs.Node{
+		graphs.NewNode("alice", "Person"),
+		graphs.NewNode("acme", "Company"),
+	}
+
+	err := n4j.AddNodes(context.Background(), nodes, graphs.WithForceLabel("Batch42"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(driver.queries))
+	}
+	for i, typ := range []string{"Person", "Company"} {
+		query := driver.queries[i].query
+		if !strings.Contains(query, "(n:`"+typ+"` {id: node.id})") {
+			t.Errorf("Expected node type %s to be kept, got %s", typ, query)
+		}
+		if !strings.Contains(query, "SET n:`Batch42`") {
+			t.Errorf("Expected forced label in query, got %s", query)
+		}
+	}
+}
+
+func TestAddNodesWithInvalidForceLabel(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	err := n4j.AddNodes(context.Background(), []graphs.Node{graphs.NewNode("alice", "Person")},
+		graphs.WithForceLabel("Batch` DETACH DELETE n //"))
+	if !errors.Is(err, ErrInvalidLabel) {
+		t.Fatalf("Expected ErrInvalidLabel, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
+
+func TestAddGraphDocumentAssumeEndpointsExist(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if rels, ok := params["relationships"].([]map[string]interface{}); ok {
+			return []*neo4j.Record{newRecord("relationships_created", int64(len(rels)))}, nil
+		}
+		return nil, nil
+	}
+
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddRelationship(graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("acme", "Company"), "WORKS_AT"))
+
+	err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}, graphs.WithAssumeEndpointsExist(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query := driver.queries[len(driver.queries)-1].query
+	if !strings.Contains(query, "MATCH (source {id: rel.source})") || !strings.Contains(query, "MATCH (target {id: rel.target})") {
+		t.Errorf("Expected endpoints to be matched, got %s", query)
+	}
+	if strings.Contains(query, "apoc.merge.node") || strings.Contains(query, "MERGE (source") {
+		t.Errorf("Expected endpoints not to be merged, got %s", query)
+	}
+	if !strings.Contains(query, "count(DISTINCT rel.index)") {
+		t.Errorf("Expected every relationship to be counted once, got %s", query)
+	}
+}
+
+func TestAddGraphDocumentAssumeEndpointsExistBaseEntityLabel(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithBaseEntityLabel(true))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if rels, ok := params["relationships"].([]map[string]interface{}); ok {
+			return []*neo4j.Record{newRecord("relationships_created", int64(len(rels)))}, nil
+		}
+		return nil, nil
+	}
+
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddRelationship(graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("acme", "Company"), "WORKS_AT"))
+
+	err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}, graphs.WithAssumeEndpointsExist(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	query := driver.queries[len(driver.queries)-1].query
+	for _, want := range []string{"MATCH (source:`__Entity__` {id: rel.source})", "MATCH (target:`__Entity__` {id: rel.target})", "rel.source_label IN labels(source)", "count(DISTINCT rel.index)"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("Expected the query to contain %s, got %s", want, query)
+		}
+	}
+}
+
+func TestAddGraphDocumentAssumeEndpointsExistMissingEndpoint(t *testing.T) {
+	for _, inTx := range []bool{false, true} {
+		n4j, driver := newFakeNeo4j()
+		driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+			if _, ok := params["relationships"]; ok {
+				return []*neo4j.Record{newRecord("relationships_created", int64(1))}, nil
+			}
+			return nil, nil
+		}
+
+		doc := graphs.NewGraphDocument(schema.Document{})
+		doc.AddRelationship(graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("acme", "Company"), "WORKS_AT"))
+		doc.AddRelationship(graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("ghost", "Person"), "KNOWS"))
+		docs := []graphs.GraphDocument{doc}
+
+		var err error
+		if inTx {
+			err = n4j.TransactionManager().AddGraphDocumentWithTransaction(context.Background(), docs, graphs.WithAssumeEndpointsExist(true))
+		} else {
+			err = n4j.AddGraphDocument(context.Background(), docs, graphs.WithAssumeEndpointsExist(true))
+		}
+
+		if !errors.Is(err, ErrEndpointNotFound) {
+			t.Errorf("Expected ErrEndpointNotFound (transaction: %v), got %v", inTx, err)
+		}
+		if inTx && driver.rollbacks != 1 {
+			t.Errorf("Expected the transaction to roll back, got %d rollbacks", driver.rollbacks)
+		}
+	}
+}
+
+func TestTimezoneNormalizesTemporalProperties(t *testing.T) {
+	utc := time.UTC
+	n4j, driver := newFakeNeo4j(WithTimezone(utc))
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, tokyo)
+
+	alice := graphs.NewNode("alice", "Person")
+	alice.SetProperty("created", created)
+	alice.SetProperty("visits", []interface{}{created, "not a time"})
+
+	if err := n4j.AddNodes(context.Background(), []graphs.Node{alice}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	props := driver.queries[0].params["nodes"].([]map[string]interface{})[0]["properties"].(map[string]interface{})
+	got, ok := props["created"].(time.Time)
+	if !ok || got.Location() != utc || !got.Equal(created) {
+		t.Errorf("Expected %v normalized to UTC, got %v", created, props["created"])
+	}
+	if got.Hour() != 0 || got.Minute() != 30 {
+		t.Errorf("Expected 00:30 UTC, got %v", got)
+	}
+
+	visits := props["visits"].([]interface{})
+	if visit, ok := visits[0].(time.Time); !ok || visit.Location() != utc {
+		t.Errorf("Expected list values to be normalized, got %v", visits[0])
+	}
+	if visits[1] != "not a time" {
+		t.Errorf("Expected non-temporal values to be unchanged, got %v", visits[1])
+	}
+
+	if original := alice.Properties["created"].(time.Time); original.Location() != tokyo {
+		t.Error("Expected the caller's properties to be left unchanged")
+	}
+}
+
+func TestTimezoneNormalizesImportedRelationships(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithTimezone(time.UTC))
+
+	since := time.Date(2020, 1, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	rel := graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("bob", "Person"), "KNOWS")
+	rel.SetProperty("since", since)
+
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddRelationship(rel)
+	if err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rels := driver.queries[len(driver.queries)-1].params["relationships"].([]map[string]interface{})
+	got := rels[0]["properties"].(map[string]interface{})["since"].(time.Time)
+	if got.Location() != time.UTC || got.Hour() != 17 {
+		t.Errorf("Expected 17:00 UTC, got %v", got)
+	}
+}
+
+func TestAddGraphDocumentAtomic(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddNode(graphs.NewNode("alice", "Person"))
+	doc.AddNode(graphs.NewNode("acme", "Company"))
+	doc.AddRelationship(graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("acme", "Company"), "WORKS_AT"))
+
+	if err := n4j.AddGraphDocumentAtomic(context.Background(), doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected node and relationship imports, got %d queries", len(driver.queries))
+	}
+	for _, q := range driver.queries {
+		if !q.inTx {
+			t.Errorf("Expected %q to run in the transaction", q.query)
+		}
+	}
+	if driver.commits != 1 || driver.rollbacks != 0 {
+		t.Errorf("Expected a single commit, got %d commits and %d rollbacks", driver.commits, driver.rollbacks)
+	}
+}
+
+func TestAddGraphDocumentAtomicRollsBackOnRelationshipFailure(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if _, ok := params["relationships"]; ok {
+			return nil, errors.New("relationship import failed")
+		}
+		return nil, nil
+	}
+
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddNode(graphs.NewNode("alice", "Person"))
+	doc.AddRelationship(graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("acme", "Company"), "WORKS_AT"))
+
+	if err := n4j.AddGraphDocumentAtomic(context.Background(), doc); err == nil {
+		t.Fatal("Expected the relationship failure to be returned")
+	}
+
+	if driver.rollbacks != 1 || driver.commits != 0 {
+		t.Errorf("Expected the node import to be rolled back, got %d commits and %d rollbacks", driver.commits, driver.rollbacks)
+	}
+	if _, ok := driver.queries[0].params["nodes"]; !ok || !driver.queries[0].inTx {
+		t.Error("Expected nodes to be written inside the rolled back transaction")
+	}
+}
+
+// BenchmarkAddNodes10k measures a 10k-node insert against the fake driver, so it
+// counts client-side work and round-trips rather than server time.
+//
+// One query per node (before batching):
+//
+//	BenchmarkAddNodes10k  148  8685512 ns/op  10000 queries/op  7294680 B/op  60231 allocs/op
+//
+// One UNWIND query per type and batch of 100 (after):
+//
+//	BenchmarkAddNodes10k  218  5538177 ns/op    100 queries/op  5164616 B/op  30696 allocs/op
+func BenchmarkAddNodes10k(b *testing.B) {
+	nodes := make([]graphs.Node, 10000)
+	for i := range nodes {
+		nodes[i] = graphs.NewNode(fmt.Sprintf("node-%d", i), []string{"Person", "Company"}[i%2])
+	}
+
+	for i := 0; i < b.N; i++ {
+		n4j, driver := newFakeNeo4j()
+		if err := n4j.AddNodes(context.Background(), nodes); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		b.ReportMetric(float64(len(driver.queries)), "queries/op")
+	}
+}
+
+func TestAddNodesBatchesByType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	nodes := []graphs.Node{
+		graphs.NewNode("alice", "Person"),
+		graphs.NewNode("acme", "Company"),
+		graphs.NewNode("bob", "Person"),
+		graphs.NewNode("carol", "Person"),
+	}
+
+	err := n4j.AddNodes(context.Background(), nodes, graphs.WithBatchSize(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Person splits into two batches of at most 2, Company fits in one
+	if len(driver.queries) != 3 {
+		t.Fatalf("Expected 3 batched queries, got %d", len(driver.queries))
+	}
+
+	expected := []struct {
+		label string
+		ids   []string
+	}{
+		{"Person", []string{"alice", "bob"}},
+		{"Person", []string{"carol"}},
+		{"Company", []string{"acme"}},
+	}
+	for i, want := range expected {
+		q := driver.queries[i]
+		if !strings.Contains(q.query, "UNWIND $nodes AS node MERGE (n:`"+want.label+"` {id: node.id})") {
+			t.Errorf("Expected query %d to merge %s nodes, got %s", i, want.label, q.query)
+		}
+		nodeData := q.params["nodes"].([]map[string]interface{})
+		if len(nodeData) != len(want.ids) {
+			t.Fatalf("Expected query %d to carry %d nodes, got %d", i, len(want.ids), len(nodeData))
+		}
+		for j, id := range want.ids {
+			if nodeData[j]["id"] != id {
+				t.Errorf("Expected node %s at query %d position %d, got %v", id, i, j, nodeData[j]["id"])
+			}
+		}
+	}
+}
+
+func TestAddNodesWithBaseEntityLabel(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithBaseEntityLabel(true))
+
+	err := n4j.AddNodes(context.Background(), []graphs.Node{graphs.NewNode("alice", "Person")},
+		graphs.WithMergeMode(graphs.MergeModeCreate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(driver.queries[0].query, "CREATE (n:`Person`:`"+BASE_ENTITY_LABEL+"` {id: node.id})") {
+		t.Errorf("Expected the base entity label, got %s", driver.queries[0].query)
+	}
+}
+
+func TestAddRelationshipsBatchesByType(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	alice := graphs.NewNode("alice", "Person")
+	bob := graphs.NewNode("bob", "Person")
+	acme := graphs.NewNode("acme", "Company")
+
+	var rels []graphs.Relationship
+	for i := 0; i < 50; i++ {
+		rels = append(rels,
+			graphs.NewRelationship(alice, bob, "KNOWS"),
+			graphs.NewRelationship(alice, acme, "WORKS_AT"),
+			graphs.NewRelationship(bob, acme, "WORKS_AT"),
+		)
+	}
+
+	err := n4j.AddRelationships(context.Background(), rels)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 150 relationships of 2 distinct types take one round-trip per type
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(driver.queries))
+	}
+
+	expected := []struct {
+		relType string
+		count   int
+	}{
+		{"KNOWS", 50},
+		{"WORKS_AT", 100},
+	}
+	for i, want := range expected {
+		q := driver.queries[i]
+		if !strings.Contains(q.query, "UNWIND $relationships AS rel") ||
+			!strings.Contains(q.query, "MERGE (s)-[r:`"+want.relType+"`]->(t)") {
+			t.Errorf("Expected query %d to merge %s relationships, got %s", i, want.relType, q.query)
+		}
+		relData := q.params["relationships"].([]map[string]interface{})
+		if len(relData) != want.count {
+			t.Errorf("Expected query %d to carry %d relationships, got %d", i, want.count, len(relData))
+		}
+	}
+}
+
+func TestAddRelationshipsMissingEndpoint(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		var records []*neo4j.Record
+		for _, rel := range params["relationships"].([]map[string]interface{}) {
+			if rel["target"] == "ghost" {
+				records = append(records, newRecord("source", rel["source"], "target", rel["target"]))
+			}
+		}
+		return records, nil
+	}
+
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("bob", "Person"), "KNOWS"),
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("ghost", "Person"), "KNOWS"),
+	}
+
+	err := n4j.AddRelationships(context.Background(), rels)
+	if !errors.Is(err, ErrEndpointNotFound) {
+		t.Fatalf("Expected ErrEndpointNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "alice-KNOWS->ghost") || strings.Contains(err.Error(), "bob") {
+		t.Errorf("Expected only the unmatched relationship to be reported, got %v", err)
+	}
+}
+
+func TestAddRelationshipsEmpty(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	if err := n4j.AddRelationships(context.Background(), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 0 || len(driver.sessions) != 0 {
+		t.Errorf("Expected no round-trips for an empty slice, got %d queries", len(driver.queries))
+	}
+}
+
+func TestAddNodesQuotesTypes(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	nodes := []graphs.Node{
+		graphs.NewNode("alice", "Person"),
+		graphs.NewNode("pm", "Product Manager"),
+		graphs.NewNode("x", "Odd`Type"),
+	}
+	if err := n4j.AddNodes(context.Background(), nodes, graphs.WithForceLabel("Batch42")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 3 {
+		t.Fatalf("Expected 3 queries, got %d", len(driver.queries))
+	}
+	expected := []string{
+		"MERGE (n:`Person` {id: node.id})",
+		"MERGE (n:`Product Manager` {id: node.id})",
+		"MERGE (n:`Odd``Type` {id: node.id})",
+	}
+	for i, want := range expected {
+		q := driver.queries[i].query
+		if !strings.Contains(q, want) || !strings.Contains(q, "SET n:`Batch42`") {
+			t.Errorf("Expected query %d to contain %s, got %s", i, want, q)
+		}
+		if strings.Contains(q, "apoc") {
+			t.Errorf("Expected quoted types not to need APOC, got %s", q)
+		}
+	}
+}
+
+func TestAddRejectsEmptyTypes(t *testing.T) {
+	alice := graphs.NewNode("alice", "Person")
+	bob := graphs.NewNode("bob", "Person")
+
+	for _, mode := range []graphs.MergeMode{graphs.MergeModeCreate, graphs.MergeModeUpdate, graphs.MergeModeReplace, graphs.MergeModeUpsert} {
+		n4j, driver := newFakeNeo4j()
+
+		err := n4j.AddNodes(context.Background(), []graphs.Node{alice, {ID: "anon"}}, graphs.WithMergeMode(mode))
+		if !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("Expected ErrInvalidLabel in mode %d, got %v", mode, err)
+		}
+		rels := []graphs.Relationship{graphs.NewRelationship(alice, bob, "KNOWS"), graphs.NewRelationship(alice, bob, "")}
+		err = n4j.AddRelationships(context.Background(), rels, graphs.WithMergeMode(mode), graphs.WithCreateMissingEndpoints(true))
+		if !errors.Is(err, ErrInvalidRelType) {
+			t.Errorf("Expected ErrInvalidRelType in mode %d, got %v", mode, err)
+		}
+		if len(driver.queries) != 0 {
+			t.Errorf("Expected no queries in mode %d, got %d", mode, len(driver.queries))
+		}
+	}
+}
+
+func TestAddRelationshipsQuotesTypes(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	alice := graphs.NewNode("alice", "Person")
+	bob := graphs.NewNode("bob", "Person")
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(alice, bob, "WORKED WITH"),
+		graphs.NewRelationship(alice, bob, "ODD`TYPE"),
+	}
+
+	if err := n4j.AddRelationships(context.Background(), rels, graphs.WithMergeMode(graphs.MergeModeCreate)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i, want := range []string{"CREATE (s)-[r:`WORKED WITH`]->(t)", "CREATE (s)-[r:`ODD``TYPE`]->(t)"} {
+		q := driver.queries[i].query
+		if !strings.Contains(q, want) || strings.Contains(q, "apoc") {
+			t.Errorf("Expected query %d to contain %s without APOC, got %s", i, want, q)
+		}
+	}
+}
+
+func TestMergeFuncQuotesTypes(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	keepIncoming := func(existing, incoming map[string]interface{}) map[string]interface{} { return incoming }
+
+	odd := graphs.NewNode("odd", "Odd`Type")
+	if err := n4j.AddNodes(context.Background(), []graphs.Node{odd}, graphs.WithMergePropertiesFunc(keepIncoming)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rels := []graphs.Relationship{graphs.NewRelationship(odd, odd, "KNOWS`]->() DETACH DELETE s //")}
+	if err := n4j.AddRelationships(context.Background(), rels, graphs.WithMergePropertiesFunc(keepIncoming)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, q := range driver.queries {
+		if !strings.Contains(q.query, "`Odd``Type`") && !strings.Contains(q.query, "`KNOWS``]->() DETACH DELETE s //`") {
+			t.Errorf("Expected the type to be quoted, got %s", q.query)
+		}
+	}
+}
+
+func TestAddRelationshipsCreateMissingEndpoints(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	ghost := graphs.NewNode("ghost", "Person")
+	ghost.SetProperty("name", "Ghost")
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("acme", "Company"), "WORKS_AT"),
+		graphs.NewRelationship(graphs.Node{ID: "alice"}, ghost, "KNOWS"),
+	}
+
+	err := n4j.AddRelationships(context.Background(), rels, graphs.WithCreateMissingEndpoints(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Endpoints are merged per type before the relationships are written
+	if len(driver.queries) != 4 {
+		t.Fatalf("Expected 2 endpoint and 2 relationship queries, got %d", len(driver.queries))
+	}
+	expected := []struct {
+		label string
+		ids   []string
+	}{
+		{"Person", []string{"alice", "ghost"}},
+		{"Company", []string{"acme"}},
+	}
+	for i, want := range expected {
+		q := driver.queries[i]
+		if !strings.Contains(q.query, "MERGE (n {id: node.id}) ON CREATE SET n:`"+want.label+"`, n += node.properties") {
+			t.Errorf("Expected query %d to create missing %s endpoints, got %s", i, want.label, q.query)
+		}
+		nodeData := q.params["nodes"].([]map[string]interface{})
+		if len(nodeData) != len(want.ids) {
+			t.Fatalf("Expected query %d to carry %d endpoints, got %d", i, len(want.ids), len(nodeData))
+		}
+		for j, id := range want.ids {
+			if nodeData[j]["id"] != id {
+				t.Errorf("Expected endpoint %s at query %d position %d, got %v", id, i, j, nodeData[j]["id"])
+			}
+		}
+	}
+	if props := driver.queries[0].params["nodes"].([]map[string]interface{})[1]["properties"].(map[string]interface{}); props["name"] != "Ghost" {
+		t.Errorf("Expected the embedded endpoint properties, got %v", props)
+	}
+	if !strings.Contains(driver.queries[2].query, "MERGE (s)-[r:`WORKS_AT`]->(t)") {
+		t.Errorf("Expected the relationships to follow, got %s", driver.queries[2].query)
+	}
+}
+
+func TestAddRelationshipsMergeFuncCreatesMissingEndpoints(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	keepIncoming := func(existing, incoming map[string]interface{}) map[string]interface{} { return incoming }
+
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("ghost", "Person"), "KNOWS"),
+	}
+	err := n4j.AddRelationships(context.Background(), rels,
+		graphs.WithCreateMissingEndpoints(true), graphs.WithMergePropertiesFunc(keepIncoming))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) < 2 || !strings.Contains(driver.queries[0].query, "ON CREATE SET n:`Person`") {
+		t.Fatalf("Expected missing endpoints to be created first, got %d queries", len(driver.queries))
+	}
+	if last := driver.queries[len(driver.queries)-1].query; !strings.Contains(last, "MERGE (s)-[r:`KNOWS`]->(t)") {
+		t.Errorf("Expected the relationship to be merged after the endpoints, got %s", last)
+	}
+}
+
+func TestAddRelationshipsMergeFuncReportsMissingEndpoint(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "MERGE") {
+			return []*neo4j.Record{newRecord("source", "alice", "target", "ghost")}, nil
+		}
+		return nil, nil
+	}
+	keepIncoming := func(existing, incoming map[string]interface{}) map[string]interface{} { return incoming }
+
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("ghost", "Person"), "KNOWS"),
+	}
+	err := n4j.AddRelationships(context.Background(), rels, graphs.WithMergePropertiesFunc(keepIncoming))
+	if !errors.Is(err, ErrEndpointNotFound) || !strings.Contains(err.Error(), "ghost") {
+		t.Fatalf("Expected ErrEndpointNotFound naming ghost, got %v", err)
+	}
+}
+
+func TestAddRelationshipsCreateMissingEndpointsReportsPullErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.pullErr = func(query string) error {
+		if strings.Contains(query, "ON CREATE") {
+			return errors.New("constraint violated")
+		}
+		return nil
+	}
+
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("ghost", "Person"), "KNOWS"),
+	}
+	err := n4j.AddRelationships(context.Background(), rels, graphs.WithCreateMissingEndpoints(true))
+	if err == nil || !strings.Contains(err.Error(), "missing Person endpoints") {
+		t.Fatalf("Expected the endpoint creation failure to be reported, got %v", err)
+	}
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected no relationships to be written after the failure, got %d queries", len(driver.queries))
+	}
+}
+
+func TestAddRelationshipsCreateMissingEndpointsQuotedLabel(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithBaseEntityLabel(true))
+
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("pm", "Product Manager"), graphs.Node{ID: "acme"}, "WORKS_AT"),
+	}
+	if err := n4j.AddRelationships(context.Background(), rels, graphs.WithCreateMissingEndpoints(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if q := driver.queries[0].query; !strings.Contains(q, "ON CREATE SET n:`Product Manager`:`"+BASE_ENTITY_LABEL+"`, n += node.properties") {
+		t.Errorf("Expected the endpoint to be labeled with the quoted type, got %s", q)
+	}
+	if q := driver.queries[1].query; !strings.Contains(q, "ON CREATE SET n:`"+BASE_ENTITY_LABEL+"`, n += node.properties") {
+		t.Errorf("Expected an untyped endpoint to only get the base label, got %s", q)
+	}
+}
+
+func TestAddRelationshipsMissingEndpointWithoutCreate(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("source", "alice", "target", "ghost")}, nil
+	}
+
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("ghost", "Person"), "KNOWS"),
+	}
+	err := n4j.AddRelationships(context.Background(), rels, graphs.WithCreateMissingEndpoints(false))
+	if !errors.Is(err, ErrEndpointNotFound) || !strings.Contains(err.Error(), "ghost") {
+		t.Fatalf("Expected ErrEndpointNotFound naming ghost, got %v", err)
+	}
+	if len(driver.queries) != 1 || strings.Contains(driver.queries[0].query, "ON CREATE") {
+		t.Errorf("Expected no endpoints to be created, got %d queries", len(driver.queries))
+	}
+}
+
+func TestAddRelationshipsDropSelfLoops(t *testing.T) {
+	alice := graphs.NewNode("alice", "Person")
+	rels := []graphs.Relationship{
+		graphs.NewRelationship(alice, alice, "KNOWS"),
+		graphs.NewRelationship(alice, graphs.NewNode("bob", "Person"), "KNOWS"),
+	}
+
+	tests := []struct {
+		name     string
+		options  []graphs.Option
+		expected int
+	}{
+		{"kept by default", nil, 2},
+		{"dropped when enabled", []graphs.Option{graphs.WithDropSelfLoops(true)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n4j, driver := newFakeNeo4j()
+			if err := n4j.AddRelationships(context.Background(), rels, tt.options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			relData := driver.queries[0].params["relationships"].([]map[string]interface{})
+			if len(relData) != tt.expected {
+				t.Fatalf("Expected %d relationships, got %d", tt.expected, len(relData))
+			}
+			if tt.expected == 1 && relData[0]["target"] != "bob" {
+				t.Errorf("Expected the self-loop to be dropped, got %v", relData[0])
+			}
+		})
+	}
+}
+
+// recordingLogger records the messages logged by the store
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestDropSelfLoopsLogsCount(t *testing.T) {
+	alice := graphs.NewNode("alice", "Person")
+	bob := graphs.NewNode("bob", "Person")
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddNode(alice)
+	doc.AddNode(bob)
+	doc.AddRelationship(graphs.NewRelationship(alice, alice, "KNOWS"))
+	doc.AddRelationship(graphs.NewRelationship(bob, bob, "KNOWS"))
+	doc.AddRelationship(graphs.NewRelationship(alice, bob, "KNOWS"))
+
+	imports := map[string]func(n4j *Neo4j) error{
+		"AddRelationships": func(n4j *Neo4j) error {
+			return n4j.AddRelationships(context.Background(), doc.Relationships, graphs.WithDropSelfLoops(true))
+		},
+		"AddGraphDocument": func(n4j *Neo4j) error {
+			return n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}, graphs.WithDropSelfLoops(true))
+		},
+		"AddGraphDocumentWithTransaction": func(n4j *Neo4j) error {
+			return n4j.TransactionManager().AddGraphDocumentWithTransaction(context.Background(), []graphs.GraphDocument{doc}, graphs.WithDropSelfLoops(true))
+		},
+	}
+	for name, add := range imports {
+		t.Run(name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			n4j, _ := newFakeNeo4j(WithLogger(logger))
+			if err := add(n4j); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(logger.messages) != 1 || !strings.Contains(logger.messages[0], "dropped 2 self-loop") {
+				t.Errorf("Expected the dropped count to be logged once, got %q", logger.messages)
+			}
+		})
+	}
+	if len(doc.Relationships) != 3 {
+		t.Errorf("Expected the caller's document to be left intact, got %d relationships", len(doc.Relationships))
+	}
+}
+
+func TestAddRelationshipsDropSelfLoopsOnly(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	alice := graphs.NewNode("alice", "Person")
+	rels := []graphs.Relationship{graphs.NewRelationship(alice, alice, "KNOWS")}
+	if err := n4j.AddRelationships(context.Background(), rels, graphs.WithDropSelfLoops(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries when every relationship is a self-loop, got %d", len(driver.queries))
+	}
+}
+
+func TestAddGraphDocumentDropSelfLoops(t *testing.T) {
+	alice := graphs.NewNode("alice", "Person")
+	doc := graphs.NewGraphDocument(schema.Document{})
+	doc.AddRelationship(graphs.NewRelationship(alice, alice, "KNOWS"))
+	doc.AddRelationship(graphs.NewRelationship(alice, graphs.NewNode("bob", "Person"), "KNOWS"))
+
+	tests := []struct {
+		name     string
+		options  []graphs.Option
+		expected int
+	}{
+		{"kept by default", nil, 2},
+		{"dropped when enabled", []graphs.Option{graphs.WithDropSelfLoops(true)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n4j, driver := newFakeNeo4j()
+			if err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}, tt.options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			relData := driver.queries[len(driver.queries)-1].params["relationships"].([]map[string]interface{})
+			if len(relData) != tt.expected {
+				t.Errorf("Expected %d relationships, got %d", tt.expected, len(relData))
+			}
+		})
+	}
+}
+
+func TestAddGraphDocumentWritesAllLabels(t *testing.T) {
+	for _, baseEntityLabel := range []bool{false, true} {
+		n4j, driver := newFakeNeo4j(WithBaseEntityLabel(baseEntityLabel))
+
+		alice := graphs.NewNode("alice", "Person")
+		alice.Labels = []string{"Person", "Employee", BASE_ENTITY_LABEL}
+		doc := graphs.NewGraphDocument(schema.Document{})
+		doc.AddNode(alice)
+		doc.AddNode(graphs.NewNode("acme", "Company"))
+		if err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		q := driver.queries[len(driver.queries)-1]
+		if !strings.Contains(q.query, "CALL apoc.create.addLabels(") || !strings.Contains(q.query, ", node.labels) YIELD node AS n") {
+			t.Errorf("Expected every label to be added, got %s", q.query)
+		}
+		nodeData := q.params["nodes"].([]map[string]interface{})
+		if !reflect.DeepEqual(nodeData[0]["labels"], []string{"Person", "Employee"}) {
+			t.Errorf("Expected the type and extra labels without the base label, got %v", nodeData[0]["labels"])
+		}
+		if !reflect.DeepEqual(nodeData[1]["labels"], []string{"Company"}) {
+			t.Errorf("Expected the type as only label, got %v", nodeData[1]["labels"])
+		}
+	}
+}
+
+func TestAddNodesWritesExtraLabels(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	alice := graphs.NewNode("alice", "Person")
+	alice.Labels = []string{"Employee"}
+	nodes := []graphs.Node{alice, graphs.NewNode("acme", "Company")}
+	if err := n4j.AddNodes(context.Background(), nodes); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(driver.queries))
+	}
+	if q := driver.queries[0]; !strings.HasSuffix(q.query, extraLabelsClause) {
+		t.Errorf("Expected the extra labels to be added, got %s", q.query)
+	} else if labels := q.params["nodes"].([]map[string]interface{})[0]["labels"]; !reflect.DeepEqual(labels, []string{"Person", "Employee"}) {
+		t.Errorf("Expected labels Person and Employee, got %v", labels)
+	}
+	if q := driver.queries[1].query; strings.Contains(q, "apoc") {
+		t.Errorf("Expected nodes without extra labels not to require APOC, got %s", q)
+	}
+}
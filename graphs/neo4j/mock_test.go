@@ -0,0 +1,303 @@
+package neo4j
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// recordedQuery captures a query issued against the fake driver
+type recordedQuery struct {
+	query    string
+	params   map[string]interface{}
+	database string
+	inTx     bool
+	// deadline is the deadline of the query context, zero if it has none
+	deadline time.Time
+}
+
+// fakeDriver is an in-process stand-in for neo4j.DriverWithContext that records
+// every query it receives and answers with the records produced by respond.
+type fakeDriver struct {
+	neo4j.DriverWithContext
+
+	respond  func(query string, params map[string]interface{}) ([]*neo4j.Record, error)
+	queries  []recordedQuery
+	sessions []neo4j.SessionConfig
+
+	commits   int
+	rollbacks int
+
+	closedSessions int
+
+	// counters, when set, reports the write counters of each query
+	counters func(query string) fakeCounters
+
+	// pullErr, when set, returns the error raised while the records of a query are
+	// pulled, after Run itself succeeded
+	pullErr func(query string) error
+
+	mu              sync.Mutex
+	connectivityErr error
+
+	// unreachable makes VerifyConnectivity block until its context is done
+	unreachable bool
+	// closed records whether Close was called
+	closed bool
+}
+
+// newFakeNeo4j returns a Neo4j instance wired to a fake driver
+func newFakeNeo4j(opts ...Option) (*Neo4j, *fakeDriver) {
+	options := &options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	applyDefaults(options)
+
+	driver := &fakeDriver{}
+	n4j := &Neo4j{
+		driver:           driver,
+		uri:              options.uri,
+		database:         options.database,
+		sanitize:         options.sanitize,
+		sanitizeStrings:  options.sanitizeStrings,
+		enhancedSchema:   options.enhancedSchema,
+		baseEntityLabel:  options.baseEntityLabel,
+		timeout:          options.timeout,
+		connectTimeout:   options.connectTimeout,
+		userAgent:        options.userAgent,
+		config:           options.config,
+		structuredSchema: make(map[string]interface{}),
+		databaseSchemas:  make(map[string]string),
+
+		schemaSampleLimit:     options.schemaSampleLimit,
+		exhaustiveSearchLimit: options.exhaustiveSearchLimit,
+		authTokenProvider:     options.authTokenProvider,
+		defaultParams:         options.defaultParams,
+		retryAttempts:         options.retryAttempts,
+		retryBaseDelay:        options.retryBaseDelay,
+		retryPredicate:        options.retryPredicate,
+		timezone:              options.timezone,
+		boltLogger:            options.boltLogger,
+		logger:                options.logger,
+		nodeTypeSelector:      options.nodeTypeSelector,
+		serverSideSanitize:    options.serverSideSanitize,
+		healthCheckInterval:   options.healthCheckInterval,
+		onUnhealthy:           options.onUnhealthy,
+	}
+	n4j.txManager = newTransactionManager(n4j)
+	if options.queryCacheSize > 0 {
+		n4j.queryCache = newQueryCache(options.queryCacheSize, options.queryCacheTTL)
+	}
+	return n4j, driver
+}
+
+// newRecord builds a record from alternating key/value pairs
+func newRecord(kv ...interface{}) *neo4j.Record {
+	record := &neo4j.Record{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		record.Keys = append(record.Keys, kv[i].(string))
+		record.Values = append(record.Values, kv[i+1])
+	}
+	return record
+}
+
+func (d *fakeDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+	d.sessions = append(d.sessions, config)
+	return &fakeSession{driver: d, database: config.DatabaseName}
+}
+
+func (d *fakeDriver) VerifyConnectivity(ctx context.Context) error {
+	if d.unreachable {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.connectivityErr
+}
+
+// setConnectivityErr sets the error returned by VerifyConnectivity
+func (d *fakeDriver) setConnectivityErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connectivityErr = err
+}
+
+func (d *fakeDriver) Close(ctx context.Context) error {
+	d.closed = true
+	return nil
+}
+
+func (d *fakeDriver) run(ctx context.Context, query string, params map[string]interface{}, database string, inTx bool) (neo4j.ResultWithContext, error) {
+	deadline, _ := ctx.Deadline()
+	d.queries = append(d.queries, recordedQuery{query: query, params: params, database: database, inTx: inTx, deadline: deadline})
+	var pullErr error
+	if d.pullErr != nil {
+		pullErr = d.pullErr(query)
+	}
+	if d.respond == nil {
+		return &fakeResult{summary: d.summarize(query), err: pullErr}, nil
+	}
+	records, err := d.respond(query, params)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeResult{records: records, summary: d.summarize(query), err: pullErr}, nil
+}
+
+// summarize returns the result summary of a query
+func (d *fakeDriver) summarize(query string) neo4j.ResultSummary {
+	if d.counters == nil {
+		return nil
+	}
+	return &fakeSummary{counters: d.counters(query)}
+}
+
+// fakeSession records queries on behalf of its driver
+type fakeSession struct {
+	neo4j.SessionWithContext
+
+	driver   *fakeDriver
+	database string
+}
+
+func (s *fakeSession) Run(ctx context.Context, query string, params map[string]interface{}, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ResultWithContext, error) {
+	return s.driver.run(ctx, query, params, s.database, false)
+}
+
+func (s *fakeSession) ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.execute(work)
+}
+
+func (s *fakeSession) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	return s.execute(work)
+}
+
+func (s *fakeSession) execute(work neo4j.ManagedTransactionWork) (interface{}, error) {
+	result, err := work(&fakeTransaction{session: s})
+	if err != nil {
+		s.driver.rollbacks++
+		return nil, err
+	}
+	s.driver.commits++
+	return result, nil
+}
+
+func (s *fakeSession) BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ExplicitTransaction, error) {
+	return &fakeTransaction{session: s}, nil
+}
+
+func (s *fakeSession) Close(ctx context.Context) error {
+	s.driver.closedSessions++
+	return nil
+}
+
+// fakeTransaction serves as both a managed and an explicit transaction
+type fakeTransaction struct {
+	neo4j.ExplicitTransaction
+
+	session *fakeSession
+}
+
+func (t *fakeTransaction) Run(ctx context.Context, query string, params map[string]interface{}) (neo4j.ResultWithContext, error) {
+	return t.session.driver.run(ctx, query, params, t.session.database, true)
+}
+
+func (t *fakeTransaction) Commit(ctx context.Context) error {
+	t.session.driver.commits++
+	return nil
+}
+
+func (t *fakeTransaction) Rollback(ctx context.Context) error {
+	t.session.driver.rollbacks++
+	return nil
+}
+
+func (t *fakeTransaction) Close(ctx context.Context) error {
+	return nil
+}
+
+// fakeResult iterates over a fixed set of records
+type fakeResult struct {
+	neo4j.ResultWithContext
+
+	records []*neo4j.Record
+	current *neo4j.Record
+	summary neo4j.ResultSummary
+	// err is raised when the records are pulled
+	err error
+}
+
+func (r *fakeResult) Next(ctx context.Context) bool {
+	if len(r.records) == 0 || r.err != nil {
+		r.current = nil
+		return false
+	}
+	r.current, r.records = r.records[0], r.records[1:]
+	return true
+}
+
+func (r *fakeResult) Record() *neo4j.Record {
+	return r.current
+}
+
+func (r *fakeResult) Err() error {
+	return r.err
+}
+
+func (r *fakeResult) Collect(ctx context.Context) ([]*neo4j.Record, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	records := r.records
+	r.records = nil
+	return records, nil
+}
+
+func (r *fakeResult) Single(ctx context.Context) (*neo4j.Record, error) {
+	if !r.Next(ctx) {
+		return nil, errNoRecords
+	}
+	return r.current, nil
+}
+
+func (r *fakeResult) Consume(ctx context.Context) (neo4j.ResultSummary, error) {
+	r.records = nil
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.summary, nil
+}
+
+// fakeSummary reports fixed write counters
+type fakeSummary struct {
+	neo4j.ResultSummary
+
+	counters fakeCounters
+}
+
+func (s *fakeSummary) Counters() neo4j.Counters {
+	return s.counters
+}
+
+// fakeCounters implements the write counters used by the store
+type fakeCounters struct {
+	neo4j.Counters
+
+	nodesCreated         int
+	relationshipsCreated int
+	propertiesSet        int
+}
+
+func (c fakeCounters) NodesCreated() int         { return c.nodesCreated }
+func (c fakeCounters) NodesDeleted() int         { return 0 }
+func (c fakeCounters) RelationshipsCreated() int { return c.relationshipsCreated }
+func (c fakeCounters) RelationshipsDeleted() int { return 0 }
+func (c fakeCounters) PropertiesSet() int        { return c.propertiesSet }
+func (c fakeCounters) LabelsAdded() int          { return c.nodesCreated }
+func (c fakeCounters) LabelsRemoved() int        { return 0 }
+
+var errNoRecords = &TestError{"result contains no records"}
@@ -1,11 +1,13 @@
 package neo4j
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 )
 
 const (
@@ -13,6 +15,8 @@ const (
 	LIST_LIMIT = 128
 	// EXHAUSTIVE_SEARCH_LIMIT determines when to do exhaustive vs sampling search
 	EXHAUSTIVE_SEARCH_LIMIT = 10000
+	// SCHEMA_SAMPLE_LIMIT is the number of entities sampled for non-exhaustive schema search
+	SCHEMA_SAMPLE_LIMIT = 5
 	// DISTINCT_VALUE_LIMIT is the threshold for returning all vs sample values
 	DISTINCT_VALUE_LIMIT = 10
 	// BASE_ENTITY_LABEL is the secondary label applied to all nodes for performance
@@ -25,6 +29,18 @@ var (
 	ErrConnectionFailed     = fmt.Errorf("failed to connect to neo4j")
 	ErrQueryExecution       = fmt.Errorf("failed to execute query")
 	ErrAPOCNotAvailable     = fmt.Errorf("APOC procedures not available")
+	ErrAPOCNotAllowed       = fmt.Errorf("APOC procedures not allowed")
+	ErrInvalidPredicate     = fmt.Errorf("invalid predicate")
+	ErrInvalidRelType       = fmt.Errorf("invalid relationship type")
+	ErrInvalidLabel         = fmt.Errorf("invalid label")
+	ErrInvalidPropertyKey   = fmt.Errorf("invalid property key")
+	ErrWriteQueryRejected   = fmt.Errorf("write query rejected")
+	ErrEndpointNotFound     = fmt.Errorf("relationship endpoint not found")
+	ErrInvalidVectorIndex   = fmt.Errorf("invalid vector index")
+	ErrInvalidFullTextIndex = fmt.Errorf("invalid full-text index")
+	ErrIndexNotFound        = fmt.Errorf("index not found")
+	ErrInvalidPagination    = fmt.Errorf("invalid pagination")
+	ErrNotConfirmed         = fmt.Errorf("operation not confirmed")
 )
 
 // Neo4j implements the graphs.GraphStore interface for Neo4j
@@ -38,14 +54,61 @@ type Neo4j struct {
 	password        string
 	database        string
 	sanitize        bool
+	sanitizeStrings bool
 	enhancedSchema  bool
 	baseEntityLabel bool
 	timeout         time.Duration
+	connectTimeout  time.Duration
+	userAgent       string
+
+	// Authentication token provider, used instead of username and password when set
+	authTokenProvider AuthTokenProvider
+
+	// Parameters merged into every query, caller parameters take precedence
+	defaultParams map[string]interface{}
+
+	// Zone temporal properties are normalized to before writing
+	timezone *time.Location
+
+	// Derives node types from labels when reading nodes
+	nodeTypeSelector NodeTypeSelector
+
+	// Whether node getters exclude large properties on the server
+	serverSideSanitize bool
+
+	// Logger for Bolt protocol messages, nil when disabled
+	boltLogger log.BoltLogger
+
+	// Logger for adjustments made to writes, nil when disabled
+	logger Logger
+
+	// Background health check, running while healthCheckCancel is set
+	healthCheckInterval time.Duration
+	onUnhealthy         func(error)
+	healthCheckCancel   context.CancelFunc
+	healthCheckDone     chan struct{}
+
+	// Retry configuration for import writes
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	retryPredicate RetryPredicate
+
+	// Enhanced schema sampling
+	schemaSampleLimit     int
+	exhaustiveSearchLimit int
 
 	// Schema cache
 	schemaMux        sync.RWMutex
 	schemaCache      string
 	structuredSchema map[string]interface{}
+	databaseSchemas  map[string]string
+
+	// Whether APOC is available for schema queries, valid once apocChecked is set
+	apocChecked   bool
+	apocAvailable bool
+
+	// Read query result cache, nil when disabled
+	queryCache *queryCache
 
 	// Transaction manager
 	txManager *TransactionManager
@@ -55,7 +118,7 @@ type Neo4j struct {
 }
 
 // newNeo4j creates a new Neo4j instance with the given configuration
-func newNeo4j(opts ...Option) (*Neo4j, error) {
+func newNeo4j(ctx context.Context, opts ...Option) (*Neo4j, error) {
 	options := &options{}
 
 	// Apply options
@@ -73,27 +136,51 @@ func newNeo4j(opts ...Option) (*Neo4j, error) {
 		password:         options.password,
 		database:         options.database,
 		sanitize:         options.sanitize,
+		sanitizeStrings:  options.sanitizeStrings,
 		enhancedSchema:   options.enhancedSchema,
 		baseEntityLabel:  options.baseEntityLabel,
 		timeout:          options.timeout,
+		connectTimeout:   options.connectTimeout,
+		userAgent:        options.userAgent,
 		config:           options.config,
 		structuredSchema: make(map[string]interface{}),
+		databaseSchemas:  make(map[string]string),
+
+		schemaSampleLimit:     options.schemaSampleLimit,
+		exhaustiveSearchLimit: options.exhaustiveSearchLimit,
+		authTokenProvider:     options.authTokenProvider,
+		defaultParams:         options.defaultParams,
+		retryAttempts:         options.retryAttempts,
+		retryBaseDelay:        options.retryBaseDelay,
+		retryPredicate:        options.retryPredicate,
+		timezone:              options.timezone,
+		boltLogger:            options.boltLogger,
+		logger:                options.logger,
+		nodeTypeSelector:      options.nodeTypeSelector,
+		serverSideSanitize:    options.serverSideSanitize,
+		healthCheckInterval:   options.healthCheckInterval,
+		onUnhealthy:           options.onUnhealthy,
 	}
 
 	// Initialize driver
-	if err := n4j.connect(); err != nil {
+	if err := n4j.connectWithRetry(ctx, options.connectAttempts, options.connectBackoff); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
 	}
 
 	// Initialize transaction manager
 	n4j.txManager = newTransactionManager(n4j)
+	if options.queryCacheSize > 0 {
+		n4j.queryCache = newQueryCache(options.queryCacheSize, options.queryCacheTTL)
+	}
+
+	n4j.startHealthCheck()
 
 	return n4j, nil
 }
 
 // NewNeo4j creates a new Neo4j graph store
 func NewNeo4j(opts ...Option) (*Neo4j, error) {
-	return newNeo4j(opts...)
+	return newNeo4j(context.Background(), opts...)
 }
 
 // TransactionManager returns the transaction manager for advanced transaction control
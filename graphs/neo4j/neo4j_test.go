@@ -1,6 +1,8 @@
 package neo4j
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	
 	"github.com/tmc/langchaingo/schema"
@@ -129,6 +131,51 @@ func TestAPOCErrorDetection(t *testing.T) {
 	}
 }
 
+func TestWrapAPOCError(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		want     error
+		notWant  error
+		guidance string
+	}{
+		{
+			name:     "not installed",
+			message:  "Neo.ClientError.Procedure.ProcedureNotFound: There is no procedure with the name `apoc.merge.node` registered for this database instance",
+			want:     ErrAPOCNotAvailable,
+			notWant:  ErrAPOCNotAllowed,
+			guidance: "APOC plugin is installed",
+		},
+		{
+			name:     "not allowed",
+			message:  "Neo.ClientError.Security.Forbidden: apoc.merge.node is unavailable because it is sandboxed and has dependencies outside of the sandbox",
+			want:     ErrAPOCNotAllowed,
+			notWant:  ErrAPOCNotAvailable,
+			guidance: "dbms.security.procedures.allowlist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapAPOCError(&TestError{tt.message})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+			if errors.Is(err, tt.notWant) {
+				t.Errorf("Did not expect %v, got %v", tt.notWant, err)
+			}
+			if !strings.Contains(err.Error(), tt.guidance) {
+				t.Errorf("Expected guidance %q in %q", tt.guidance, err.Error())
+			}
+		})
+	}
+
+	forbidden := &TestError{"Neo.ClientError.Security.Forbidden: Read operations are not allowed for user 'reader'"}
+	if err := wrapAPOCError(forbidden); err != forbidden {
+		t.Errorf("Expected unrelated forbidden error to be returned unchanged, got %v", err)
+	}
+}
+
 // TestError is a simple error implementation for testing
 type TestError struct {
 	message string
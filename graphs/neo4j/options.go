@@ -1,12 +1,16 @@
 package neo4j
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 )
 
 const (
@@ -21,8 +25,34 @@ const (
 	DefaultUsername = "neo4j"
 	DefaultPassword = "password"
 	DefaultDatabase = "neo4j"
+
+	// Version is the version of this library reported in the default user agent when
+	// the module version cannot be read from the build info, such as in its own tests.
+	// It must be bumped with every release.
+	Version = "0.1.0"
+
+	// modulePath is the module path looked up in the build info
+	modulePath = "github.com/0xDezzy/langchaingo-graphs"
 )
 
+// DefaultUserAgent identifies this library and its version to the Neo4j server
+var DefaultUserAgent = "langchaingo-graphs/" + moduleVersion()
+
+// moduleVersion returns the version of this module the binary was built with, falling
+// back to Version when the build info does not record one
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath && dep.Version != "" {
+			return strings.TrimPrefix(dep.Version, "v")
+		}
+	}
+	return Version
+}
+
 var (
 	ErrInvalidOptions = errors.New("invalid neo4j options")
 )
@@ -37,10 +67,41 @@ type options struct {
 	password        string
 	database        string
 	sanitize        bool
+	sanitizeStrings bool
 	enhancedSchema  bool
 	baseEntityLabel bool
 	timeout         time.Duration
+	connectTimeout  time.Duration
+	userAgent       string
 	config          neo4j.Config
+
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	retryPredicate RetryPredicate
+
+	connectAttempts int
+	connectBackoff  time.Duration
+
+	timezone *time.Location
+
+	boltLogger log.BoltLogger
+	logger     Logger
+
+	healthCheckInterval time.Duration
+	onUnhealthy         func(error)
+
+	queryCacheSize int
+	queryCacheTTL  time.Duration
+
+	nodeTypeSelector NodeTypeSelector
+
+	serverSideSanitize bool
+
+	authTokenProvider AuthTokenProvider
+	defaultParams     map[string]interface{}
+
+	schemaSampleLimit     int
+	exhaustiveSearchLimit int
 }
 
 // WithURI sets the Neo4j connection URI.
@@ -86,6 +147,29 @@ func WithPassword(password string) Option {
 	}
 }
 
+// AuthTokenProvider returns the current authentication token, such as a rotating bearer token.
+type AuthTokenProvider func(ctx context.Context) (neo4j.AuthToken, error)
+
+// WithAuthTokenProvider sets a provider used to obtain authentication tokens instead of
+// static credentials. The driver asks the provider for a new token whenever the server
+// rejects the current one, and every reconnect starts from a freshly provided token.
+func WithAuthTokenProvider(provider AuthTokenProvider) Option {
+	return func(o *options) {
+		o.authTokenProvider = provider
+	}
+}
+
+// WithDefaultParams sets parameters merged into the parameters of every query and import,
+// such as a tenant id used for scoping. Parameters passed by the caller win on conflict.
+func WithDefaultParams(params map[string]interface{}) Option {
+	return func(o *options) {
+		o.defaultParams = make(map[string]interface{}, len(params))
+		for k, v := range params {
+			o.defaultParams[k] = v
+		}
+	}
+}
+
 // WithDatabase sets the Neo4j database name.
 func WithDatabase(database string) Option {
 	return func(o *options) {
@@ -114,6 +198,100 @@ func WithConnectionAcquisitionTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithMaxTransactionRetryTime sets how long managed transactions, such as those run by
+// TransactionManager.WithTransaction, are retried by the driver on transient failures
+// before giving up. Zero keeps the driver default.
+func WithMaxTransactionRetryTime(d time.Duration) Option {
+	return func(o *options) {
+		o.config.MaxTransactionRetryTime = d
+	}
+}
+
+// WithConnectTimeout bounds the initial connection: the driver's socket connect
+// timeout and the connectivity check made when the store is created, so New
+// fails fast on an unreachable host.
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.connectTimeout = timeout
+	}
+}
+
+// WithConnectRetries makes New try the initial connection up to attempts times, waiting
+// backoff after the first failure and doubling the wait after each further one, so a
+// store can start alongside a Neo4j server that is still booting. Each attempt is bounded
+// by WithConnectTimeout, and NewWithContext stops waiting when its context is done.
+func WithConnectRetries(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.connectAttempts = attempts
+		o.connectBackoff = backoff
+	}
+}
+
+// WithUserAgent sets the user agent the driver reports to the server.
+// It appears in SHOW TRANSACTIONS and server logs. Without it, the UserAgent of
+// WithConfig is used if set, and DefaultUserAgent otherwise.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithBoltLogging sets a logger receiving every Bolt protocol message exchanged by sessions.
+// The output is verbose and intended for debugging, so no Bolt logger is set by default.
+func WithBoltLogging(logger log.BoltLogger) Option {
+	return func(o *options) {
+		o.boltLogger = logger
+	}
+}
+
+// Logger receives notices about adjustments the store makes to writes, such as dropped
+// self-loops or create-mode writes performed as upserts under WithRetry. A *log.Logger from the standard library satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithLogger sets the logger notified when the store adjusts a write. Nothing is logged
+// by default.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithHealthCheck pings the database every interval in the background and calls
+// onUnhealthy with the error whenever a ping fails, surfacing connection loss before
+// the next query. The health check stops when the store is closed.
+func WithHealthCheck(interval time.Duration, onUnhealthy func(error)) Option {
+	return func(o *options) {
+		o.healthCheckInterval = interval
+		o.onUnhealthy = onUnhealthy
+	}
+}
+
+// WithQueryCache caches up to size results of read queries run through Query for ttl.
+// Results are keyed by the query text and parameters. Write queries bypass the cache,
+// and any mutating operation on the store clears it. Cached results are shared between
+// callers and must not be modified. A zero ttl keeps results until evicted or invalidated.
+func WithQueryCache(size int, ttl time.Duration) Option {
+	return func(o *options) {
+		o.queryCacheSize = size
+		o.queryCacheTTL = ttl
+	}
+}
+
+// NodeTypeSelector derives a node type from the labels of a stored node.
+type NodeTypeSelector func(labels []string) string
+
+// WithNodeTypeSelector sets how the Type of nodes read from the database is derived from
+// their labels, for example preferring the most specific label of multi-label nodes.
+// The selector receives the labels without the base entity label. By default the first
+// label is used.
+func WithNodeTypeSelector(selector NodeTypeSelector) Option {
+	return func(o *options) {
+		o.nodeTypeSelector = selector
+	}
+}
+
 // WithConfig allows setting a custom Neo4j driver configuration.
 func WithConfig(config neo4j.Config) Option {
 	return func(o *options) {
@@ -129,6 +307,26 @@ func WithSanitize(sanitize bool) Option {
 	}
 }
 
+// WithServerSideSanitize enables or disables excluding large properties from nodes read by
+// the store's node getters on the server, instead of transferring them. A property is
+// excluded when the enhanced schema reports it as a list of at least LIST_LIMIT elements on
+// every sampled node, the lists WithSanitize would drop anyway. It has no effect until the
+// schema has been refreshed with enhanced schema enabled.
+func WithServerSideSanitize(enabled bool) Option {
+	return func(o *options) {
+		o.serverSideSanitize = enabled
+	}
+}
+
+// WithSanitizeStrings enables or disables cleaning of string values in query results.
+// When enabled, newlines and tabs become spaces and other control characters are removed,
+// so returned values can be embedded in LLM prompts safely.
+func WithSanitizeStrings(sanitize bool) Option {
+	return func(o *options) {
+		o.sanitizeStrings = sanitize
+	}
+}
+
 // WithEnhancedSchema enables enhanced schema generation with property value sampling.
 // When enabled, includes example values, min/max ranges, and distinct counts in schema.
 func WithEnhancedSchema(enhanced bool) Option {
@@ -137,6 +335,51 @@ func WithEnhancedSchema(enhanced bool) Option {
 	}
 }
 
+// WithSchemaSampleSize controls property sampling for enhanced schema generation.
+// sampleLimit is the number of entities sampled when the search is not exhaustive, and
+// labels or types with fewer than exhaustiveThreshold entities are searched exhaustively.
+// Non-positive values keep the defaults of SCHEMA_SAMPLE_LIMIT and EXHAUSTIVE_SEARCH_LIMIT.
+func WithSchemaSampleSize(sampleLimit, exhaustiveThreshold int) Option {
+	return func(o *options) {
+		o.schemaSampleLimit = sampleLimit
+		o.exhaustiveSearchLimit = exhaustiveThreshold
+	}
+}
+
+// WithRetry retries queries and import writes failing with transient Neo4j errors, such
+// as deadlocks, leader switches and lost connections, up to maxAttempts times in total,
+// doubling the delay between attempts starting from baseDelay. Other errors fail
+// immediately. Managed transactions are retried by the driver instead, see
+// WithMaxTransactionRetryTime. While retries are enabled, MergeModeCreate writes are performed
+// as MergeModeUpsert so that a retried write cannot duplicate data: existing nodes and
+// relationships are updated instead of duplicated. Every such upgrade is reported to the
+// WithLogger logger.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(o *options) {
+		o.retryAttempts = maxAttempts
+		o.retryBaseDelay = baseDelay
+	}
+}
+
+// WithRetryPredicate sets the function deciding which errors are retried and whether to keep
+// retrying, replacing the built-in transient error classifier. The attempt limit and base
+// delay of WithRetry still apply when set; without it, failed operations run at most 5
+// times with delays doubling from 50ms.
+func WithRetryPredicate(predicate func(err error, attempt int) bool) Option {
+	return func(o *options) {
+		o.retryPredicate = predicate
+	}
+}
+
+// WithTimezone sets the zone temporal properties are normalized to before being written.
+// time.Time values, including those inside lists, are converted to the zone so that
+// stored zoned datetimes compare and sort consistently. Nil leaves values unchanged.
+func WithTimezone(loc *time.Location) Option {
+	return func(o *options) {
+		o.timezone = loc
+	}
+}
+
 // WithTimeout sets the timeout for Neo4j queries.
 // Useful for terminating long-running queries. Zero value means no timeout.
 func WithTimeout(timeout time.Duration) Option {
@@ -155,7 +398,13 @@ func WithBaseEntityLabel(enable bool) Option {
 
 // New creates a new Neo4j GraphStore instance with the given options.
 func New(opts ...Option) (*Neo4j, error) {
-	return newNeo4j(opts...)
+	return newNeo4j(context.Background(), opts...)
+}
+
+// NewWithContext is like New, connecting under ctx so that retries configured with
+// WithConnectRetries can be abandoned.
+func NewWithContext(ctx context.Context, opts ...Option) (*Neo4j, error) {
+	return newNeo4j(ctx, opts...)
 }
 
 // getFromDictOrEnv gets a value from options, environment variable, or default value.
@@ -176,4 +425,10 @@ func applyDefaults(o *options) {
 	o.username = getFromDictOrEnv(o.username, Neo4jUsernameEnvVarName, DefaultUsername)
 	o.password = getFromDictOrEnv(o.password, Neo4jPasswordEnvVarName, DefaultPassword)
 	o.database = getFromDictOrEnv(o.database, Neo4jDatabaseEnvVarName, DefaultDatabase)
+	if o.schemaSampleLimit <= 0 {
+		o.schemaSampleLimit = SCHEMA_SAMPLE_LIMIT
+	}
+	if o.exhaustiveSearchLimit <= 0 {
+		o.exhaustiveSearchLimit = EXHAUSTIVE_SEARCH_LIMIT
+	}
 }
@@ -0,0 +1,156 @@
+package neo4j
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// GetNodesByTypePaged retrieves one page of the nodes of a type, ordered by node id.
+// Pass an empty cursor for the first page and the returned cursor for the next one;
+// an empty returned cursor means there are no more pages. Unlike the Offset option of
+// GetNodesByType, each page is located through the id ordering instead of skipping
+// the preceding nodes, so deep pages are as fast as the first. Nodes without a string
+// id are not returned.
+func (n *Neo4j) GetNodesByTypePaged(ctx context.Context, nodeType string, cursor string, limit int, options ...graphs.Option) ([]graphs.Node, string, error) {
+	if n.driver == nil {
+		return nil, "", ErrDriverNotInitialized
+	}
+
+	if err := validateLabel(nodeType); err != nil {
+		return nil, "", err
+	}
+	after, err := decodeCursor(cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	// Fetch one extra node to tell whether another page follows
+	query := fmt.Sprintf(`
+		MATCH (n:%s)
+		WHERE n.id > $after
+		RETURN %s
+		ORDER BY n.id
+		LIMIT $limit
+	`, nodeType, n.nodeReturn("n"))
+	params := map[string]interface{}{
+		"after": after,
+		"limit": limit + 1,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get nodes by type %s: %w", nodeType, err)
+	}
+
+	var nodes []graphs.Node
+	for result.Next(ctx) {
+		if node, ok := asNode(result.Record().Values[0]); ok {
+			nodes = append(nodes, *n.convertNeo4jNodeToGraphNode(node, opts))
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to get nodes by type %s: %w", nodeType, err)
+	}
+
+	if len(nodes) <= limit {
+		return nodes, "", nil
+	}
+	nodes = nodes[:limit]
+	return nodes, encodeCursor(nodes[limit-1].ID), nil
+}
+
+// GetRelationshipsByTypePaged retrieves one page of the relationships of a type, ordered
+// by their element id, with the cursor semantics of GetNodesByTypePaged.
+func (n *Neo4j) GetRelationshipsByTypePaged(ctx context.Context, relType string, cursor string, limit int, options ...graphs.Option) ([]graphs.Relationship, string, error) {
+	if n.driver == nil {
+		return nil, "", ErrDriverNotInitialized
+	}
+
+	if err := validateRelType(relType); err != nil {
+		return nil, "", err
+	}
+	after, err := decodeCursor(cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf(`
+		MATCH (s)-[r:%s]->(t)
+		WHERE elementId(r) > $after
+		RETURN s, r, t, elementId(r) AS cursor
+		ORDER BY cursor
+		LIMIT $limit
+	`, relType)
+	params := map[string]interface{}{
+		"after": after,
+		"limit": limit + 1,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get relationships by type %s: %w", relType, err)
+	}
+
+	var relationships []graphs.Relationship
+	var last string
+	for result.Next(ctx) {
+		record := result.Record()
+		rel, ok := n.convertRecordToRelationship(record, opts)
+		if !ok {
+			continue
+		}
+		if len(relationships) < limit {
+			value, _ := record.Get("cursor")
+			last, _ = value.(string)
+		}
+		relationships = append(relationships, rel)
+	}
+	if err := result.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to get relationships by type %s: %w", relType, err)
+	}
+
+	if len(relationships) <= limit {
+		return relationships, "", nil
+	}
+	return relationships[:limit], encodeCursor(last), nil
+}
+
+// encodeCursor turns the key of the last returned entity into an opaque page cursor
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// decodeCursor returns the key encoded in a page cursor, validating the page size
+func decodeCursor(cursor string, limit int) (string, error) {
+	if limit <= 0 {
+		return "", fmt.Errorf("%w: limit must be positive, got %d", ErrInvalidPagination, limit)
+	}
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPagination, err)
+	}
+	return string(key), nil
+}
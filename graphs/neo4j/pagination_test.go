@@ -0,0 +1,142 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// pagedNodes answers paged node queries from ids, honoring $after and $limit
+func pagedNodes(ids []string) func(string, map[string]interface{}) ([]*neo4j.Record, error) {
+	return func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		after, _ := params["after"].(string)
+		limit, _ := params["limit"].(int)
+		var records []*neo4j.Record
+		for _, id := range ids {
+			if id > after && len(records) < limit {
+				node := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": id}}
+				records = append(records, newRecord("n", node))
+			}
+		}
+		return records, nil
+	}
+}
+
+func TestGetNodesByTypePaged(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = pagedNodes([]string{"a", "b", "c", "d", "e"})
+	ctx := context.Background()
+
+	var pages [][]string
+	cursor := ""
+	for {
+		nodes, next, err := n4j.GetNodesByTypePaged(ctx, "Person", cursor, 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var ids []string
+		for _, node := range nodes {
+			ids = append(ids, node.ID)
+		}
+		pages = append(pages, ids)
+		if next == "" {
+			break
+		}
+		if next == "c" || next == "d" {
+			t.Errorf("Expected an opaque cursor, got %q", next)
+		}
+		cursor = next
+	}
+
+	if got := fmt.Sprint(pages); got != "[[a b] [c d] [e]]" {
+		t.Errorf("Unexpected pages %s", got)
+	}
+
+	q := driver.queries[1]
+	if !strings.Contains(q.query, "MATCH (n:Person)") || !strings.Contains(q.query, "WHERE n.id > $after") ||
+		!strings.Contains(q.query, "ORDER BY n.id") || strings.Contains(q.query, "SKIP") {
+		t.Errorf("Expected a keyset query, got %s", q.query)
+	}
+	if q.params["after"] != "b" || q.params["limit"] != 3 {
+		t.Errorf("Expected to continue after b, got %v", q.params)
+	}
+}
+
+func TestGetNodesByTypePagedExactLastPage(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = pagedNodes([]string{"a", "b"})
+
+	nodes, next, err := n4j.GetNodesByTypePaged(context.Background(), "Person", "", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nodes) != 2 || next != "" {
+		t.Errorf("Expected a full last page without a cursor, got %d nodes and cursor %q", len(nodes), next)
+	}
+
+	driver.respond = pagedNodes(nil)
+	nodes, next, err = n4j.GetNodesByTypePaged(context.Background(), "Person", "", 2)
+	if err != nil || len(nodes) != 0 || next != "" {
+		t.Errorf("Expected an empty page without a cursor, got %v, %q, %v", nodes, next, err)
+	}
+}
+
+func TestGetRelationshipsByTypePaged(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	alice := neo4j.Node{Labels: []string{"Person"}, Props: map[string]interface{}{"id": "alice"}}
+	acme := neo4j.Node{Labels: []string{"Company"}, Props: map[string]interface{}{"id": "acme"}}
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		var records []*neo4j.Record
+		for _, id := range []string{"5:r1", "5:r2", "5:r3"} {
+			if id > params["after"].(string) && len(records) < params["limit"].(int) {
+				records = append(records, newRecord("s", alice, "r", neo4j.Relationship{Type: "WORKS_AT"}, "t", acme, "cursor", id))
+			}
+		}
+		return records, nil
+	}
+	ctx := context.Background()
+
+	first, next, err := n4j.GetRelationshipsByTypePaged(ctx, "WORKS_AT", "", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(first) != 2 || next == "" {
+		t.Fatalf("Expected a full first page with a cursor, got %d and %q", len(first), next)
+	}
+	second, next, err := n4j.GetRelationshipsByTypePaged(ctx, "WORKS_AT", next, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(second) != 1 || next != "" {
+		t.Errorf("Expected a last page of 1 without a cursor, got %d and %q", len(second), next)
+	}
+
+	if q := driver.queries[1]; q.params["after"] != "5:r2" || !strings.Contains(q.query, "WHERE elementId(r) > $after") {
+		t.Errorf("Expected to continue after 5:r2, got %v in %s", q.params, q.query)
+	}
+}
+
+func TestPagedRejectsInvalidInput(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	if _, _, err := n4j.GetNodesByTypePaged(ctx, "Person", "", 0); !errors.Is(err, ErrInvalidPagination) {
+		t.Errorf("Expected ErrInvalidPagination for a zero limit, got %v", err)
+	}
+	if _, _, err := n4j.GetNodesByTypePaged(ctx, "Person", "not a cursor!", 10); !errors.Is(err, ErrInvalidPagination) {
+		t.Errorf("Expected ErrInvalidPagination for a malformed cursor, got %v", err)
+	}
+	if _, _, err := n4j.GetNodesByTypePaged(ctx, "Person`) DETACH DELETE n //", "", 10); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Expected ErrInvalidLabel, got %v", err)
+	}
+	if _, _, err := n4j.GetRelationshipsByTypePaged(ctx, "KNOWS]->() DELETE s //", "", 10); !errors.Is(err, ErrInvalidRelType) {
+		t.Errorf("Expected ErrInvalidRelType, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected no queries, got %d", len(driver.queries))
+	}
+}
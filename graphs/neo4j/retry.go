@@ -0,0 +1,112 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// Limits applied when WithRetryPredicate is used without WithRetry, so that a predicate
+// returning true on a persistent error cannot retry forever without backoff
+const (
+	defaultPredicateRetryAttempts  = 5
+	defaultPredicateRetryBaseDelay = 50 * time.Millisecond
+)
+
+// RetryPredicate decides whether a failed operation is retried, given the error and
+// the number of attempts made so far.
+type RetryPredicate func(err error, attempt int) bool
+
+// retryableErrorCodes lists error codes outside Neo.TransientError that are safe to retry,
+// raised while a cluster elects a new leader or routing information is stale
+var retryableErrorCodes = map[string]bool{
+	"Neo.ClientError.Cluster.NotALeader":                  true,
+	"Neo.ClientError.General.ForbiddenOnReadOnlyDatabase": true,
+}
+
+// nonRetryableTransientCodes lists transient error codes caused by the client giving up,
+// which fail again when retried
+var nonRetryableTransientCodes = map[string]bool{
+	"Neo.TransientError.Transaction.Terminated":        true,
+	"Neo.TransientError.Transaction.LockClientStopped": true,
+}
+
+// isRetryableError reports whether err is a transient Neo4j error worth retrying, such
+// as a deadlock, a leader switch or a lost connection
+func isRetryableError(err error) bool {
+	var neo4jErr *neo4j.Neo4jError
+	if errors.As(err, &neo4jErr) {
+		if strings.HasPrefix(neo4jErr.Code, "Neo.TransientError.") {
+			return !nonRetryableTransientCodes[neo4jErr.Code]
+		}
+		return retryableErrorCodes[neo4jErr.Code]
+	}
+	return neo4j.IsRetryable(err)
+}
+
+// retriesEnabled reports whether failed writes may be run more than once
+func (n *Neo4j) retriesEnabled() bool {
+	return n.retryAttempts > 1 || n.retryPredicate != nil
+}
+
+// retryLimits returns the attempt limit and base delay of retries, falling back to
+// defaults when a retry predicate is set without WithRetry
+func (n *Neo4j) retryLimits() (int, time.Duration) {
+	if n.retryAttempts <= 0 && n.retryPredicate != nil {
+		delay := n.retryBaseDelay
+		if delay <= 0 {
+			delay = defaultPredicateRetryBaseDelay
+		}
+		return defaultPredicateRetryAttempts, delay
+	}
+	return n.retryAttempts, n.retryBaseDelay
+}
+
+// shouldRetry reports whether to retry after the given failed attempt. The attempt
+// limit always applies. Within it, the retry predicate decides if set, and otherwise
+// transient Neo4j errors are retried.
+func (n *Neo4j) shouldRetry(err error, attempt int) bool {
+	maxAttempts, _ := n.retryLimits()
+	if attempt >= maxAttempts {
+		return false
+	}
+	if n.retryPredicate != nil {
+		return n.retryPredicate(err, attempt)
+	}
+	return isRetryableError(err)
+}
+
+// withRetry runs fn, retrying transient errors with exponential backoff.
+// The context is honored between attempts.
+func (n *Neo4j) withRetry(ctx context.Context, fn func() error) error {
+	_, delay := n.retryLimits()
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !n.shouldRetry(err, attempt) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// idempotentMergeMode returns the merge mode to use for a write. Create mode is
+// upgraded to upsert while retries are enabled, as a retried CREATE duplicates data,
+// and the upgrade is reported to the logger.
+func (n *Neo4j) idempotentMergeMode(mode graphs.MergeMode) graphs.MergeMode {
+	if mode == graphs.MergeModeCreate && n.retriesEnabled() {
+		n.logf("neo4j: retries are enabled, performing create-mode write as upsert to stay idempotent")
+		return graphs.MergeModeUpsert
+	}
+	return mode
+}
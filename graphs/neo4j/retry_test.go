@@ -0,0 +1,365 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/tmc/langchaingo/schema"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// failingResponder fails the first n queries with a transient error
+func failingResponder(n int) func(string, map[string]interface{}) ([]*neo4j.Record, error) {
+	calls := 0
+	return func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		calls++
+		if calls <= n {
+			return nil, &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected", Msg: "deadlock"}
+		}
+		return nil, nil
+	}
+}
+
+// failingPull fails pulling the records of the first n queries with a transient error
+func failingPull(n int) func(string) error {
+	calls := 0
+	return func(query string) error {
+		calls++
+		if calls <= n {
+			return &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected", Msg: "deadlock"}
+		}
+		return nil
+	}
+}
+
+func TestAddNodesRetriesPullErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(3, time.Millisecond))
+	driver.pullErr = failingPull(1)
+
+	if err := n4j.AddNodes(context.Background(), []graphs.Node{graphs.NewNode("alice", "Person")}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected the batch failing at pull time to be retried once, got %d queries", len(driver.queries))
+	}
+}
+
+func TestAddNodesReportsPullErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.pullErr = func(string) error {
+		return &neo4j.Neo4jError{Code: "Neo.ClientError.Schema.ConstraintValidationFailed", Msg: "already exists"}
+	}
+
+	err := n4j.AddNodes(context.Background(), []graphs.Node{graphs.NewNode("alice", "Person")})
+	var neo4jErr *neo4j.Neo4jError
+	if !errors.As(err, &neo4jErr) || neo4jErr.Code != "Neo.ClientError.Schema.ConstraintValidationFailed" {
+		t.Errorf("Expected the constraint violation to be reported, got %v", err)
+	}
+}
+
+func TestCreateModeUpgradedToUpsertUnderRetry(t *testing.T) {
+	logger := &recordingLogger{}
+	n4j, driver := newFakeNeo4j(WithRetry(3, time.Millisecond), WithLogger(logger))
+	driver.respond = failingResponder(1)
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	err := n4j.AddNodes(context.Background(), nodes, graphs.WithMergeMode(graphs.MergeModeCreate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 2 {
+		t.Fatalf("Expected the failed write to be retried once, got %d queries", len(driver.queries))
+	}
+	for _, q := range driver.queries {
+		if strings.Contains(q.query, "CREATE") || !strings.Contains(q.query, "MERGE") {
+			t.Errorf("Expected an idempotent MERGE write, got %s", q.query)
+		}
+	}
+	if len(logger.messages) != 1 || !strings.Contains(logger.messages[0], "as upsert") {
+		t.Errorf("Expected the upgrade to be logged once, got %q", logger.messages)
+	}
+}
+
+func TestCreateModeRelationshipsUpgradedUnderRetry(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(2, time.Millisecond))
+
+	rels := []graphs.Relationship{graphs.NewRelationship(graphs.NewNode("alice", "Person"), graphs.NewNode("bob", "Person"), "KNOWS")}
+	err := n4j.AddRelationships(context.Background(), rels, graphs.WithMergeMode(graphs.MergeModeCreate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(driver.queries[0].query, "MERGE (s)-[r:`KNOWS`]->(t)") {
+		t.Errorf("Expected an idempotent MERGE write, got %s", driver.queries[0].query)
+	}
+}
+
+func TestCreateModeKeptWithoutRetry(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes, graphs.WithMergeMode(graphs.MergeModeCreate)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(driver.queries[0].query, "CREATE (n:") {
+		t.Errorf("Expected a CREATE write, got %s", driver.queries[0].query)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(2, time.Millisecond))
+	driver.respond = failingResponder(5)
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes); err == nil {
+		t.Fatal("Expected an error after exhausting retries")
+	}
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", len(driver.queries))
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(3, time.Millisecond))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, errors.New("syntax error")
+	}
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected permanent errors not to be retried, got %d attempts", len(driver.queries))
+	}
+}
+
+func TestRetryPredicateRetriesCustomErrors(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	var attempts []int
+	n4j, driver := newFakeNeo4j(WithRetryPredicate(func(err error, attempt int) bool {
+		attempts = append(attempts, attempt)
+		return errors.Is(err, errQuota) && attempt < 3
+	}))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, errQuota
+	}
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	err := n4j.AddNodes(context.Background(), nodes, graphs.WithMergeMode(graphs.MergeModeCreate))
+	if !errors.Is(err, errQuota) {
+		t.Fatalf("Expected the custom error after giving up, got %v", err)
+	}
+
+	if len(driver.queries) != 3 {
+		t.Errorf("Expected the predicate to give up after 3 attempts, got %d", len(driver.queries))
+	}
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[2] != 3 {
+		t.Errorf("Expected the predicate to see attempts 1 to 3, got %v", attempts)
+	}
+	if !strings.Contains(driver.queries[0].query, "MERGE (n:") {
+		t.Errorf("Expected create mode to be upgraded while retrying, got %s", driver.queries[0].query)
+	}
+}
+
+func TestRetryPredicateRejectsOtherErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(5, time.Millisecond), WithRetryPredicate(func(err error, attempt int) bool {
+		return err.Error() == "retry me"
+	}))
+	// Transient errors are no longer retried once a predicate replaces the classifier
+	driver.respond = failingResponder(1)
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected a single attempt, got %d", len(driver.queries))
+	}
+}
+
+func TestRetryPredicateWithoutRetryIsCapped(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetryPredicate(func(err error, attempt int) bool {
+		return true
+	}))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, errors.New("persistent")
+	}
+
+	start := time.Now()
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(driver.queries) != defaultPredicateRetryAttempts {
+		t.Errorf("Expected the default attempt limit of %d, got %d attempts", defaultPredicateRetryAttempts, len(driver.queries))
+	}
+	// Delays double from the default base delay between the attempts
+	if elapsed, minimum := time.Since(start), 15*defaultPredicateRetryBaseDelay; elapsed < minimum {
+		t.Errorf("Expected at least %v of backoff, got %v", minimum, elapsed)
+	}
+}
+
+func TestRetryPredicateCappedByMaxAttempts(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(2, time.Millisecond), WithRetryPredicate(func(err error, attempt int) bool {
+		return true
+	}))
+	driver.respond = failingResponder(5)
+
+	nodes := []graphs.Node{graphs.NewNode("alice", "Person")}
+	if err := n4j.AddNodes(context.Background(), nodes); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected the attempt limit to apply, got %d attempts", len(driver.queries))
+	}
+}
+
+func TestAddGraphDocumentRetriesFailingBatchOnce(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithBaseEntityLabel(false), WithRetry(3, time.Millisecond), WithRetryPredicate(func(err error, attempt int) bool {
+		return true
+	}))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, errors.New("boom")
+	}
+
+	doc := graphs.NewGraphDocument(schema.Document{PageContent: "Alice"})
+	doc.AddNode(graphs.NewNode("alice", "Person"))
+	if err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(driver.queries) != 3 {
+		t.Errorf("Expected the failing batch to run 3 times, got %d", len(driver.queries))
+	}
+}
+
+func TestAddGraphDocumentRetriesTransientErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithBaseEntityLabel(false), WithRetry(3, time.Millisecond))
+	driver.respond = failingResponder(1)
+
+	doc := graphs.NewGraphDocument(schema.Document{PageContent: "Alice"})
+	doc.AddNode(graphs.NewNode("alice", "Person"))
+	if err := n4j.AddGraphDocument(context.Background(), []graphs.GraphDocument{doc}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected the transient error to be retried once, got %d queries", len(driver.queries))
+	}
+}
+
+func TestQueryErrorKeepsDriverError(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = failingResponder(1)
+
+	_, err := n4j.Query(context.Background(), "MATCH (n) RETURN n", nil)
+	if !errors.Is(err, ErrQueryExecution) {
+		t.Fatalf("Expected ErrQueryExecution, got %v", err)
+	}
+	var neo4jErr *neo4j.Neo4jError
+	if !errors.As(err, &neo4jErr) || !isRetryableError(err) {
+		t.Errorf("Expected the transient driver error to stay in the chain, got %v", err)
+	}
+}
+
+func TestQueryRetriesTransientErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(3, time.Millisecond))
+	calls := 0
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		calls++
+		if calls <= 2 {
+			return nil, &neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader", Msg: "No write operations are allowed on this database"}
+		}
+		return []*neo4j.Record{newRecord("name", "Alice")}, nil
+	}
+
+	result, err := n4j.Query(context.Background(), "MATCH (n) RETURN n.name AS name", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 3 {
+		t.Errorf("Expected 3 attempts, got %d", len(driver.queries))
+	}
+	records, _ := result["records"].([]map[string]interface{})
+	if len(records) != 1 || records[0]["name"] != "Alice" {
+		t.Errorf("Expected the records of the successful attempt, got %v", records)
+	}
+}
+
+func TestQueryFailsImmediatelyOnPermanentErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(3, time.Millisecond))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError", Msg: "Invalid input"}
+	}
+
+	_, err := n4j.Query(context.Background(), "MATCH (n RETURN n", nil)
+	if !errors.Is(err, ErrQueryExecution) {
+		t.Fatalf("Expected ErrQueryExecution, got %v", err)
+	}
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected a single attempt, got %d", len(driver.queries))
+	}
+}
+
+func TestQueryWithoutRetry(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = failingResponder(1)
+
+	if _, err := n4j.Query(context.Background(), "MATCH (n) RETURN n", nil); err == nil {
+		t.Fatal("Expected an error without retries")
+	}
+	if len(driver.queries) != 1 {
+		t.Errorf("Expected a single attempt, got %d", len(driver.queries))
+	}
+}
+
+func TestWithTransactionLeavesRetriesToDriver(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithRetry(3, time.Millisecond))
+	driver.respond = failingResponder(2)
+
+	// The fake driver does not retry managed transactions, so a single run is expected
+	runs := 0
+	err := n4j.TransactionManager().WithTransaction(context.Background(), func(tx neo4j.ManagedTransaction) error {
+		runs++
+		_, err := tx.Run(context.Background(), "CREATE (n:Person)", nil)
+		return err
+	})
+	if err == nil {
+		t.Fatal("Expected the transient error to be returned")
+	}
+	if runs != 1 {
+		t.Errorf("Expected the transaction to run once, got %d", runs)
+	}
+	if driver.rollbacks != 1 || driver.commits != 0 {
+		t.Errorf("Expected 1 rollback and no commit, got %d and %d", driver.rollbacks, driver.commits)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}, true},
+		{&neo4j.Neo4jError{Code: "Neo.TransientError.General.DatabaseUnavailable"}, true},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"}, true},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase"}, true},
+		{&neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.Terminated"}, false},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}, false},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Schema.ConstraintValidationFailed"}, false},
+		{errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		wrapped := fmt.Errorf("failed to run: %w", tt.err)
+		if got := isRetryableError(wrapped); got != tt.want {
+			t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -2,10 +2,14 @@ package neo4j
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 )
 
+// JSONSchemaDraft is the JSON Schema dialect produced by ExportJSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
 // RefreshSchema refreshes the schema information from the Neo4j database
 func (n *Neo4j) RefreshSchema(ctx context.Context) error {
 	if n.driver == nil {
@@ -15,6 +19,124 @@ func (n *Neo4j) RefreshSchema(ctx context.Context) error {
 	n.schemaMux.Lock()
 	defer n.schemaMux.Unlock()
 
+	structuredSchema, err := n.fetchStructuredSchema(ctx, n.database)
+	if err != nil {
+		return err
+	}
+
+	n.structuredSchema = structuredSchema
+
+	// Format schema as string
+	n.schemaCache = n.formatSchema(structuredSchema)
+
+	return nil
+}
+
+// RefreshSchemaForDatabase refreshes the cached schema of a specific database.
+// The default database schema returned by GetSchema is left untouched.
+func (n *Neo4j) RefreshSchemaForDatabase(ctx context.Context, database string) error {
+	if n.driver == nil {
+		return ErrDriverNotInitialized
+	}
+
+	n.schemaMux.Lock()
+	defer n.schemaMux.Unlock()
+
+	structuredSchema, err := n.fetchStructuredSchema(ctx, database)
+	if err != nil {
+		return err
+	}
+
+	if n.databaseSchemas == nil {
+		n.databaseSchemas = make(map[string]string)
+	}
+	n.databaseSchemas[database] = n.formatSchema(structuredSchema)
+
+	return nil
+}
+
+// GetSchemaForDatabase returns the cached schema of a specific database.
+// An empty string is returned if RefreshSchemaForDatabase has not been called for it.
+func (n *Neo4j) GetSchemaForDatabase(database string) string {
+	n.schemaMux.RLock()
+	defer n.schemaMux.RUnlock()
+	return n.databaseSchemas[database]
+}
+
+// excludedLabels and excludedRels are internal labels and relationship types left out of the schema
+var (
+	excludedLabels = []string{"_Bloom_Perspective_", "_Bloom_Scene_", "__Entity__"}
+	excludedRels   = []string{"_Bloom_HAS_SCENE_"}
+)
+
+// fetchStructuredSchema queries the structured schema of the given database.
+// APOC is used when available. The first time APOC turns out to be missing, the
+// decision is cached and the schema is built from the built-in db.schema procedures.
+// The caller must hold schemaMux.
+func (n *Neo4j) fetchStructuredSchema(ctx context.Context, database string) (map[string]interface{}, error) {
+	var nodeProps, relProps map[string]interface{}
+	var relationships []map[string]interface{}
+	var err error
+
+	if n.apocChecked && !n.apocAvailable {
+		nodeProps, relProps, relationships, err = n.fetchBuiltinSchema(ctx, database)
+	} else {
+		nodeProps, relProps, relationships, err = n.fetchAPOCSchema(ctx, database)
+		if isAPOCError(err) {
+			n.apocChecked, n.apocAvailable = true, false
+			nodeProps, relProps, relationships, err = n.fetchBuiltinSchema(ctx, database)
+		} else if err == nil {
+			n.apocChecked, n.apocAvailable = true, true
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Build structured schema
+	structuredSchema := make(map[string]interface{})
+	structuredSchema["node_props"] = nodeProps
+	structuredSchema["rel_props"] = relProps
+	structuredSchema["relationships"] = relationships
+
+	// Get constraints & indexes metadata
+	metadata := make(map[string]interface{})
+
+	// Try to get constraints
+	constraintResult, err := n.queryDatabase(ctx, database, "SHOW CONSTRAINTS", nil)
+	if err == nil {
+		if records, ok := constraintResult["records"].([]map[string]interface{}); ok {
+			metadata["constraint"] = records
+		}
+	} else {
+		// Fallback: user might not have access to schema information
+		metadata["constraint"] = []map[string]interface{}{}
+	}
+
+	// Try to get indexes
+	indexQuery := "CALL apoc.schema.nodes() YIELD label, properties, type, size, valuesSelectivity " +
+		"WHERE type = 'RANGE' RETURN *, size * valuesSelectivity as distinctValues"
+	if !n.apocAvailable {
+		indexQuery = "SHOW INDEXES YIELD labelsOrTypes, properties, type WHERE type = 'RANGE' " +
+			"RETURN labelsOrTypes[0] AS label, properties, type"
+	}
+	indexResult, err := n.queryDatabase(ctx, database, indexQuery, nil)
+	if err == nil {
+		if records, ok := indexResult["records"].([]map[string]interface{}); ok {
+			metadata["index"] = records
+		}
+	} else {
+		// Fallback: APOC might not be available or user lacks permissions
+		metadata["index"] = []map[string]interface{}{}
+	}
+
+	structuredSchema["metadata"] = metadata
+
+	return structuredSchema, nil
+}
+
+// fetchAPOCSchema queries node properties, relationship properties and relationship patterns with apoc.meta.data
+func (n *Neo4j) fetchAPOCSchema(ctx context.Context, database string) (map[string]interface{}, map[string]interface{}, []map[string]interface{}, error) {
 	// Query node properties
 	nodePropsQuery := `
 		CALL apoc.meta.data()
@@ -46,37 +168,28 @@ func (n *Neo4j) RefreshSchema(ctx context.Context) error {
 		RETURN {start: label, type: property, end: toString(other_node)} AS output
 	`
 
-	excludedLabels := []string{"_Bloom_Perspective_", "_Bloom_Scene_", "__Entity__"}
-	excludedRels := []string{"_Bloom_HAS_SCENE_"}
-
 	// Execute queries
-	nodeResult, err := n.Query(ctx, nodePropsQuery, map[string]interface{}{
+	nodeResult, err := n.queryDatabase(ctx, database, nodePropsQuery, map[string]interface{}{
 		"EXCLUDED_LABELS": excludedLabels,
 	})
 	if err != nil {
-		if isAPOCError(err) {
-			return wrapAPOCError(err)
-		}
-		return fmt.Errorf("failed to query node properties: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to query node properties: %w", err)
 	}
 
-	relPropsResult, err := n.Query(ctx, relPropsQuery, map[string]interface{}{
+	relPropsResult, err := n.queryDatabase(ctx, database, relPropsQuery, map[string]interface{}{
 		"EXCLUDED_LABELS": excludedRels,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to query relationship properties: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to query relationship properties: %w", err)
 	}
 
-	relsResult, err := n.Query(ctx, relQuery, map[string]interface{}{
+	relsResult, err := n.queryDatabase(ctx, database, relQuery, map[string]interface{}{
 		"EXCLUDED_LABELS": excludedLabels,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to query relationships: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to query relationships: %w", err)
 	}
 
-	// Build structured schema
-	structuredSchema := make(map[string]interface{})
-
 	// Process node properties
 	nodeProps := make(map[string]interface{})
 	if records, ok := nodeResult["records"].([]map[string]interface{}); ok {
@@ -115,44 +228,129 @@ func (n *Neo4j) RefreshSchema(ctx context.Context) error {
 		}
 	}
 
-	structuredSchema["node_props"] = nodeProps
-	structuredSchema["rel_props"] = relProps
-	structuredSchema["relationships"] = relationships
+	return nodeProps, relProps, relationships, nil
+}
 
-	// Get constraints & indexes metadata
-	metadata := make(map[string]interface{})
+// fetchBuiltinSchema queries node properties, relationship properties and relationship patterns
+// with the built-in db.schema procedures, producing the same shapes as fetchAPOCSchema
+func (n *Neo4j) fetchBuiltinSchema(ctx context.Context, database string) (map[string]interface{}, map[string]interface{}, []map[string]interface{}, error) {
+	nodePropsQuery := `
+		CALL db.schema.nodeTypeProperties()
+		YIELD nodeLabels, propertyName, propertyTypes
+		WHERE propertyName IS NOT NULL
+		RETURN nodeLabels, propertyName, propertyTypes
+	`
+	relPropsQuery := `
+		CALL db.schema.relTypeProperties()
+		YIELD relType, propertyName, propertyTypes
+		WHERE propertyName IS NOT NULL
+		RETURN relType, propertyName, propertyTypes
+	`
+	relQuery := `
+		CALL db.schema.visualization()
+		YIELD nodes, relationships
+		UNWIND relationships AS rel
+		RETURN labels(startNode(rel))[0] AS start, type(rel) AS type, labels(endNode(rel))[0] AS end
+	`
 
-	// Try to get constraints
-	constraintResult, err := n.Query(ctx, "SHOW CONSTRAINTS", nil)
-	if err == nil {
-		if records, ok := constraintResult["records"].([]map[string]interface{}); ok {
-			metadata["constraint"] = records
+	nodeResult, err := n.queryDatabase(ctx, database, nodePropsQuery, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query node properties: %w", err)
+	}
+	relPropsResult, err := n.queryDatabase(ctx, database, relPropsQuery, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query relationship properties: %w", err)
+	}
+	relsResult, err := n.queryDatabase(ctx, database, relQuery, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query relationships: %w", err)
+	}
+
+	excluded := make(map[string]bool)
+	for _, label := range append(append([]string(nil), excludedLabels...), excludedRels...) {
+		excluded[label] = true
+	}
+
+	// Properties are listed per label combination, so a label may appear in several rows
+	nodeProps := make(map[string]interface{})
+	seen := make(map[[2]string]bool)
+	records, _ := nodeResult["records"].([]map[string]interface{})
+	for _, record := range records {
+		labels, _ := record["nodeLabels"].([]interface{})
+		name, _ := record["propertyName"].(string)
+		propType := builtinPropertyType(record["propertyTypes"])
+		for _, labelVal := range labels {
+			label, ok := labelVal.(string)
+			if !ok || excluded[label] || seen[[2]string{label, name}] {
+				continue
+			}
+			seen[[2]string{label, name}] = true
+			props, _ := nodeProps[label].([]interface{})
+			nodeProps[label] = append(props, map[string]interface{}{"property": name, "type": propType})
 		}
-	} else {
-		// Fallback: user might not have access to schema information
-		metadata["constraint"] = []map[string]interface{}{}
 	}
 
-	// Try to get indexes
-	indexQuery := "CALL apoc.schema.nodes() YIELD label, properties, type, size, valuesSelectivity " +
-		"WHERE type = 'RANGE' RETURN *, size * valuesSelectivity as distinctValues"
-	indexResult, err := n.Query(ctx, indexQuery, nil)
-	if err == nil {
-		if records, ok := indexResult["records"].([]map[string]interface{}); ok {
-			metadata["index"] = records
+	relProps := make(map[string]interface{})
+	records, _ = relPropsResult["records"].([]map[string]interface{})
+	for _, record := range records {
+		// Relationship types are reported as :`TYPE`
+		relTypeVal, _ := record["relType"].(string)
+		relType := strings.TrimSuffix(strings.TrimPrefix(relTypeVal, ":`"), "`")
+		name, _ := record["propertyName"].(string)
+		if excluded[relType] {
+			continue
 		}
-	} else {
-		// Fallback: APOC might not be available or user lacks permissions
-		metadata["index"] = []map[string]interface{}{}
+		props, _ := relProps[relType].([]interface{})
+		relProps[relType] = append(props, map[string]interface{}{"property": name, "type": builtinPropertyType(record["propertyTypes"])})
 	}
 
-	structuredSchema["metadata"] = metadata
-	n.structuredSchema = structuredSchema
+	var relationships []map[string]interface{}
+	records, _ = relsResult["records"].([]map[string]interface{})
+	for _, record := range records {
+		start, _ := record["start"].(string)
+		relType, _ := record["type"].(string)
+		end, _ := record["end"].(string)
+		if excluded[start] || excluded[end] || excluded[relType] {
+			continue
+		}
+		relationships = append(relationships, map[string]interface{}{"start": start, "type": relType, "end": end})
+	}
 
-	// Format schema as string
-	n.schemaCache = n.formatSchema(structuredSchema)
+	return nodeProps, relProps, relationships, nil
+}
 
-	return nil
+// builtinPropertyTypes maps db.schema property types to the type names reported by APOC
+var builtinPropertyTypes = map[string]string{
+	"String":        "STRING",
+	"Long":          "INTEGER",
+	"Integer":       "INTEGER",
+	"Double":        "FLOAT",
+	"Float":         "FLOAT",
+	"Boolean":       "BOOLEAN",
+	"Date":          "DATE",
+	"DateTime":      "DATE_TIME",
+	"LocalDateTime": "LOCAL_DATE_TIME",
+	"Time":          "TIME",
+	"LocalTime":     "LOCAL_TIME",
+	"Duration":      "DURATION",
+	"Point":         "POINT",
+}
+
+// builtinPropertyType converts the propertyTypes of a db.schema row to an APOC type name.
+// Arrays become LIST, and unknown types are upper-cased.
+func builtinPropertyType(types interface{}) string {
+	list, _ := types.([]interface{})
+	if len(list) == 0 {
+		return ""
+	}
+	propType, _ := list[0].(string)
+	if strings.HasSuffix(propType, "Array") {
+		return "LIST"
+	}
+	if mapped, ok := builtinPropertyTypes[propType]; ok {
+		return mapped
+	}
+	return strings.ToUpper(propType)
 }
 
 // GetSchema returns the current schema as a string representation
@@ -162,6 +360,155 @@ func (n *Neo4j) GetSchema() string {
 	return n.schemaCache
 }
 
+// ExportJSONSchema converts the node and relationship property types of the structured
+// schema into a JSON Schema document. Every node label and relationship type becomes an
+// object definition under $defs, keyed as "node:<label>" and "relationship:<type>".
+// The schema is refreshed first if it has not been loaded yet.
+func (n *Neo4j) ExportJSONSchema(ctx context.Context) ([]byte, error) {
+	structuredSchema := n.GetStructuredSchema()
+	if _, ok := structuredSchema["node_props"]; !ok {
+		if err := n.RefreshSchema(ctx); err != nil {
+			return nil, err
+		}
+		structuredSchema = n.GetStructuredSchema()
+	}
+
+	defs := make(map[string]interface{})
+	if nodeProps, ok := structuredSchema["node_props"].(map[string]interface{}); ok {
+		for label, props := range nodeProps {
+			defs["node:"+label] = jsonSchemaObject(label, props)
+		}
+	}
+	if relProps, ok := structuredSchema["rel_props"].(map[string]interface{}); ok {
+		for relType, props := range relProps {
+			defs["relationship:"+relType] = jsonSchemaObject(relType, props)
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema": JSONSchemaDraft,
+		"title":   "Neo4j graph schema",
+		"$defs":   defs,
+	}, "", "  ")
+}
+
+// PropertyDef describes a property of a node label or relationship type
+type PropertyDef struct {
+	Name string
+	Type string
+}
+
+// Pattern describes a relationship type connecting two node labels
+type Pattern struct {
+	Start string
+	Type  string
+	End   string
+}
+
+// NodeProperties returns the properties of every node label in the cached structured schema
+func (n *Neo4j) NodeProperties() map[string][]PropertyDef {
+	return schemaPropertyDefs(n.GetStructuredSchema()["node_props"])
+}
+
+// RelationshipProperties returns the properties of every relationship type in the cached structured schema
+func (n *Neo4j) RelationshipProperties() map[string][]PropertyDef {
+	return schemaPropertyDefs(n.GetStructuredSchema()["rel_props"])
+}
+
+// RelationshipPatterns returns the relationship patterns in the cached structured schema
+func (n *Neo4j) RelationshipPatterns() []Pattern {
+	relationships, _ := n.GetStructuredSchema()["relationships"].([]map[string]interface{})
+
+	var patterns []Pattern
+	for _, rel := range relationships {
+		start, hasStart := rel["start"].(string)
+		relType, hasType := rel["type"].(string)
+		end, hasEnd := rel["end"].(string)
+		if hasStart && hasType && hasEnd {
+			patterns = append(patterns, Pattern{Start: start, Type: relType, End: end})
+		}
+	}
+	return patterns
+}
+
+// schemaPropertyDefs parses a node_props or rel_props entry of the structured schema
+func schemaPropertyDefs(props interface{}) map[string][]PropertyDef {
+	result := make(map[string][]PropertyDef)
+	if propsByName, ok := props.(map[string]interface{}); ok {
+		for name, list := range propsByName {
+			result[name] = propertyDefs(list)
+		}
+	}
+	return result
+}
+
+// propertyDefs parses a list of property/type entries, skipping malformed ones
+func propertyDefs(props interface{}) []PropertyDef {
+	var propsList []map[string]interface{}
+	switch v := props.(type) {
+	case []interface{}:
+		for _, prop := range v {
+			if propMap, ok := prop.(map[string]interface{}); ok {
+				propsList = append(propsList, propMap)
+			}
+		}
+	case []map[string]interface{}:
+		propsList = v
+	}
+
+	var defs []PropertyDef
+	for _, propMap := range propsList {
+		name, hasName := propMap["property"].(string)
+		propType, hasType := propMap["type"].(string)
+		if hasName && hasType {
+			defs = append(defs, PropertyDef{Name: name, Type: propType})
+		}
+	}
+	return defs
+}
+
+// jsonSchemaObject builds an object schema from a list of property/type entries
+func jsonSchemaObject(title string, props interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, prop := range propertyDefs(props) {
+		properties[prop.Name] = jsonSchemaType(prop.Type)
+	}
+
+	return map[string]interface{}{
+		"title":      title,
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+// jsonSchemaType maps a Neo4j property type to a JSON Schema type
+func jsonSchemaType(propType string) map[string]interface{} {
+	switch propType {
+	case "STRING":
+		return map[string]interface{}{"type": "string"}
+	case "INTEGER":
+		return map[string]interface{}{"type": "integer"}
+	case "FLOAT":
+		return map[string]interface{}{"type": "number"}
+	case "BOOLEAN":
+		return map[string]interface{}{"type": "boolean"}
+	case "LIST":
+		return map[string]interface{}{"type": "array"}
+	case "DATE":
+		return map[string]interface{}{"type": "string", "format": "date"}
+	case "DATE_TIME", "LOCAL_DATE_TIME":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "TIME", "LOCAL_TIME":
+		return map[string]interface{}{"type": "string", "format": "time"}
+	case "DURATION":
+		return map[string]interface{}{"type": "string", "format": "duration"}
+	case "POINT":
+		return map[string]interface{}{"type": "object"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
 // formatSchema formats the structured schema into a human-readable string
 func (n *Neo4j) formatSchema(schema map[string]interface{}) string {
 	var parts []string
@@ -311,21 +658,29 @@ func (n *Neo4j) formatEnhancedProperty(propMap map[string]interface{}) string {
 	return fmt.Sprintf("`%s`: %s", name, propType)
 }
 
-// enhancedSchemaCypher generates Cypher queries for enhanced schema information
-func (n *Neo4j) enhancedSchemaCypher(labelOrType string, properties []interface{}, exhaustive bool, isRelationship bool) string {
-	var matchClause string
+// isExhaustiveSearch reports whether a label or type with the given number of
+// entities is small enough to be searched exhaustively for enhanced schema
+func (n *Neo4j) isExhaustiveSearch(count int) bool {
+	return count < n.exhaustiveSearchLimit
+}
+
+// enhancedSchemaCypher generates the Cypher query and parameters profiling the properties
+// of a label or relationship type for enhanced schema information. The query is empty
+// when none of the properties can be profiled, in which case the label or type is skipped.
+func (n *Neo4j) enhancedSchemaCypher(labelOrType string, properties []interface{}, exhaustive bool, isRelationship bool) (string, map[string]interface{}) {
+	builder := newCypherBuilder()
 	if isRelationship {
-		matchClause = fmt.Sprintf("MATCH ()-[n:`%s`]->()", labelOrType)
+		builder.Match(relationshipPattern("()", "n", labelOrType, "()"))
 	} else {
-		matchClause = fmt.Sprintf("MATCH (n:`%s`)", labelOrType)
+		builder.Match(nodePattern("n", labelOrType))
 	}
 
 	var withClauses []string
 	outputDict := make(map[string]string)
 
 	if !exhaustive {
-		// Just sample 5 random nodes
-		matchClause += " WITH n LIMIT 5"
+		// Just sample a few random entities
+		builder.With("n").Limit(n.schemaSampleLimit)
 	}
 
 	for _, prop := range properties {
@@ -374,16 +729,16 @@ func (n *Neo4j) enhancedSchemaCypher(labelOrType string, properties []interface{
 	}
 
 	if len(withClauses) == 0 {
-		return ""
+		return "", nil
 	}
 
-	withClause := "WITH " + strings.Join(withClauses, ",\n     ")
-
 	var returnParts []string
 	for prop, expr := range outputDict {
 		returnParts = append(returnParts, fmt.Sprintf("`%s`: %s", prop, expr))
 	}
-	returnClause := "RETURN {" + strings.Join(returnParts, ", ") + "} AS output"
 
-	return strings.Join([]string{matchClause, withClause, returnClause}, "\n")
+	return builder.
+		With(withClauses...).
+		Return("{" + strings.Join(returnParts, ", ") + "} AS output").
+		Build()
 }
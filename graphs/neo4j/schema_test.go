@@ -0,0 +1,312 @@
+package neo4j
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// schemaResponder answers the node property schema query with one label per database
+func schemaResponder(driver *fakeDriver, labels map[string]string) func(string, map[string]interface{}) ([]*neo4j.Record, error) {
+	return func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if !strings.Contains(query, `elementType = "node"`) || strings.Contains(query, `WHERE type = "RELATIONSHIP"`) {
+			return nil, nil
+		}
+		database := driver.queries[len(driver.queries)-1].database
+		output := map[string]interface{}{
+			"labels": labels[database],
+			"properties": []interface{}{
+				map[string]interface{}{"property": "name", "type": "STRING"},
+			},
+		}
+		return []*neo4j.Record{newRecord("output", output)}, nil
+	}
+}
+
+func TestRefreshSchemaForDatabase(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = schemaResponder(driver, map[string]string{
+		"movies": "Movie",
+		"people": "Person",
+	})
+
+	ctx := context.Background()
+	if err := n4j.RefreshSchemaForDatabase(ctx, "movies"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := n4j.RefreshSchemaForDatabase(ctx, "people"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	movies := n4j.GetSchemaForDatabase("movies")
+	people := n4j.GetSchemaForDatabase("people")
+
+	if !strings.Contains(movies, "Movie {name: STRING}") || strings.Contains(movies, "Person") {
+		t.Errorf("Unexpected movies schema: %q", movies)
+	}
+	if !strings.Contains(people, "Person {name: STRING}") || strings.Contains(people, "Movie") {
+		t.Errorf("Unexpected people schema: %q", people)
+	}
+
+	if n4j.GetSchema() != "" {
+		t.Errorf("Expected default schema to be untouched, got %q", n4j.GetSchema())
+	}
+	if n4j.GetSchemaForDatabase("unknown") != "" {
+		t.Error("Expected empty schema for a database that was never refreshed")
+	}
+
+	for _, q := range driver.queries {
+		if q.database != "movies" && q.database != "people" {
+			t.Errorf("Query ran against unexpected database %q", q.database)
+		}
+	}
+}
+
+func TestSchemaSampleSize(t *testing.T) {
+	properties := []interface{}{
+		map[string]interface{}{"property": "name", "type": "STRING"},
+	}
+
+	n4j, _ := newFakeNeo4j(WithSchemaSampleSize(25, 500))
+
+	query, params := n4j.enhancedSchemaCypher("Person", properties, false, false)
+	if !strings.Contains(query, "WITH n LIMIT 25") {
+		t.Errorf("Expected sample limit in query, got %q", query)
+	}
+
+	if len(params) != 0 {
+		t.Errorf("Expected no parameters, got %v", params)
+	}
+
+	query, _ = n4j.enhancedSchemaCypher("Person", properties, true, false)
+	if strings.Contains(query, "LIMIT") {
+		t.Errorf("Expected no sample limit for exhaustive search, got %q", query)
+	}
+
+	unprofiled := []interface{}{map[string]interface{}{"property": "name"}}
+	if query, _ = n4j.enhancedSchemaCypher("Person", unprofiled, true, false); query != "" {
+		t.Errorf("Expected the label to be skipped without profiled properties, got %q", query)
+	}
+
+	if !n4j.isExhaustiveSearch(499) || n4j.isExhaustiveSearch(500) {
+		t.Error("Expected exhaustive threshold of 500")
+	}
+}
+
+func TestSchemaSampleSizeDefaults(t *testing.T) {
+	properties := []interface{}{
+		map[string]interface{}{"property": "name", "type": "STRING"},
+	}
+
+	n4j, _ := newFakeNeo4j()
+
+	query, _ := n4j.enhancedSchemaCypher("Person", properties, false, false)
+	if !strings.Contains(query, "WITH n LIMIT 5") {
+		t.Errorf("Expected default sample limit in query, got %q", query)
+	}
+	if !n4j.isExhaustiveSearch(EXHAUSTIVE_SEARCH_LIMIT-1) || n4j.isExhaustiveSearch(EXHAUSTIVE_SEARCH_LIMIT) {
+		t.Error("Expected default exhaustive threshold")
+	}
+}
+
+func TestExportJSONSchema(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	n4j.structuredSchema = map[string]interface{}{
+		"node_props": map[string]interface{}{
+			"Person": []interface{}{
+				map[string]interface{}{"property": "name", "type": "STRING"},
+				map[string]interface{}{"property": "age", "type": "INTEGER"},
+				map[string]interface{}{"property": "born", "type": "DATE"},
+			},
+		},
+		"rel_props": map[string]interface{}{
+			"KNOWS": []interface{}{
+				map[string]interface{}{"property": "weight", "type": "FLOAT"},
+				map[string]interface{}{"property": "tags", "type": "LIST"},
+			},
+		},
+	}
+
+	data, err := n4j.ExportJSONSchema(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected the cached schema to be used, got %d queries", len(driver.queries))
+	}
+
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Neo4j graph schema",
+		"$defs": {
+			"node:Person": {
+				"title": "Person",
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"age": {"type": "integer"},
+					"born": {"type": "string", "format": "date"}
+				}
+			},
+			"relationship:KNOWS": {
+				"title": "KNOWS",
+				"type": "object",
+				"properties": {
+					"weight": {"type": "number"},
+					"tags": {"type": "array"}
+				}
+			}
+		}
+	}`
+
+	var got, want interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("Fixture is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected JSON Schema:\n%s", data)
+	}
+}
+
+func TestStructuredSchemaTypedAccessors(t *testing.T) {
+	n4j, _ := newFakeNeo4j()
+	n4j.structuredSchema = map[string]interface{}{
+		"node_props": map[string]interface{}{
+			"Person": []interface{}{
+				map[string]interface{}{"property": "name", "type": "STRING"},
+				map[string]interface{}{"property": "age", "type": "INTEGER"},
+				map[string]interface{}{"property": "broken"},
+			},
+		},
+		"rel_props": map[string]interface{}{
+			"KNOWS": []map[string]interface{}{
+				{"property": "since", "type": "DATE"},
+			},
+		},
+		"relationships": []map[string]interface{}{
+			{"start": "Person", "type": "KNOWS", "end": "Person"},
+			{"start": "Person", "type": "WORKS_AT", "end": "Company"},
+		},
+	}
+
+	expectedNodes := map[string][]PropertyDef{
+		"Person": {{Name: "name", Type: "STRING"}, {Name: "age", Type: "INTEGER"}},
+	}
+	if got := n4j.NodeProperties(); !reflect.DeepEqual(got, expectedNodes) {
+		t.Errorf("Expected node properties %v, got %v", expectedNodes, got)
+	}
+
+	expectedRels := map[string][]PropertyDef{
+		"KNOWS": {{Name: "since", Type: "DATE"}},
+	}
+	if got := n4j.RelationshipProperties(); !reflect.DeepEqual(got, expectedRels) {
+		t.Errorf("Expected relationship properties %v, got %v", expectedRels, got)
+	}
+
+	expectedPatterns := []Pattern{
+		{Start: "Person", Type: "KNOWS", End: "Person"},
+		{Start: "Person", Type: "WORKS_AT", End: "Company"},
+	}
+	if got := n4j.RelationshipPatterns(); !reflect.DeepEqual(got, expectedPatterns) {
+		t.Errorf("Expected patterns %v, got %v", expectedPatterns, got)
+	}
+}
+
+func TestStructuredSchemaTypedAccessorsEmpty(t *testing.T) {
+	n4j, _ := newFakeNeo4j()
+
+	if got := n4j.NodeProperties(); len(got) != 0 {
+		t.Errorf("Expected no node properties, got %v", got)
+	}
+	if got := n4j.RelationshipPatterns(); got != nil {
+		t.Errorf("Expected no patterns, got %v", got)
+	}
+}
+
+// builtinSchemaResponder rejects APOC procedures and answers the built-in db.schema procedures
+func builtinSchemaResponder(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+	switch {
+	case strings.Contains(query, "apoc."):
+		return nil, &neo4j.Neo4jError{Code: "Neo.ClientError.Procedure.ProcedureNotFound", Msg: "There is no procedure with the name `apoc.meta.data` registered"}
+	case strings.Contains(query, "db.schema.nodeTypeProperties"):
+		return []*neo4j.Record{
+			newRecord("nodeLabels", []interface{}{"Person", "__Entity__"}, "propertyName", "name", "propertyTypes", []interface{}{"String"}),
+			newRecord("nodeLabels", []interface{}{"Person"}, "propertyName", "age", "propertyTypes", []interface{}{"Long"}),
+			newRecord("nodeLabels", []interface{}{"Person"}, "propertyName", "name", "propertyTypes", []interface{}{"String"}),
+			newRecord("nodeLabels", []interface{}{"Company"}, "propertyName", "tags", "propertyTypes", []interface{}{"StringArray"}),
+		}, nil
+	case strings.Contains(query, "db.schema.relTypeProperties"):
+		return []*neo4j.Record{
+			newRecord("relType", ":`WORKS_AT`", "propertyName", "since", "propertyTypes", []interface{}{"Date"}),
+		}, nil
+	case strings.Contains(query, "db.schema.visualization"):
+		return []*neo4j.Record{
+			newRecord("start", "Person", "type", "WORKS_AT", "end", "Company"),
+			newRecord("start", "_Bloom_Scene_", "type", "_Bloom_HAS_SCENE_", "end", "Person"),
+		}, nil
+	}
+	return nil, nil
+}
+
+func TestRefreshSchemaWithoutAPOC(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = builtinSchemaResponder
+
+	if err := n4j.RefreshSchema(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	schema := n4j.GetSchema()
+	for _, want := range []string{
+		"Person {name: STRING, age: INTEGER}",
+		"Company {tags: LIST}",
+		"WORKS_AT {since: DATE}",
+		"(:Person)-[:WORKS_AT]->(:Company)",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("Expected %q in schema:\n%s", want, schema)
+		}
+	}
+	if strings.Contains(schema, "__Entity__") || strings.Contains(schema, "Bloom") {
+		t.Errorf("Expected internal labels to be excluded:\n%s", schema)
+	}
+
+	patterns := n4j.RelationshipPatterns()
+	if !reflect.DeepEqual(patterns, []Pattern{{Start: "Person", Type: "WORKS_AT", End: "Company"}}) {
+		t.Errorf("Unexpected patterns %v", patterns)
+	}
+}
+
+func TestRefreshSchemaCachesAPOCDetection(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = builtinSchemaResponder
+	ctx := context.Background()
+
+	if err := n4j.RefreshSchema(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first := len(driver.queries)
+	if err := n4j.RefreshSchema(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	apocQueries := 0
+	for _, q := range driver.queries {
+		if strings.Contains(q.query, "apoc.") {
+			apocQueries++
+		}
+	}
+	if apocQueries != 1 {
+		t.Errorf("Expected APOC to be probed once, got %d APOC queries", apocQueries)
+	}
+	if second := len(driver.queries) - first; second != first-1 {
+		t.Errorf("Expected the second refresh to skip the APOC probe, got %d queries after %d", second, first)
+	}
+}
@@ -0,0 +1,171 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// RunScript runs the Cypher statements read from r, such as the contents of a .cypher
+// file, sequentially sharing params. Statements are separated by semicolons outside of
+// string literals, quoted identifiers and comments. The records returned by the last
+// statement are returned.
+//
+// Consecutive data statements run in a single transaction that is rolled back if any of
+// them fails. Neo4j does not allow schema statements (creating or dropping an index or
+// constraint) in a transaction that also writes data, so each of them runs in its own
+// transaction. Transactions that committed before a failing statement are not rolled back.
+func (n *Neo4j) RunScript(ctx context.Context, r io.Reader, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+
+	script, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	statements := splitStatements(string(script))
+	if len(statements) == 0 {
+		return nil, nil
+	}
+
+	params = n.withDefaultParams(params)
+
+	var records []map[string]interface{}
+	for _, group := range groupSchemaStatements(statements) {
+		err = n.txManager.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
+			for i := group.start; i < group.end; i++ {
+				var err error
+				records, err = n.runStatement(ctx, tx, statements[i], params)
+				if err != nil {
+					return fmt.Errorf("%w: statement %d: %w", ErrQueryExecution, i+1, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply sanitization if enabled
+	if n.sanitize {
+		records = sanitizeRecords(records)
+	}
+	if n.sanitizeStrings {
+		records = cleanRecordStrings(records)
+	}
+
+	return records, nil
+}
+
+// runStatement runs a single script statement within the store timeout and returns its records
+func (n *Neo4j) runStatement(ctx context.Context, tx neo4j.ManagedTransaction, statement string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := n.withQueryTimeout(ctx)
+	defer cancel()
+
+	result, err := tx.Run(ctx, statement, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	for result.Next(ctx) {
+		records = append(records, result.Record().AsMap())
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// schemaStatement matches statements that create or drop an index or constraint
+var schemaStatement = regexp.MustCompile(`(?is)^(CREATE|DROP)\s+((RANGE|TEXT|POINT|LOOKUP|FULLTEXT|VECTOR|BTREE)\s+)?(INDEX|CONSTRAINT)\b`)
+
+// statementGroup is a range of statements run in one transaction
+type statementGroup struct {
+	start, end int
+}
+
+// groupSchemaStatements groups consecutive data statements together and puts every
+// schema statement in a group of its own.
+func groupSchemaStatements(statements []string) []statementGroup {
+	var groups []statementGroup
+	start := 0
+	for i, statement := range statements {
+		if !schemaStatement.MatchString(statement) {
+			continue
+		}
+		if start < i {
+			groups = append(groups, statementGroup{start: start, end: i})
+		}
+		groups = append(groups, statementGroup{start: i, end: i + 1})
+		start = i + 1
+	}
+	if start < len(statements) {
+		groups = append(groups, statementGroup{start: start, end: len(statements)})
+	}
+	return groups
+}
+
+// splitStatements splits a Cypher script into statements on semicolons, ignoring
+// semicolons inside string literals, quoted identifiers and comments. Comments are
+// removed and empty statements are skipped.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy the quoted text, honoring backslash escapes in string literals
+			end := i + 1
+			for end < len(script) && script[end] != c {
+				if script[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end >= len(script) {
+				end = len(script) - 1
+			}
+			current.WriteString(script[i : end+1])
+			i = end
+		case c == '/' && i+1 < len(script) && script[i+1] == '/':
+			// Skip to the end of the line, keeping the newline
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end - 1
+			}
+		case c == '/' && i+1 < len(script) && script[i+1] == '*':
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
@@ -0,0 +1,210 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "simple",
+			script: "CREATE (a:Person);\nCREATE (b:Person);\n",
+			want:   []string{"CREATE (a:Person)", "CREATE (b:Person)"},
+		},
+		{
+			name:   "missing final semicolon",
+			script: "MATCH (n) RETURN n",
+			want:   []string{"MATCH (n) RETURN n"},
+		},
+		{
+			name:   "semicolons in literals",
+			script: `CREATE (n {bio: 'a; b', quote: "c; \"d;\""}); MATCH (n:` + "`weird;label`" + `) RETURN n;`,
+			want: []string{
+				`CREATE (n {bio: 'a; b', quote: "c; \"d;\""})`,
+				"MATCH (n:`weird;label`) RETURN n",
+			},
+		},
+		{
+			name:   "escaped quote",
+			script: `CREATE (n {name: 'O\'Brien; Jr'});`,
+			want:   []string{`CREATE (n {name: 'O\'Brien; Jr'})`},
+		},
+		{
+			name: "comments",
+			script: `// setup; creates people
+CREATE (a:Person); // trailing; comment
+/* block; comment
+   spanning lines; */
+CREATE (b:Person {url: 'http://example.com'});
+// only a comment;`,
+			want: []string{"CREATE (a:Person)", "CREATE (b:Person {url: 'http://example.com'})"},
+		},
+		{
+			name:   "empty statements",
+			script: " ;; \n ; ",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.HasPrefix(query, "MATCH") {
+			return []*neo4j.Record{newRecord("count", int64(2))}, nil
+		}
+		return []*neo4j.Record{newRecord("ignored", true)}, nil
+	}
+
+	script := `CREATE (:Person {name: $first});
+CREATE (:Person {name: $second});
+MATCH (p:Person) RETURN count(p) AS count;`
+	params := map[string]interface{}{"first": "Alice", "second": "Bob"}
+
+	records, err := n4j.RunScript(context.Background(), strings.NewReader(script), params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(driver.queries))
+	}
+	for _, q := range driver.queries {
+		if !q.inTx {
+			t.Errorf("Expected statement to run in a transaction: %s", q.query)
+		}
+		if q.params["first"] != "Alice" || q.params["second"] != "Bob" {
+			t.Errorf("Expected shared params, got %v", q.params)
+		}
+	}
+	if driver.commits != 1 {
+		t.Errorf("Expected a single commit, got %d", driver.commits)
+	}
+	if len(records) != 1 || records[0]["count"] != int64(2) {
+		t.Errorf("Expected the records of the last statement, got %v", records)
+	}
+}
+
+func TestRunScriptRollsBackOnFailure(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		if strings.Contains(query, "broken") {
+			return nil, errors.New("syntax error")
+		}
+		return nil, nil
+	}
+
+	_, err := n4j.RunScript(context.Background(), strings.NewReader("CREATE (:Person); broken; CREATE (:Company);"), nil)
+	if !errors.Is(err, ErrQueryExecution) || !strings.Contains(err.Error(), "statement 2") {
+		t.Fatalf("Expected the failing statement to be reported, got %v", err)
+	}
+	if len(driver.queries) != 2 {
+		t.Errorf("Expected execution to stop at the failing statement, got %d queries", len(driver.queries))
+	}
+	if driver.rollbacks != 1 || driver.commits != 0 {
+		t.Errorf("Expected a rollback, got %d commits and %d rollbacks", driver.commits, driver.rollbacks)
+	}
+}
+
+func TestRunScriptWrapsDriverErrors(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError", Msg: "Invalid input"}
+	}
+
+	_, err := n4j.RunScript(context.Background(), strings.NewReader("broken;"), nil)
+	var neo4jErr *neo4j.Neo4jError
+	if !errors.Is(err, ErrQueryExecution) || !errors.As(err, &neo4jErr) {
+		t.Fatalf("Expected ErrQueryExecution wrapping the driver error, got %v", err)
+	}
+	if neo4jErr.Code != "Neo.ClientError.Statement.SyntaxError" {
+		t.Errorf("Expected the syntax error code, got %s", neo4jErr.Code)
+	}
+
+	driver.respond = nil
+	driver.pullErr = func(query string) error {
+		return &neo4j.Neo4jError{Code: "Neo.TransientError.General.MemoryPoolOutOfMemoryError", Msg: "out of memory"}
+	}
+	_, err = n4j.RunScript(context.Background(), strings.NewReader("MATCH (n) RETURN n;"), nil)
+	if !errors.As(err, &neo4jErr) || !strings.Contains(err.Error(), "statement 1") {
+		t.Errorf("Expected the pull error of statement 1, got %v", err)
+	}
+}
+
+func TestRunScriptAppliesTimeoutPerStatement(t *testing.T) {
+	timeout := 40 * time.Millisecond
+	n4j, driver := newFakeNeo4j(WithTimeout(timeout))
+	var started []time.Time
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		started = append(started, time.Now())
+		time.Sleep(timeout / 2)
+		return nil, nil
+	}
+
+	if _, err := n4j.RunScript(context.Background(), strings.NewReader("CREATE (:A); CREATE (:B); CREATE (:C);"), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(driver.queries) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(driver.queries))
+	}
+	for i, q := range driver.queries {
+		assertDeadline(t, q, started[i], timeout-5*time.Millisecond)
+	}
+}
+
+func TestRunScriptRunsSchemaStatementsSeparately(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	script := `CREATE CONSTRAINT person_id IF NOT EXISTS FOR (p:Person) REQUIRE p.id IS UNIQUE;
+create range index person_name for (p:Person) on (p.name);
+CREATE (:Person {id: 1});
+CREATE (:Person {id: 2});
+DROP INDEX person_name;
+MATCH (p:Person) RETURN p;`
+
+	if _, err := n4j.RunScript(context.Background(), strings.NewReader(script), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(driver.queries) != 6 {
+		t.Fatalf("Expected 6 statements, got %d", len(driver.queries))
+	}
+	// Each schema statement commits on its own and the data statements between them share one
+	if driver.commits != 5 {
+		t.Errorf("Expected 5 transactions, got %d", driver.commits)
+	}
+}
+
+func TestGroupSchemaStatements(t *testing.T) {
+	statements := []string{
+		"CREATE (:Person)",
+		"CREATE INDEX FOR (p:Person) ON (p.name)",
+		"CREATE (:Company)",
+		"MATCH (n) RETURN n",
+		"DROP CONSTRAINT c IF EXISTS",
+		"CREATE CONSTRAINT c FOR (p:Person) REQUIRE p.id IS UNIQUE",
+	}
+	want := []statementGroup{{0, 1}, {1, 2}, {2, 4}, {4, 5}, {5, 6}}
+	if got := groupSchemaStatements(statements); !reflect.DeepEqual(got, want) {
+		t.Errorf("groupSchemaStatements() = %v, want %v", got, want)
+	}
+}
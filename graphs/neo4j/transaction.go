@@ -3,7 +3,6 @@ package neo4j
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -22,25 +21,60 @@ func newTransactionManager(n *Neo4j) *TransactionManager {
 
 // ExplicitTransaction represents an explicit transaction
 type ExplicitTransaction struct {
-	tx      neo4j.ExplicitTransaction
-	session neo4j.SessionWithContext
-	ctx     context.Context
-	cancel  context.CancelFunc
+	tx              neo4j.ExplicitTransaction
+	session         neo4j.SessionWithContext
+	ctx             context.Context
+	cancel          context.CancelFunc
+	sanitize        bool
+	sanitizeStrings bool
+	queryCache      *queryCache
+
+	// Write counters of consumed results, and results of Run not yet consumed
+	stats   WriteResult
+	pending []neo4j.ResultWithContext
 }
 
-// WithTransaction executes a function within a transaction context
+// WriteResult summarizes the changes made by queries
+type WriteResult struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+}
+
+// add accumulates the counters of a result summary
+func (w *WriteResult) add(summary neo4j.ResultSummary) {
+	if summary == nil {
+		return
+	}
+	counters := summary.Counters()
+	w.NodesCreated += counters.NodesCreated()
+	w.NodesDeleted += counters.NodesDeleted()
+	w.RelationshipsCreated += counters.RelationshipsCreated()
+	w.RelationshipsDeleted += counters.RelationshipsDeleted()
+	w.PropertiesSet += counters.PropertiesSet()
+	w.LabelsAdded += counters.LabelsAdded()
+	w.LabelsRemoved += counters.LabelsRemoved()
+}
+
+// WithTransaction executes a function within a transaction context. The driver runs
+// the transaction again from the start on transient errors, for up to the time set by
+// WithMaxTransactionRetryTime, so fn may run more than once. WithRetry does not apply.
 func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(tx neo4j.ManagedTransaction) error) error {
 	if tm.neo4j.driver == nil {
 		return ErrDriverNotInitialized
 	}
 
 	// Create session
-	session := tm.neo4j.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: tm.neo4j.database,
-	})
+	session := tm.neo4j.driver.NewSession(ctx, tm.neo4j.getSessionConfig())
 	defer session.Close(ctx)
 
-	// Execute within transaction
+	defer tm.neo4j.invalidateQueryCache()
+
+	// Execute within transaction, retried by the driver
 	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
 		return nil, fn(tx)
 	})
@@ -68,9 +102,7 @@ func (tm *TransactionManager) BeginTransaction(ctx context.Context) (*ExplicitTr
 	txCtx, cancel := context.WithCancel(ctx)
 
 	// Create session
-	session := tm.neo4j.driver.NewSession(txCtx, neo4j.SessionConfig{
-		DatabaseName: tm.neo4j.database,
-	})
+	session := tm.neo4j.driver.NewSession(txCtx, tm.neo4j.getSessionConfig())
 
 	// Begin transaction
 	tx, err := session.BeginTransaction(txCtx)
@@ -81,16 +113,23 @@ func (tm *TransactionManager) BeginTransaction(ctx context.Context) (*ExplicitTr
 	}
 
 	return &ExplicitTransaction{
-		tx:      tx,
-		session: session,
-		ctx:     txCtx,
-		cancel:  cancel,
+		tx:              tx,
+		session:         session,
+		ctx:             txCtx,
+		cancel:          cancel,
+		sanitize:        tm.neo4j.sanitize,
+		sanitizeStrings: tm.neo4j.sanitizeStrings,
+		queryCache:      tm.neo4j.queryCache,
 	}, nil
 }
 
 // Commit commits the explicit transaction
 func (et *ExplicitTransaction) Commit() error {
 	defer et.cleanup()
+	defer et.queryCache.invalidate()
+	if err := et.consumePending(); err != nil {
+		return err
+	}
 	return et.tx.Commit(et.ctx)
 }
 
@@ -108,7 +147,70 @@ func (et *ExplicitTransaction) Close() error {
 
 // Run executes a query within the explicit transaction
 func (et *ExplicitTransaction) Run(query string, params map[string]interface{}) (neo4j.ResultWithContext, error) {
-	return et.tx.Run(et.ctx, query, params)
+	result, err := et.tx.Run(et.ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	et.pending = append(et.pending, result)
+	return result, nil
+}
+
+// Stats returns the write counters accumulated over every query run in the transaction.
+// Results returned by Run are consumed, so their remaining records are discarded; read
+// them before calling Stats. Commit consumes them as well, so Stats stays available after it.
+func (et *ExplicitTransaction) Stats() (WriteResult, error) {
+	if err := et.consumePending(); err != nil {
+		return et.stats, err
+	}
+	return et.stats, nil
+}
+
+// consumePending consumes the results returned by Run and accumulates their counters
+func (et *ExplicitTransaction) consumePending() error {
+	for len(et.pending) > 0 {
+		result := et.pending[0]
+		et.pending = et.pending[1:]
+
+		summary, err := result.Consume(et.ctx)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrQueryExecution, err)
+		}
+		et.stats.add(summary)
+	}
+	return nil
+}
+
+// RunCollect executes a query within the explicit transaction and collects the records as maps.
+// Records are sanitized when sanitization is enabled on the store, as with Query.
+func (et *ExplicitTransaction) RunCollect(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	result, err := et.tx.Run(et.ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryExecution, err)
+	}
+
+	records := make([]map[string]interface{}, 0)
+	for result.Next(et.ctx) {
+		records = append(records, result.Record().AsMap())
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryExecution, err)
+	}
+
+	summary, err := result.Consume(et.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryExecution, err)
+	}
+	et.stats.add(summary)
+
+	if et.sanitize {
+		records = sanitizeRecords(records)
+	}
+	if et.sanitizeStrings {
+		records = cleanRecordStrings(records)
+	}
+
+	return records, nil
 }
 
 // cleanup handles context cancellation and resource cleanup
@@ -127,6 +229,10 @@ func (tm *TransactionManager) AddGraphDocumentWithTransaction(ctx context.Contex
 	for _, opt := range options {
 		opt(opts)
 	}
+	ctx, cancel := tm.neo4j.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	docs = tm.neo4j.documentsWithoutSelfLoops(docs, opts)
 
 	// Use explicit transaction for better control
 	return tm.WithTransaction(ctx, func(tx neo4j.ManagedTransaction) error {
@@ -187,6 +293,7 @@ func (tm *TransactionManager) processBatchInTransaction(ctx context.Context, tx
 	}
 
 	// Then import relationships
+	nodeTypes := batchNodeTypes(docs)
 	for _, doc := range docs {
 		select {
 		case <-ctx.Done():
@@ -194,7 +301,7 @@ func (tm *TransactionManager) processBatchInTransaction(ctx context.Context, tx
 		default:
 		}
 
-		if err := tm.importRelationshipsInTransaction(ctx, tx, doc, opts); err != nil {
+		if err := tm.importRelationshipsInTransaction(ctx, tx, doc, nodeTypes, opts); err != nil {
 			return err
 		}
 	}
@@ -212,14 +319,7 @@ func (tm *TransactionManager) importNodesInTransaction(ctx context.Context, tx n
 	query := tm.neo4j.getNodeImportQuery(opts.IncludeSource)
 
 	// Prepare node data
-	var nodeData []map[string]interface{}
-	for _, node := range doc.Nodes {
-		nodeData = append(nodeData, map[string]interface{}{
-			"id":         node.ID,
-			"type":       cleanString(node.Type),
-			"properties": node.Properties,
-		})
-	}
+	nodeData := tm.neo4j.nodeImportData(doc.Nodes)
 
 	// Prepare parameters
 	params := map[string]interface{}{
@@ -233,7 +333,7 @@ func (tm *TransactionManager) importNodesInTransaction(ctx context.Context, tx n
 	}
 
 	// Execute query within transaction
-	_, err := tx.Run(ctx, query, params)
+	_, err := tx.Run(ctx, query, tm.neo4j.withDefaultParams(params))
 	if err != nil && isAPOCError(err) {
 		return wrapAPOCError(err)
 	}
@@ -241,37 +341,43 @@ func (tm *TransactionManager) importNodesInTransaction(ctx context.Context, tx n
 }
 
 // importRelationshipsInTransaction imports relationships within a transaction
-func (tm *TransactionManager) importRelationshipsInTransaction(ctx context.Context, tx neo4j.ManagedTransaction, doc graphs.GraphDocument, opts *graphs.Options) error {
-	if len(doc.Relationships) == 0 {
+func (tm *TransactionManager) importRelationshipsInTransaction(ctx context.Context, tx neo4j.ManagedTransaction, doc graphs.GraphDocument, nodeTypes map[string]string, opts *graphs.Options) error {
+	relationships := doc.Relationships
+	if len(relationships) == 0 {
 		return nil
 	}
 
 	// Generate query using the appropriate method
-	query := tm.neo4j.getRelImportQuery()
+	query := tm.neo4j.getRelImportQuery(opts.AssumeEndpointsExist)
 
 	// Prepare relationship data
-	var relData []map[string]interface{}
-	for _, rel := range doc.Relationships {
-		relData = append(relData, map[string]interface{}{
-			"source":       rel.Source.ID,
-			"source_label": cleanString(rel.Source.Type),
-			"target":       rel.Target.ID,
-			"target_label": cleanString(rel.Target.Type),
-			"type":         cleanString(strings.ReplaceAll(strings.ToUpper(rel.Type), " ", "_")),
-			"properties":   rel.Properties,
-		})
-	}
+	relData := tm.neo4j.relationshipImportData(relationships, nodeTypes)
 
 	params := map[string]interface{}{
 		"relationships": relData,
 	}
 
 	// Execute query within transaction
-	_, err := tx.Run(ctx, query, params)
-	if err != nil && isAPOCError(err) {
-		return wrapAPOCError(err)
+	result, err := tx.Run(ctx, query, tm.neo4j.withDefaultParams(params))
+	if err != nil {
+		if isAPOCError(err) {
+			return wrapAPOCError(err)
+		}
+		return err
 	}
-	return err
+
+	if opts.AssumeEndpointsExist {
+		records, err := result.Collect(ctx)
+		if err != nil {
+			return err
+		}
+		var rows []map[string]interface{}
+		for _, record := range records {
+			rows = append(rows, record.AsMap())
+		}
+		return checkImportedRelationships(rows, len(relData))
+	}
+	return nil
 }
 
 // ensureBaseEntityConstraintTx creates the base entity constraint within a transaction
@@ -307,6 +413,7 @@ func (tm *TransactionManager) PeriodicCommitQuery(ctx context.Context, query str
 	if tm.neo4j.driver == nil {
 		return ErrDriverNotInitialized
 	}
+	defer tm.neo4j.invalidateQueryCache()
 
 	// Default batch size for periodic commits
 	if batchSize <= 0 {
@@ -314,9 +421,7 @@ func (tm *TransactionManager) PeriodicCommitQuery(ctx context.Context, query str
 	}
 
 	// Create session
-	session := tm.neo4j.driver.NewSession(ctx, neo4j.SessionConfig{
-		DatabaseName: tm.neo4j.database,
-	})
+	session := tm.neo4j.driver.NewSession(ctx, tm.neo4j.getSessionConfig())
 	defer session.Close(ctx)
 
 	// Use USING PERIODIC COMMIT for large data operations
@@ -335,14 +440,109 @@ func (tm *TransactionManager) PeriodicCommitQuery(ctx context.Context, query str
 	}
 
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrQueryExecution, err)
+		return fmt.Errorf("%w: %w", ErrQueryExecution, err)
 	}
 
 	// Consume the result to ensure the query completes
 	_, err = result.Consume(ctx)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrQueryExecution, err)
+		return fmt.Errorf("%w: %w", ErrQueryExecution, err)
 	}
 
 	return nil
 }
+
+// ImportStats reports the outcome of a batched update
+type ImportStats struct {
+	// Batches is the number of batches executed
+	Batches int64
+	// Total is the number of rows returned by the match query
+	Total int64
+	// Committed is the number of rows whose update was committed
+	Committed int64
+	// Failed is the number of rows whose update failed
+	Failed int64
+	// FailedBatches is the number of batches that were rolled back
+	FailedBatches int64
+	// TimeTaken is the time the server spent executing all batches
+	TimeTaken time.Duration
+	// ErrorMessages maps each distinct error message to the number of times it occurred
+	ErrorMessages map[string]int64
+}
+
+// IterateUpdate runs updateQuery for every row returned by matchQuery in batches using
+// apoc.periodic.iterate, keeping memory bounded for large deletes and updates. Rows
+// from matchQuery are available to updateQuery under their returned names.
+func (n *Neo4j) IterateUpdate(ctx context.Context, matchQuery, updateQuery string, batchSize int) (ImportStats, error) {
+	if n.driver == nil {
+		return ImportStats{}, ErrDriverNotInitialized
+	}
+
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	query := "CALL apoc.periodic.iterate($matchQuery, $updateQuery, {batchSize: $batchSize, parallel: false}) " +
+		"YIELD batches, total, timeTaken, committedOperations, failedOperations, failedBatches, errorMessages " +
+		"RETURN batches, total, timeTaken, committedOperations, failedOperations, failedBatches, errorMessages"
+	params := map[string]interface{}{
+		"matchQuery":  matchQuery,
+		"updateQuery": updateQuery,
+		"batchSize":   batchSize,
+	}
+
+	result, err := n.Query(ctx, query, params)
+	if err != nil {
+		if isAPOCError(err) {
+			return ImportStats{}, fmt.Errorf("%w\n\nWithout APOC, run the update as "+
+				"CALL { ... } IN TRANSACTIONS OF %d ROWS instead", wrapAPOCError(err), batchSize)
+		}
+		return ImportStats{}, err
+	}
+
+	records, _ := result["records"].([]map[string]interface{})
+	if len(records) == 0 {
+		return ImportStats{}, fmt.Errorf("%w: apoc.periodic.iterate returned no statistics", ErrQueryExecution)
+	}
+
+	stats := parseIterateStats(records[0])
+	if stats.Failed > 0 || stats.FailedBatches > 0 {
+		return stats, fmt.Errorf("%w: %d operations in %d batches failed: %v",
+			ErrQueryExecution, stats.Failed, stats.FailedBatches, stats.ErrorMessages)
+	}
+
+	return stats, nil
+}
+
+// parseIterateStats converts an apoc.periodic.iterate result record into ImportStats
+func parseIterateStats(record map[string]interface{}) ImportStats {
+	toInt64 := func(value interface{}) int64 {
+		switch v := value.(type) {
+		case int64:
+			return v
+		case int:
+			return int64(v)
+		case float64:
+			return int64(v)
+		}
+		return 0
+	}
+
+	stats := ImportStats{
+		Batches:       toInt64(record["batches"]),
+		Total:         toInt64(record["total"]),
+		Committed:     toInt64(record["committedOperations"]),
+		Failed:        toInt64(record["failedOperations"]),
+		FailedBatches: toInt64(record["failedBatches"]),
+		TimeTaken:     time.Duration(toInt64(record["timeTaken"])) * time.Second,
+		ErrorMessages: make(map[string]int64),
+	}
+
+	if messages, ok := record["errorMessages"].(map[string]interface{}); ok {
+		for message, count := range messages {
+			stats.ErrorMessages[message] = toInt64(count)
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,194 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestExplicitTransactionRunCollect(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithSanitize(true))
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{
+			newRecord("name", "Alice", "embedding", make([]interface{}, 200)),
+			newRecord("name", "Bob", "embedding", []interface{}{1.0, 2.0}),
+		}, nil
+	}
+
+	tx, err := n4j.TransactionManager().BeginTransaction(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records, err := tx.RunCollect("MATCH (p:Person) RETURN p.name AS name, p.embedding AS embedding", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0]["name"] != "Alice" || records[1]["name"] != "Bob" {
+		t.Errorf("Unexpected records: %v", records)
+	}
+	if _, exists := records[0]["embedding"]; exists {
+		t.Error("Expected oversized list to be removed by sanitization")
+	}
+	if _, exists := records[1]["embedding"]; !exists {
+		t.Error("Expected small list to be kept")
+	}
+	if !driver.queries[0].inTx {
+		t.Error("Expected query to run inside the transaction")
+	}
+}
+
+func TestExplicitTransactionRunCollectWithoutSanitize(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord("embedding", make([]interface{}, 200))}, nil
+	}
+
+	tx, err := n4j.TransactionManager().BeginTransaction(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer tx.Rollback()
+
+	records, err := tx.RunCollect("RETURN $value AS embedding", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, exists := records[0]["embedding"]; !exists {
+		t.Error("Expected list to be kept when sanitization is disabled")
+	}
+}
+
+func TestIterateUpdate(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord(
+			"batches", int64(3),
+			"total", int64(2500),
+			"timeTaken", int64(4),
+			"committedOperations", int64(2500),
+			"failedOperations", int64(0),
+			"failedBatches", int64(0),
+			"errorMessages", map[string]interface{}{},
+		)}, nil
+	}
+
+	stats, err := n4j.IterateUpdate(context.Background(),
+		"MATCH (n:Stale) RETURN n", "DETACH DELETE n", 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := driver.queries[0]
+	if !strings.Contains(q.query, "CALL apoc.periodic.iterate($matchQuery, $updateQuery, {batchSize: $batchSize, parallel: false})") {
+		t.Errorf("Unexpected query: %s", q.query)
+	}
+	if q.params["matchQuery"] != "MATCH (n:Stale) RETURN n" || q.params["updateQuery"] != "DETACH DELETE n" || q.params["batchSize"] != 1000 {
+		t.Errorf("Unexpected params: %v", q.params)
+	}
+
+	if stats.Batches != 3 || stats.Total != 2500 || stats.Committed != 2500 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.TimeTaken != 4*time.Second {
+		t.Errorf("Expected 4s time taken, got %v", stats.TimeTaken)
+	}
+}
+
+func TestIterateUpdateFailures(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return []*neo4j.Record{newRecord(
+			"batches", int64(2),
+			"total", int64(20),
+			"committedOperations", int64(10),
+			"failedOperations", int64(10),
+			"failedBatches", int64(1),
+			"errorMessages", map[string]interface{}{"constraint violation": int64(10)},
+		)}, nil
+	}
+
+	stats, err := n4j.IterateUpdate(context.Background(), "MATCH (n) RETURN n", "SET n.x = 1", 10)
+	if !errors.Is(err, ErrQueryExecution) {
+		t.Fatalf("Expected ErrQueryExecution, got %v", err)
+	}
+	if stats.Failed != 10 || stats.ErrorMessages["constraint violation"] != 10 {
+		t.Errorf("Expected failure stats to be returned, got %+v", stats)
+	}
+}
+
+func TestIterateUpdateWithoutAPOC(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.respond = func(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return nil, &TestError{"Neo.ClientError.Procedure.ProcedureNotFound: There is no procedure with the name `apoc.periodic.iterate`"}
+	}
+
+	_, err := n4j.IterateUpdate(context.Background(), "MATCH (n) RETURN n", "DELETE n", 500)
+	if !errors.Is(err, ErrAPOCNotAvailable) {
+		t.Fatalf("Expected ErrAPOCNotAvailable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "IN TRANSACTIONS OF 500 ROWS") {
+		t.Errorf("Expected CALL IN TRANSACTIONS suggestion, got %v", err)
+	}
+}
+
+func TestExplicitTransactionStats(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	driver.counters = func(query string) fakeCounters {
+		switch {
+		case strings.HasPrefix(query, "CREATE (n"):
+			return fakeCounters{nodesCreated: 1, propertiesSet: 2}
+		case strings.Contains(query, "CREATE (a)-[:KNOWS]->(b)"):
+			return fakeCounters{relationshipsCreated: 1}
+		default:
+			return fakeCounters{}
+		}
+	}
+
+	tx, err := n4j.TransactionManager().BeginTransaction(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := tx.Run("CREATE (n:Person {id: 'alice', name: 'Alice'})", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tx.RunCollect("CREATE (n:Person {id: 'bob', name: 'Bob'}) RETURN n", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tx.Run("MATCH (a {id: 'alice'}), (b {id: 'bob'}) CREATE (a)-[:KNOWS]->(b)", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stats, err := tx.Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := WriteResult{NodesCreated: 2, RelationshipsCreated: 1, PropertiesSet: 4, LabelsAdded: 2}
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+
+	if _, err := tx.Run("CREATE (n:Person {id: 'carol', name: 'Carol'})", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stats, _ = tx.Stats()
+	if stats.NodesCreated != 3 || stats.PropertiesSet != 6 {
+		t.Errorf("Expected counters of results run before commit to be included, got %+v", stats)
+	}
+}
@@ -3,9 +3,16 @@ package neo4j
 import (
 	"crypto/md5"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/tmc/langchaingo/schema"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
 )
 
 // Helper functions
@@ -15,6 +22,12 @@ func cleanString(text string) string {
 	return strings.ReplaceAll(text, "`", "")
 }
 
+// normalizeRelType converts a relationship type to the form imports store it in:
+// upper case, with spaces replaced by underscores and backticks removed
+func normalizeRelType(relType string) string {
+	return cleanString(strings.ReplaceAll(strings.ToUpper(relType), " ", "_"))
+}
+
 // generateDocumentID generates an ID for a document
 func generateDocumentID(doc schema.Document) string {
 	if id, exists := doc.Metadata["id"]; exists {
@@ -31,16 +44,29 @@ func md5Hash(data []byte) [16]byte {
 	return md5.Sum(data)
 }
 
-// isAPOCError checks if an error is due to missing APOC procedures
+// isAPOCError checks if an error is due to missing or disallowed APOC procedures
 func isAPOCError(err error) bool {
 	if err == nil {
 		return false
 	}
 	errorStr := err.Error()
-	return strings.Contains(errorStr, "Neo.ClientError.Procedure.ProcedureNotFound") ||
+	return isAPOCForbiddenError(err) ||
+		strings.Contains(errorStr, "Neo.ClientError.Procedure.ProcedureNotFound") ||
 		strings.Contains(errorStr, "apoc.meta.data") ||
 		strings.Contains(errorStr, "apoc.merge.node") ||
-		strings.Contains(errorStr, "apoc.merge.relationship")
+		strings.Contains(errorStr, "apoc.merge.relationship") ||
+		strings.Contains(errorStr, "apoc.periodic.iterate")
+}
+
+// isAPOCForbiddenError checks if an error is due to APOC procedures that are installed
+// but not allowed by the server security configuration
+func isAPOCForbiddenError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errorStr := err.Error()
+	return strings.Contains(errorStr, "Neo.ClientError.Security.Forbidden") &&
+		strings.Contains(strings.ToLower(errorStr), "apoc")
 }
 
 // wrapAPOCError wraps APOC-related errors with helpful guidance
@@ -49,6 +75,15 @@ func wrapAPOCError(err error) error {
 		return err
 	}
 
+	if isAPOCForbiddenError(err) {
+		return fmt.Errorf("%w: %v\n\nAPOC procedures are installed but not allowed. Please ensure:\n"+
+			"1. 'dbms.security.procedures.allowlist' includes the APOC procedures, e.g. apoc.*\n"+
+			"2. 'dbms.security.procedures.unrestricted' includes them if they need unrestricted access\n"+
+			"3. The current user has EXECUTE privileges on the procedures\n"+
+			"Restart Neo4j after changing the configuration",
+			ErrAPOCNotAllowed, err)
+	}
+
 	return fmt.Errorf("%w: %v\n\nAPOC procedures are not available. Please ensure:\n"+
 		"1. APOC plugin is installed in your Neo4j instance\n"+
 		"2. APOC procedures are allowed in Neo4j configuration\n"+
@@ -57,6 +92,34 @@ func wrapAPOCError(err error) error {
 		ErrAPOCNotAvailable, err)
 }
 
+// filterProperties returns the properties selected by the IncludeProperties and
+// ExcludeProperties of opts. A non-empty include list is an allowlist and takes
+// precedence, so the exclude list only applies when no include list is set.
+// properties is returned unchanged when opts is nil or selects everything.
+func filterProperties(properties map[string]interface{}, opts *graphs.Options) map[string]interface{} {
+	if opts == nil || properties == nil || (len(opts.IncludeProperties) == 0 && len(opts.ExcludeProperties) == 0) {
+		return properties
+	}
+
+	filtered := make(map[string]interface{}, len(properties))
+	if len(opts.IncludeProperties) > 0 {
+		for _, key := range opts.IncludeProperties {
+			if value, ok := properties[key]; ok {
+				filtered[key] = value
+			}
+		}
+		return filtered
+	}
+
+	for key, value := range properties {
+		filtered[key] = value
+	}
+	for _, key := range opts.ExcludeProperties {
+		delete(filtered, key)
+	}
+	return filtered
+}
+
 // valueSanitize sanitizes input by removing embedding-like values and oversized lists.
 // This prevents context pollution and improves LLM performance by filtering out
 // irrelevant large data structures. Based on the Python implementation.
@@ -90,6 +153,155 @@ func valueSanitize(d interface{}) interface{} {
 	}
 }
 
+// projectedLabelsKey holds the labels of a node projected by nodeReturn
+const projectedLabelsKey = "__labels"
+
+// largePropertyKeys returns the node properties the structured schema reports as lists of at
+// least LIST_LIMIT elements on every sampled node, which valueSanitize would always drop.
+// Size statistics are only present once the schema was refreshed with enhanced schema enabled.
+func (n *Neo4j) largePropertyKeys() []string {
+	nodeProps, _ := n.GetStructuredSchema()["node_props"].(map[string]interface{})
+
+	seen := make(map[string]bool)
+	var keys []string
+	for _, props := range nodeProps {
+		propsList, _ := props.([]interface{})
+		for _, prop := range propsList {
+			propMap, ok := prop.(map[string]interface{})
+			if !ok || propMap["type"] != "LIST" {
+				continue
+			}
+			name, _ := propMap["property"].(string)
+			if name == "" || seen[name] {
+				continue
+			}
+			var minSize int64
+			switch v := propMap["min_size"].(type) {
+			case int:
+				minSize = int64(v)
+			case int64:
+				minSize = v
+			default:
+				continue
+			}
+			if minSize >= LIST_LIMIT {
+				seen[name] = true
+				keys = append(keys, name)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// nodeReturn returns the expression returning the node bound to variable. With server-side
+// sanitization enabled, large properties are nulled in a map projection carrying the labels
+// under projectedLabelsKey, which asNode turns back into a node.
+func (n *Neo4j) nodeReturn(variable string) string {
+	if !n.serverSideSanitize {
+		return variable
+	}
+	keys := n.largePropertyKeys()
+	if len(keys) == 0 {
+		return variable
+	}
+
+	entries := []string{".*"}
+	for _, key := range keys {
+		entries = append(entries, fmt.Sprintf("`%s`: null", strings.ReplaceAll(key, "`", "``")))
+	}
+	entries = append(entries, fmt.Sprintf("`%s`: labels(%s)", projectedLabelsKey, variable))
+	return fmt.Sprintf("%s {%s} AS %s", variable, strings.Join(entries, ", "), variable)
+}
+
+// asNode converts a value returned by nodeReturn to a node
+func asNode(value interface{}) (neo4j.Node, bool) {
+	switch v := value.(type) {
+	case neo4j.Node:
+		return v, true
+	case map[string]interface{}:
+		labelValues, ok := v[projectedLabelsKey].([]interface{})
+		if !ok {
+			return neo4j.Node{}, false
+		}
+		node := neo4j.Node{Props: make(map[string]interface{}, len(v))}
+		for _, label := range labelValues {
+			if s, ok := label.(string); ok {
+				node.Labels = append(node.Labels, s)
+			}
+		}
+		// Stored properties are never null, so null entries are the excluded ones
+		for key, prop := range v {
+			if key != projectedLabelsKey && prop != nil {
+				node.Props[key] = prop
+			}
+		}
+		return node, true
+	default:
+		return neo4j.Node{}, false
+	}
+}
+
+// sanitizeRecords applies valueSanitize to every record
+func sanitizeRecords(records []map[string]interface{}) []map[string]interface{} {
+	sanitizedRecords := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if sanitized := valueSanitize(record); sanitized != nil {
+			if sanitizedMap, ok := sanitized.(map[string]interface{}); ok {
+				sanitizedRecords = append(sanitizedRecords, sanitizedMap)
+			}
+		}
+	}
+	return sanitizedRecords
+}
+
+// cleanRecordStrings applies cleanControlCharacters to every string value in the records
+func cleanRecordStrings(records []map[string]interface{}) []map[string]interface{} {
+	cleaned := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if cleanedMap, ok := cleanValueStrings(record).(map[string]interface{}); ok {
+			cleaned = append(cleaned, cleanedMap)
+		}
+	}
+	return cleaned
+}
+
+// cleanValueStrings recursively cleans string values inside maps and lists
+func cleanValueStrings(d interface{}) interface{} {
+	switch v := d.(type) {
+	case string:
+		return cleanControlCharacters(v)
+	case map[string]interface{}:
+		newDict := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			newDict[key] = cleanValueStrings(value)
+		}
+		return newDict
+	case []interface{}:
+		newList := make([]interface{}, len(v))
+		for i, item := range v {
+			newList[i] = cleanValueStrings(item)
+		}
+		return newList
+	default:
+		return v
+	}
+}
+
+// cleanControlCharacters replaces line breaks and tabs with spaces and drops other control characters
+func cleanControlCharacters(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		default:
+			return r
+		}
+	}, text)
+}
+
 // cleanStringValues cleans string values for schema display
 func cleanStringValues(text string) string {
 	// Replace newlines and carriage returns with spaces
@@ -97,3 +309,138 @@ func cleanStringValues(text string) string {
 	cleaned = strings.ReplaceAll(cleaned, "\r", " ")
 	return cleaned
 }
+
+var (
+	// predicateNodeRef matches a reference to the node variable n
+	predicateNodeRef = regexp.MustCompile(`\bn\b`)
+	// predicateForbidden matches clauses that could turn a filter into a write or a subquery
+	predicateForbidden = regexp.MustCompile(`(?i)\b(CALL|CREATE|MERGE|DELETE|DETACH|SET|REMOVE|DROP|LOAD|FOREACH|UNION|MATCH|WITH|RETURN|USE)\b`)
+	// predicateStringOperator matches the STARTS WITH and ENDS WITH operators, which are
+	// not WITH clauses
+	predicateStringOperator = regexp.MustCompile(`(?i)\b(STARTS|ENDS)\s+WITH\b`)
+	// predicateParam matches a query parameter such as $name
+	predicateParam = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+	// identifierPattern matches labels and relationship types that are safe to interpolate into Cypher
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+var (
+	// cypherLiteral matches string literals, quoted identifiers and comments
+	cypherLiteral = regexp.MustCompile(`'(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `|//[^\n]*|/\*(?s:.*?)\*/`)
+	// cypherWriteClause matches clauses that modify the graph or the database
+	cypherWriteClause = regexp.MustCompile(`(?i)\b(CREATE|MERGE|DELETE|DETACH|SET|REMOVE|DROP|FOREACH|LOAD\s+CSV|IN\s+TRANSACTIONS|GRANT|DENY|REVOKE|ALTER|START\s+DATABASE|STOP\s+DATABASE)\b`)
+	// cypherWriteProcedure matches procedures known to write to the graph
+	cypherWriteProcedure = regexp.MustCompile(`(?i)\bCALL\s+(apoc\.(create|merge|refactor|periodic|do|nodes\.(delete|link|collapse)|trigger|load|import|export|atomic|lock|schema\.assert|cypher\.(run(Write|Many|Schema|File|Files)|doIt))|db\.(create|index\.fulltext\.create|clearQueryCaches)|dbms\.)`)
+)
+
+// checkReadOnlyQuery rejects queries containing clauses or procedures that write.
+// Literals, quoted identifiers and comments are ignored so they cannot trigger false matches.
+func checkReadOnlyQuery(query string) error {
+	stripped := cypherLiteral.ReplaceAllString(query, " ")
+	if clause := cypherWriteClause.FindString(stripped); clause != "" {
+		return fmt.Errorf("%w: query contains %s", ErrWriteQueryRejected, strings.ToUpper(strings.Join(strings.Fields(clause), " ")))
+	}
+	if procedure := cypherWriteProcedure.FindString(stripped); procedure != "" {
+		return fmt.Errorf("%w: query calls %s", ErrWriteQueryRejected, strings.Join(strings.Fields(procedure)[1:], " "))
+	}
+	return nil
+}
+
+// validateRelType checks that a relationship type can be safely used in a query
+func validateRelType(relType string) error {
+	if !identifierPattern.MatchString(relType) {
+		return fmt.Errorf("%w: %q", ErrInvalidRelType, relType)
+	}
+	return nil
+}
+
+// validateLabel checks that a node label can be safely used in a query
+func validateLabel(label string) error {
+	if !identifierPattern.MatchString(label) {
+		return fmt.Errorf("%w: %q", ErrInvalidLabel, label)
+	}
+	return nil
+}
+
+// validatePredicate checks that a WHERE fragment only filters on the node n.
+// Literal string values are rejected so that callers pass values through params.
+func validatePredicate(predicate string, params map[string]interface{}) error {
+	trimmed := strings.TrimSpace(predicate)
+	if trimmed == "" {
+		return fmt.Errorf("%w: predicate is empty", ErrInvalidPredicate)
+	}
+	if strings.Contains(trimmed, ";") {
+		return fmt.Errorf("%w: predicate contains a semicolon", ErrInvalidPredicate)
+	}
+	if strings.Contains(trimmed, "//") || strings.Contains(trimmed, "/*") {
+		return fmt.Errorf("%w: predicate contains a comment", ErrInvalidPredicate)
+	}
+	if keyword := predicateForbidden.FindString(predicateStringOperator.ReplaceAllString(trimmed, " ")); keyword != "" {
+		return fmt.Errorf("%w: predicate contains forbidden keyword %s", ErrInvalidPredicate, strings.ToUpper(keyword))
+	}
+	if strings.ContainsAny(trimmed, `'"`) {
+		return fmt.Errorf("%w: values must be supplied as parameters", ErrInvalidPredicate)
+	}
+	if !predicateNodeRef.MatchString(trimmed) {
+		return fmt.Errorf("%w: predicate must reference n", ErrInvalidPredicate)
+	}
+	for _, match := range predicateParam.FindAllStringSubmatch(trimmed, -1) {
+		if _, ok := params[match[1]]; !ok {
+			return fmt.Errorf("%w: missing parameter $%s", ErrInvalidPredicate, match[1])
+		}
+	}
+	return nil
+}
+
+// withDefaultParams returns params merged over the configured default parameters.
+// The caller's map is never modified and caller values win on conflict.
+func (n *Neo4j) withDefaultParams(params map[string]interface{}) map[string]interface{} {
+	if len(n.defaultParams) == 0 {
+		return params
+	}
+
+	merged := make(map[string]interface{}, len(n.defaultParams)+len(params))
+	for k, v := range n.defaultParams {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return merged
+}
+
+// normalizeProperties returns properties with time.Time values converted to the configured timezone.
+// The input map is never modified.
+func (n *Neo4j) normalizeProperties(properties map[string]interface{}) map[string]interface{} {
+	if n.timezone == nil || properties == nil {
+		return properties
+	}
+
+	normalized := make(map[string]interface{}, len(properties))
+	for k, v := range properties {
+		normalized[k] = normalizeTemporal(v, n.timezone)
+	}
+	return normalized
+}
+
+// normalizeTemporal converts time.Time values, directly or inside lists, to loc
+func normalizeTemporal(value interface{}, loc *time.Location) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.In(loc)
+	case []time.Time:
+		converted := make([]time.Time, len(v))
+		for i, t := range v {
+			converted[i] = t.In(loc)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = normalizeTemporal(item, loc)
+		}
+		return converted
+	default:
+		return value
+	}
+}
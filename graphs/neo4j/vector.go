@@ -0,0 +1,134 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+// VectorScoreProperty is the property holding the similarity score of nodes returned by VectorSearch
+const VectorScoreProperty = "score"
+
+// vectorSimilarityFunctions lists the similarity functions supported by vector indexes
+var vectorSimilarityFunctions = map[string]bool{
+	"cosine":    true,
+	"euclidean": true,
+}
+
+// vectorIndexName returns the name of the vector index over a label and property
+func vectorIndexName(label, property string) string {
+	return fmt.Sprintf("%s_%s_vector", label, property)
+}
+
+// CreateVectorIndex creates a vector index over the embeddings stored in property on
+// nodes with label, unless it already exists. similarity is "cosine" or "euclidean".
+// The index is named after the label and property so VectorSearch can find it.
+func (n *Neo4j) CreateVectorIndex(ctx context.Context, label, property string, dimensions int, similarity string) error {
+	if err := validateLabel(label); err != nil {
+		return err
+	}
+	if !identifierPattern.MatchString(property) {
+		return fmt.Errorf("%w: invalid property %q", ErrInvalidVectorIndex, property)
+	}
+	if dimensions <= 0 {
+		return fmt.Errorf("%w: dimensions must be positive, got %d", ErrInvalidVectorIndex, dimensions)
+	}
+	if !vectorSimilarityFunctions[similarity] {
+		return fmt.Errorf("%w: unsupported similarity function %q", ErrInvalidVectorIndex, similarity)
+	}
+
+	query := fmt.Sprintf(
+		"CREATE VECTOR INDEX `%s` IF NOT EXISTS FOR (n:`%s`) ON (n.`%s`) "+
+			"OPTIONS {indexConfig: {`vector.dimensions`: %d, `vector.similarity_function`: '%s'}}",
+		vectorIndexName(label, property), label, property, dimensions, similarity,
+	)
+	if _, err := n.Query(ctx, query, nil); err != nil {
+		return fmt.Errorf("failed to create vector index: %w", err)
+	}
+	return nil
+}
+
+// VectorSearch returns the topK nodes with label whose embedding in property is most
+// similar to queryVector, using the index created by CreateVectorIndex. Nodes are
+// ordered by decreasing similarity, which is set on each node as VectorScoreProperty.
+// When the store sanitizes values, the embedding property is still kept on the results;
+// use graphs.WithSkipSanitize to keep every other property as well.
+func (n *Neo4j) VectorSearch(ctx context.Context, label, property string, queryVector []float32, topK int, options ...graphs.Option) ([]graphs.Node, error) {
+	if n.driver == nil {
+		return nil, ErrDriverNotInitialized
+	}
+	if err := validateLabel(label); err != nil {
+		return nil, err
+	}
+	if !identifierPattern.MatchString(property) {
+		return nil, fmt.Errorf("%w: invalid property %q", ErrInvalidVectorIndex, property)
+	}
+
+	opts := graphs.NewOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+	ctx, cancel := n.withOperationTimeout(ctx, opts)
+	defer cancel()
+
+	session := n.driver.NewSession(ctx, n.getSessionConfig())
+	defer session.Close(ctx)
+
+	query := "CALL db.index.vector.queryNodes($indexName, $topK, $queryVector) YIELD node, score RETURN node, score ORDER BY score DESC"
+	params := map[string]interface{}{
+		"indexName":   vectorIndexName(label, property),
+		"topK":        topK,
+		"queryVector": queryVector,
+	}
+
+	result, err := session.Run(ctx, query, n.withDefaultParams(params))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vector index: %w", err)
+	}
+
+	var nodes []graphs.Node
+	for result.Next(ctx) {
+		record := result.Record()
+		nodeVal, _ := record.Get("node")
+		node, ok := nodeVal.(neo4j.Node)
+		if !ok {
+			continue
+		}
+		score, _ := record.Get("score")
+
+		graphNode := n.convertNeo4jNodeToGraphNode(node, opts)
+		properties := make(map[string]interface{}, len(graphNode.Properties)+1)
+		if n.sanitize && !opts.SkipSanitize {
+			properties = sanitizeKeeping(graphNode.Properties, property)
+		} else {
+			for key, value := range graphNode.Properties {
+				properties[key] = value
+			}
+		}
+		properties[VectorScoreProperty] = score
+		graphNode.Properties = properties
+
+		nodes = append(nodes, *graphNode)
+	}
+
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search vector index: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// sanitizeKeeping applies valueSanitize to properties, leaving the keep property untouched
+func sanitizeKeeping(properties map[string]interface{}, keep string) map[string]interface{} {
+	sanitized, _ := valueSanitize(properties).(map[string]interface{})
+	if sanitized == nil {
+		sanitized = make(map[string]interface{})
+	}
+	if value, ok := properties[keep]; ok {
+		sanitized[keep] = value
+	}
+	return sanitized
+}
@@ -0,0 +1,106 @@
+package neo4j
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/0xDezzy/langchaingo-graphs/graphs"
+)
+
+func TestCreateVectorIndex(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+
+	if err := n4j.CreateVectorIndex(context.Background(), "Chunk", "embedding", 1536, "cosine"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "CREATE VECTOR INDEX `Chunk_embedding_vector` IF NOT EXISTS FOR (n:`Chunk`) ON (n.`embedding`) " +
+		"OPTIONS {indexConfig: {`vector.dimensions`: 1536, `vector.similarity_function`: 'cosine'}}"
+	if driver.queries[0].query != expected {
+		t.Errorf("Expected query %q, got %q", expected, driver.queries[0].query)
+	}
+}
+
+func TestCreateVectorIndexRejectsInvalidInput(t *testing.T) {
+	n4j, driver := newFakeNeo4j()
+	ctx := context.Background()
+
+	if err := n4j.CreateVectorIndex(ctx, "Chunk`) DETACH DELETE n //", "embedding", 3, "cosine"); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Expected ErrInvalidLabel, got %v", err)
+	}
+	if err := n4j.CreateVectorIndex(ctx, "Chunk", "embedding", 0, "cosine"); !errors.Is(err, ErrInvalidVectorIndex) {
+		t.Errorf("Expected ErrInvalidVectorIndex for zero dimensions, got %v", err)
+	}
+	if err := n4j.CreateVectorIndex(ctx, "Chunk", "embedding", 3, "manhattan"); !errors.Is(err, ErrInvalidVectorIndex) {
+		t.Errorf("Expected ErrInvalidVectorIndex for an unknown similarity, got %v", err)
+	}
+	if len(driver.queries) != 0 {
+		t.Errorf("Expected invalid indexes not to reach the database, got %d queries", len(driver.queries))
+	}
+}
+
+// vectorSearchResponder returns two chunks with 200-dimensional embeddings
+func vectorSearchResponder(query string, params map[string]interface{}) ([]*neo4j.Record, error) {
+	embedding := make([]interface{}, 200)
+	chunk := func(id string) neo4j.Node {
+		return neo4j.Node{Labels: []string{"Chunk"}, Props: map[string]interface{}{
+			"id":        id,
+			"embedding": embedding,
+			"other":     embedding,
+		}}
+	}
+	return []*neo4j.Record{
+		newRecord("node", chunk("c1"), "score", 0.92),
+		newRecord("node", chunk("c2"), "score", 0.81),
+	}, nil
+}
+
+func TestVectorSearch(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithSanitize(true))
+	driver.respond = vectorSearchResponder
+
+	vector := []float32{0.1, 0.2, 0.3}
+	nodes, err := n4j.VectorSearch(context.Background(), "Chunk", "embedding", vector, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := driver.queries[0]
+	if !strings.Contains(q.query, "CALL db.index.vector.queryNodes($indexName, $topK, $queryVector)") {
+		t.Errorf("Expected a vector index query, got %s", q.query)
+	}
+	if q.params["indexName"] != "Chunk_embedding_vector" || q.params["topK"] != 2 {
+		t.Errorf("Unexpected params %v", q.params)
+	}
+
+	if len(nodes) != 2 || nodes[0].ID != "c1" || nodes[1].ID != "c2" {
+		t.Fatalf("Expected c1 and c2 in score order, got %+v", nodes)
+	}
+	if nodes[0].Properties[VectorScoreProperty] != 0.92 {
+		t.Errorf("Expected the score as a property, got %v", nodes[0].Properties)
+	}
+	if _, ok := nodes[0].Properties["embedding"]; !ok {
+		t.Error("Expected the searched embedding to survive sanitization")
+	}
+	if _, ok := nodes[0].Properties["other"]; ok {
+		t.Error("Expected other oversized lists to be sanitized")
+	}
+}
+
+func TestVectorSearchSkipSanitize(t *testing.T) {
+	n4j, driver := newFakeNeo4j(WithSanitize(true))
+	driver.respond = vectorSearchResponder
+
+	nodes, err := n4j.VectorSearch(context.Background(), "Chunk", "embedding", []float32{0.1}, 1,
+		graphs.WithSkipSanitize(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := nodes[0].Properties["other"]; !ok {
+		t.Error("Expected every property to be kept when sanitization is skipped")
+	}
+}